@@ -5,6 +5,8 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -18,5 +20,14 @@ func main() {
 	pflag.CommandLine = flags
 
 	root := cmd.NewPGOCommand(os.Stdin, os.Stdout, os.Stderr)
-	cobra.CheckErr(root.Execute())
+	err := root.Execute()
+
+	// Backups that fail verification exit with a status that differs from
+	// other errors.
+	var verification cmd.BackupVerificationError
+	if errors.As(err, &verification) {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+	cobra.CheckErr(err)
 }
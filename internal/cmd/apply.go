@@ -0,0 +1,43 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+)
+
+// applyCluster sends intent as a server-side apply patch of the PostgresCluster
+// called name. When force is true, fields owned by other clients are taken.
+// A conflict prints a suggestion to use the --force-conflicts flag.
+func applyCluster(ctx context.Context, config *internal.Config,
+	client dynamic.ResourceInterface, name string, intent *unstructured.Unstructured, force bool,
+) (*unstructured.Unstructured, error) {
+	patch, err := intent.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	patchOptions := metav1.PatchOptions{}
+	if force {
+		b := true
+		patchOptions.Force = &b
+	}
+
+	cluster, err := client.Patch(ctx, name, types.ApplyPatchType, patch,
+		config.Patch.PatchOptions(patchOptions))
+	if apierrors.IsConflict(err) {
+		_, _ = fmt.Fprintf(config.Out, "SUGGESTION: The --force-conflicts flag may help in performing this operation.\n")
+	}
+	return cluster, err
+}
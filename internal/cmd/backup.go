@@ -65,6 +65,15 @@ postgresclusters/hippo backup initiated`)
 	cmdBackup.Flags().StringArrayVar(&backup.Options, "options", []string{},
 		"options for taking a backup; can be used multiple times")
 
+	cmdBackup.AddCommand(
+		newBackupListCommand(config),
+		newBackupExpireCommand(config),
+		newBackupRetentionCommand(config),
+		newBackupScheduleCommand(config),
+		newBackupTestRestoreCommand(config),
+		newBackupVerifyCommand(config),
+	)
+
 	// Define the 'backup' command
 	cmdBackup.RunE = func(cmd *cobra.Command, args []string) error {
 
@@ -0,0 +1,649 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+func newBackupListCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list CLUSTER_NAME",
+		Short: "List the backup sets of a PostgresCluster",
+		Long: `List the pgBackRest backup sets of a PostgresCluster with their type, repository,
+start and stop times, size, and range of WAL. Times are in UTC.
+
+With --pitr-target, also report whether each repository has what is needed for
+a point-in-time recovery to that time and which backup set it would start from.
+
+### RBAC Requirements
+    Resources  Verbs
+    ---------  -----
+    pods       [list]
+    pods/exec  [create]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# List the backup sets of the 'hippo' postgrescluster
+pgo backup list hippo
+
+# Check whether 'hippo' can be recovered to 2 PM on March 9th
+pgo backup list hippo --repo repo1 --pitr-target "2025-03-09 14:00:00+00"
+
+### Example output
+LABEL                               TYPE   REPO    START                  STOP                   SIZE     REPO SIZE   WAL START                  WAL STOP
+20250302-010002F                    full   repo1   2025-03-02T01:00:02Z   2025-03-02T01:04:10Z   1.2GiB   310.4MiB    000000010000000000000010   000000010000000000000012
+20250302-010002F_20250309-010003I   incr   repo1   2025-03-09T01:00:03Z   2025-03-09T01:00:41Z   1.2GiB   12.6MiB     00000001000000000000002A   00000001000000000000002A
+repo1: PITR to 2025-03-09T14:00:00Z is possible from backup 20250302-010002F_20250309-010003I when WAL is archived through the target; latest archived WAL is 000000010000000000000031`)
+
+	list := pgBackRestBackups{Config: config}
+
+	cmd.Flags().StringVar(&list.RepoName, "repo", "", "only list the backup sets of this repository, such as 1 or repo1")
+	cmd.Flags().StringVar(&list.PITRTarget, "pitr-target", "",
+		`report whether a point-in-time recovery to this time is possible, such as "2025-03-09 14:00:00+00"`)
+
+	var outputEnum = util.TextOutput
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"output format. types supported: text,json")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		list.ClusterName = args[0]
+		list.JSON = outputEnum == util.JSONOutput
+		return list.List(context.Background(), time.Now())
+	}
+
+	return cmd
+}
+
+func newBackupExpireCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expire CLUSTER_NAME --repo REPO_NAME",
+		Short: "Remove backup sets from a repository",
+		Long: `Remove backup sets from a repository of a PostgresCluster using "pgbackrest expire".
+Pass --set to remove one backup set, or --older-than to remove the full backup
+sets that finished before then. The newest full backup set is never selected by
+--older-than.
+
+Removing a backup set also removes the differential and incremental backup
+sets that depend on it. Every backup set that would be removed is listed before
+you are asked to confirm.
+
+### RBAC Requirements
+    Resources  Verbs
+    ---------  -----
+    pods       [list]
+    pods/exec  [create]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Remove a full backup set and the backup sets that depend on it
+pgo backup expire hippo --repo repo1 --set 20250223-010002F
+
+# Remove the full backup sets that finished more than 30 days ago
+pgo backup expire hippo --repo repo1 --older-than 30d
+
+### Example output
+The following backup sets will be removed from repo1:
+LABEL                               TYPE   STOP                   DEPENDS ON
+20250223-010002F                    full   2025-02-23T01:04:02Z
+20250223-010002F_20250224-010002I   incr   2025-02-24T01:00:38Z   20250223-010002F
+WARNING: Removed backup sets cannot be recovered.
+Are you sure you want to continue? (yes/no): yes
+repo1 backup set 20250223-010002F expired`)
+
+	expire := pgBackRestBackups{Config: config}
+
+	cmd.Flags().StringVar(&expire.RepoName, "repo", "", "the repository of the backup sets, such as 1 or repo1 (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("repo"))
+
+	cmd.Flags().StringVar(&expire.Set, "set", "", "the label of the backup set to remove")
+	cmd.Flags().StringVar(&expire.OlderThan, "older-than", "",
+		`remove full backup sets that finished before this age, such as "30d" or "12h"`)
+	cmd.MarkFlagsMutuallyExclusive("set", "older-than")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		expire.ClusterName = args[0]
+		if expire.Set == "" && expire.OlderThan == "" {
+			return fmt.Errorf("one of --set or --older-than is required")
+		}
+		return expire.Expire(context.Background(), time.Now())
+	}
+
+	return cmd
+}
+
+// backupSetLabel matches the label of a full backup set, or of a differential
+// or incremental backup set and the full backup set it depends on.
+var backupSetLabel = regexp.MustCompile(`^\d{8}-\d{6}F(_\d{8}-\d{6}[DI])?$`)
+
+// pgBackRestBackups lists and removes the pgBackRest backup sets of a
+// PostgresCluster.
+type pgBackRestBackups struct {
+	*internal.Config
+
+	ClusterName string
+	JSON        bool
+	OlderThan   string
+	PITRTarget  string
+	RepoName    string
+	Set         string
+}
+
+// backupSet is one backup in the output of "pgbackrest info --output=json".
+// - https://pgbackrest.org/command.html#command-info
+type backupSet struct {
+	Label     string   `json:"label"`
+	Type      string   `json:"type"`
+	Prior     string   `json:"prior"`
+	Reference []string `json:"reference"`
+	Error     bool     `json:"error"`
+
+	Archive struct {
+		Start string `json:"start"`
+		Stop  string `json:"stop"`
+	} `json:"archive"`
+	Database struct {
+		ID      int `json:"id"`
+		RepoKey int `json:"repo-key"`
+	} `json:"database"`
+	Info struct {
+		Size       int64 `json:"size"`
+		Repository struct {
+			Size int64 `json:"size"`
+		} `json:"repository"`
+	} `json:"info"`
+	Timestamp struct {
+		Start int64 `json:"start"`
+		Stop  int64 `json:"stop"`
+	} `json:"timestamp"`
+}
+
+// archiveRange is the WAL archived to one repository for one database.
+type archiveRange struct {
+	Database struct {
+		ID      int `json:"id"`
+		RepoKey int `json:"repo-key"`
+	} `json:"database"`
+	Min string `json:"min"`
+	Max string `json:"max"`
+}
+
+// backupRow is one row of "pgo backup list".
+type backupRow struct {
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Repo     string `json:"repo"`
+	Start    string `json:"start"`
+	Stop     string `json:"stop"`
+	Size     int64  `json:"size"`
+	RepoSize int64  `json:"repoSize"`
+	WALStart string `json:"walStart"`
+	WALStop  string `json:"walStop"`
+	Prior    string `json:"prior,omitempty"`
+}
+
+// parseBackupInfo parses the output of "pgbackrest info --output=json" into
+// backup sets and archived WAL, ordered as pgBackRest reports them: oldest first.
+func parseBackupInfo(info string) ([]backupSet, []archiveRange, error) {
+	var stanzas []struct {
+		Archive []archiveRange `json:"archive"`
+		Backup  []backupSet    `json:"backup"`
+	}
+	if err := json.Unmarshal([]byte(info), &stanzas); err != nil {
+		return nil, nil, fmt.Errorf("unexpected pgBackRest info: %w", err)
+	}
+
+	var backups []backupSet
+	var archives []archiveRange
+	for _, stanza := range stanzas {
+		backups = append(backups, stanza.Backup...)
+		archives = append(archives, stanza.Archive...)
+	}
+	return backups, archives, nil
+}
+
+func repoName(key int) string { return "repo" + strconv.Itoa(key) }
+
+// formatBytes returns size with a binary unit, such as "1.2GiB".
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	value, exponent := float64(size)/unit, 0
+	for value >= unit && exponent < 4 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f%ciB", value, "KMGTP"[exponent])
+}
+
+func formatUnix(seconds int64) string {
+	return time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+}
+
+// backupRows returns the rows of backups in repo, or of every repository when
+// repo is empty.
+func backupRows(backups []backupSet, repo string) []backupRow {
+	var rows []backupRow
+	for _, backup := range backups {
+		if repo != "" && repoName(backup.Database.RepoKey) != repo {
+			continue
+		}
+		rows = append(rows, backupRow{
+			Label:    backup.Label,
+			Type:     backup.Type,
+			Repo:     repoName(backup.Database.RepoKey),
+			Start:    formatUnix(backup.Timestamp.Start),
+			Stop:     formatUnix(backup.Timestamp.Stop),
+			Size:     backup.Info.Size,
+			RepoSize: backup.Info.Repository.Size,
+			WALStart: backup.Archive.Start,
+			WALStop:  backup.Archive.Stop,
+			Prior:    backup.Prior,
+		})
+	}
+	return rows
+}
+
+func printBackupRows(w io.Writer, rows []backupRow, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if rows == nil {
+			rows = []backupRow{}
+		}
+		return encoder.Encode(rows)
+	}
+
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "LABEL\tTYPE\tREPO\tSTART\tSTOP\tSIZE\tREPO SIZE\tWAL START\tWAL STOP\n"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.Label, row.Type, row.Repo, row.Start, row.Stop,
+			formatBytes(row.Size), formatBytes(row.RepoSize), row.WALStart, row.WALStop,
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// parseRecoveryTarget parses the time of a point-in-time recovery. It accepts
+// RFC 3339 and the format of the pgBackRest "--target" option. Times without
+// a zone are UTC.
+func parseRecoveryTarget(text string) (time.Time, error) {
+	for _, layout := range []string{
+		time.RFC3339,
+		"2006-01-02 15:04:05Z07:00",
+		"2006-01-02 15:04:05Z07",
+		"2006-01-02 15:04:05",
+		"2006-01-02T15:04:05",
+	} {
+		if t, err := time.Parse(layout, strings.TrimSpace(text)); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`invalid time %q: expected a format like "2025-03-09 14:00:00+00"`, text)
+}
+
+// recoveryMessages report whether each repository has a backup set and WAL
+// for a point-in-time recovery to target.
+func recoveryMessages(backups []backupSet, archives []archiveRange, repo string, target, now time.Time) []string {
+	var repos []int
+	for _, backup := range backups {
+		if !slices.Contains(repos, backup.Database.RepoKey) {
+			repos = append(repos, backup.Database.RepoKey)
+		}
+	}
+	slices.Sort(repos)
+
+	var messages []string
+	prefix := "PITR to " + target.Format(time.RFC3339)
+	for _, key := range repos {
+		name := repoName(key)
+		if repo != "" && name != repo {
+			continue
+		}
+
+		if target.After(now) {
+			messages = append(messages, fmt.Sprintf("%s: %s is not possible: the target is in the future", name, prefix))
+			continue
+		}
+
+		// Recovery starts from the latest backup set that finished before the target.
+		var start *backupSet
+		for i := range backups {
+			if backups[i].Database.RepoKey == key && !backups[i].Error &&
+				backups[i].Timestamp.Stop <= target.Unix() {
+				start = &backups[i]
+			}
+		}
+		if start == nil {
+			messages = append(messages, fmt.Sprintf(
+				"%s: %s is not possible: no backup set finished before the target", name, prefix))
+			continue
+		}
+
+		// The WAL archived for the database of the backup must reach at least
+		// the end of the backup.
+		latest := ""
+		for _, archive := range archives {
+			if archive.Database.RepoKey == key && archive.Database.ID == start.Database.ID {
+				latest = archive.Max
+			}
+		}
+		if latest == "" || walSegment(latest) < walSegment(start.Archive.Stop) {
+			messages = append(messages, fmt.Sprintf(
+				"%s: %s is not possible: WAL after backup %s is not archived", name, prefix, start.Label))
+			continue
+		}
+
+		messages = append(messages, fmt.Sprintf(
+			"%s: %s is possible from backup %s when WAL is archived through the target; latest archived WAL is %s",
+			name, prefix, start.Label, latest))
+	}
+	return messages
+}
+
+// walSegment returns the log and segment of a WAL file name so that names on
+// different timelines compare by position.
+func walSegment(name string) string {
+	if len(name) != 24 {
+		return name
+	}
+	return name[8:]
+}
+
+// dependentBackups returns the backup sets in the same repository as the
+// backup set labeled label that depend on it, directly or through another.
+func dependentBackups(backups []backupSet, repoKey int, label string) []backupSet {
+	removed := map[string]bool{label: true}
+	var dependents []backupSet
+
+	// Backup sets are ordered oldest first, so each one comes after those it
+	// depends on.
+	for _, backup := range backups {
+		if backup.Database.RepoKey != repoKey || removed[backup.Label] {
+			continue
+		}
+		depends := removed[backup.Prior]
+		for _, reference := range backup.Reference {
+			depends = depends || removed[reference]
+		}
+		if depends {
+			removed[backup.Label] = true
+			dependents = append(dependents, backup)
+		}
+	}
+	return dependents
+}
+
+// parseAge parses a duration such as "12h" or "30d".
+func parseAge(text string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(text); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf(`invalid age %q: expected a positive duration such as "30d" or "12h"`, text)
+}
+
+// expireSelection returns the labels of backup sets in repoKey to expire and
+// every backup set that would be removed with them.
+func (b pgBackRestBackups) expireSelection(backups []backupSet, repoKey int, now time.Time) (
+	[]string, []backupSet, error,
+) {
+	var labels []string
+
+	if b.Set != "" {
+		index := slices.IndexFunc(backups, func(backup backupSet) bool {
+			return backup.Database.RepoKey == repoKey && backup.Label == b.Set
+		})
+		if index < 0 {
+			return nil, nil, fmt.Errorf("backup set %q not found in %s", b.Set, repoName(repoKey))
+		}
+		labels = append(labels, b.Set)
+	} else {
+		age, err := parseAge(b.OlderThan)
+		if err != nil {
+			return nil, nil, err
+		}
+		cutoff := now.Add(-age).Unix()
+
+		var fulls []backupSet
+		for _, backup := range backups {
+			if backup.Database.RepoKey == repoKey && backup.Type == "full" {
+				fulls = append(fulls, backup)
+			}
+		}
+		// Keep the newest full backup set regardless of its age.
+		for i := 0; i < len(fulls)-1; i++ {
+			if fulls[i].Timestamp.Stop < cutoff {
+				labels = append(labels, fulls[i].Label)
+			}
+		}
+	}
+
+	var removed []backupSet
+	for _, backup := range backups {
+		if backup.Database.RepoKey == repoKey && slices.Contains(labels, backup.Label) {
+			removed = append(removed, backup)
+			removed = append(removed, dependentBackups(backups, repoKey, backup.Label)...)
+		}
+	}
+	return labels, removed, nil
+}
+
+func printExpiring(w io.Writer, removed []backupSet) error {
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "LABEL\tTYPE\tSTOP\tDEPENDS ON\n"); err != nil {
+		return err
+	}
+	for _, backup := range removed {
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\n",
+			backup.Label, backup.Type, formatUnix(backup.Timestamp.Stop), backup.Prior,
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// getBackupExec returns an executor for the pgBackRest container of the
+// repository host of clusterName. Clusters without a repository host store
+// backups only in the cloud, so it returns an executor for the primary
+// instance instead.
+func getBackupExec(ctx context.Context, config *internal.Config, clusterName string) (
+	func(stdin io.Reader, stdout io.Writer, stderr io.Writer, command ...string) error,
+	error,
+) {
+	rest, err := config.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := config.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	pods, err := client.Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.RepoHostInstanceLabels(clusterName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		return getPrimaryExec(config, []string{clusterName})
+	}
+
+	podExec, err := util.NewPodExecutor(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	pod := pods.Items[0]
+	return func(stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+		return podExec(pod.Namespace, pod.Name, util.ContainerPGBackrest,
+			stdin, stdout, stderr, command...)
+	}, nil
+}
+
+// repoName returns RepoName as a repository name, such as repo1 for 1 or
+// repo1. It returns an error when that is not a repository that PGO accepts.
+func (b pgBackRestBackups) repoName() (string, error) {
+	name := "repo" + strings.TrimPrefix(b.RepoName, "repo")
+	if !repoNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid repository %q: expected 1, 2, 3, or 4", b.RepoName)
+	}
+	return name, nil
+}
+
+// info returns the backup sets and archived WAL of the cluster.
+func (b pgBackRestBackups) info() ([]backupSet, []archiveRange, error) {
+	exec, err := getPrimaryExec(b.Config, []string{b.ClusterName})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stdout, stderr, err := Executor(exec).pgBackRestInfo("json",
+		strings.TrimPrefix(b.RepoName, "repo"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return parseBackupInfo(stdout)
+}
+
+// List prints the backup sets of the cluster and, when there is a PITRTarget,
+// whether a point-in-time recovery to it is possible.
+func (b pgBackRestBackups) List(ctx context.Context, now time.Time) error {
+	if b.RepoName != "" {
+		name, err := b.repoName()
+		if err != nil {
+			return err
+		}
+		b.RepoName = name
+	}
+
+	var target time.Time
+	if b.PITRTarget != "" {
+		var err error
+		if target, err = parseRecoveryTarget(b.PITRTarget); err != nil {
+			return err
+		}
+	}
+
+	backups, archives, err := b.info()
+	if err != nil {
+		return err
+	}
+
+	if err := printBackupRows(b.Out, backupRows(backups, b.RepoName), b.JSON); err != nil {
+		return err
+	}
+	if b.PITRTarget != "" {
+		for _, message := range recoveryMessages(backups, archives, b.RepoName, target, now) {
+			_, _ = fmt.Fprintln(b.Out, message)
+		}
+	}
+	return nil
+}
+
+// Expire explains which backup sets will be removed, asks for confirmation,
+// and then runs "pgbackrest expire" for each selected backup set.
+func (b pgBackRestBackups) Expire(ctx context.Context, now time.Time) error {
+	name, err := b.repoName()
+	if err != nil {
+		return err
+	}
+	b.RepoName = name
+	repoNum := strings.TrimPrefix(name, "repo")
+	repoKey, _ := strconv.Atoi(repoNum)
+	if b.Set != "" && !backupSetLabel.MatchString(b.Set) {
+		return fmt.Errorf("invalid backup set %q: expected a label such as 20250223-010002F", b.Set)
+	}
+
+	backups, _, err := b.info()
+	if err != nil {
+		return err
+	}
+
+	labels, removed, err := b.expireSelection(backups, repoKey, now)
+	if err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		_, _ = fmt.Fprintf(b.Out, "No backup sets in %s are older than %s\n", repoName(repoKey), b.OlderThan)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(b.Out, "The following backup sets will be removed from %s:\n", repoName(repoKey))
+	if err := printExpiring(b.Out, removed); err != nil {
+		return err
+	}
+	if !confirm(b.In, b.Out, "WARNING: Removed backup sets cannot be recovered."+
+		"\nAre you sure you want to continue? (yes/no): ") {
+		return nil
+	}
+
+	exec, err := getBackupExec(ctx, b.Config, b.ClusterName)
+	if err != nil {
+		return err
+	}
+	for _, label := range labels {
+		_, stderr, err := Executor(exec).pgBackRestExpire(repoNum, label)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+		}
+		_, _ = fmt.Fprintf(b.Out, "%s backup set %s expired\n", repoName(repoKey), label)
+	}
+	return nil
+}
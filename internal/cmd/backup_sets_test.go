@@ -0,0 +1,232 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+const backupInfoJSON = `[{"name":"db","status":{"code":0,"message":"ok"},
+"archive":[
+  {"database":{"id":1,"repo-key":1},"id":"16-1","min":"000000010000000000000001","max":"000000010000000000000031"},
+  {"database":{"id":1,"repo-key":2},"id":"16-1","min":"000000010000000000000010","max":"000000010000000000000012"}
+],
+"backup":[
+  {"label":"20250223-010002F","type":"full","prior":null,"reference":null,"error":false,
+   "archive":{"start":"000000010000000000000002","stop":"000000010000000000000003"},
+   "database":{"id":1,"repo-key":1},
+   "info":{"size":1288490188,"delta":1288490188,"repository":{"size":325477785,"delta":325477785}},
+   "timestamp":{"start":1740272402,"stop":1740272642}},
+  {"label":"20250223-010002F_20250224-010002I","type":"incr","prior":"20250223-010002F","reference":["20250223-010002F"],"error":false,
+   "archive":{"start":"000000010000000000000005","stop":"000000010000000000000005"},
+   "database":{"id":1,"repo-key":1},
+   "info":{"size":1288490188,"delta":13212057,"repository":{"size":2048,"delta":2048}},
+   "timestamp":{"start":1740358802,"stop":1740358838}},
+  {"label":"20250302-010002F","type":"full","prior":null,"reference":null,"error":false,
+   "archive":{"start":"000000010000000000000010","stop":"000000010000000000000012"},
+   "database":{"id":1,"repo-key":1},
+   "info":{"size":1288490188,"delta":1288490188,"repository":{"size":325477785,"delta":325477785}},
+   "timestamp":{"start":1740877202,"stop":1740877450}},
+  {"label":"20250302-010002F_20250305-010002D","type":"diff","prior":"20250302-010002F","reference":["20250302-010002F"],"error":false,
+   "archive":{"start":"000000010000000000000020","stop":"000000010000000000000020"},
+   "database":{"id":1,"repo-key":1},
+   "info":{"size":1288490188,"delta":52428800,"repository":{"size":13212057,"delta":13212057}},
+   "timestamp":{"start":1741136402,"stop":1741136440}},
+  {"label":"20250302-010002F_20250309-010003I","type":"incr","prior":"20250302-010002F_20250305-010002D","reference":["20250302-010002F"],"error":false,
+   "archive":{"start":"00000001000000000000002A","stop":"00000001000000000000002A"},
+   "database":{"id":1,"repo-key":1},
+   "info":{"size":1288490188,"delta":13212057,"repository":{"size":13212057,"delta":13212057}},
+   "timestamp":{"start":1741482003,"stop":1741482041}},
+  {"label":"20250302-020002F","type":"full","prior":null,"reference":null,"error":false,
+   "archive":{"start":"000000010000000000000011","stop":"000000010000000000000012"},
+   "database":{"id":1,"repo-key":2},
+   "info":{"size":1288490188,"delta":1288490188,"repository":{"size":325477785,"delta":325477785}},
+   "timestamp":{"start":1740880802,"stop":1740881050}}
+]}]`
+
+func TestParseBackupInfo(t *testing.T) {
+	backups, archives, err := parseBackupInfo(backupInfoJSON)
+	assert.NilError(t, err)
+	assert.Equal(t, len(backups), 6)
+	assert.Equal(t, len(archives), 2)
+	assert.Equal(t, backups[1].Prior, "20250223-010002F")
+	assert.Equal(t, backups[0].Prior, "")
+
+	_, _, err = parseBackupInfo("not json")
+	assert.ErrorContains(t, err, "unexpected pgBackRest info")
+
+	var buf bytes.Buffer
+	assert.NilError(t, printBackupRows(&buf, backupRows(backups, "repo2"), false))
+	assert.Equal(t, buf.String(), ``+
+		"LABEL              TYPE   REPO    START                  STOP                   SIZE     REPO SIZE   WAL START                  WAL STOP\n"+
+		"20250302-020002F   full   repo2   2025-03-02T02:00:02Z   2025-03-02T02:04:10Z   1.2GiB   310.4MiB    000000010000000000000011   000000010000000000000012\n")
+
+	buf.Reset()
+	assert.NilError(t, printBackupRows(&buf, backupRows(backups, "repo3"), true))
+	assert.Equal(t, buf.String(), "[]\n")
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, formatBytes(0), "0B")
+	assert.Equal(t, formatBytes(1023), "1023B")
+	assert.Equal(t, formatBytes(2048), "2.0KiB")
+	assert.Equal(t, formatBytes(13212057), "12.6MiB")
+	assert.Equal(t, formatBytes(1288490188), "1.2GiB")
+}
+
+func TestRecoveryMessages(t *testing.T) {
+	backups, archives, err := parseBackupInfo(backupInfoJSON)
+	assert.NilError(t, err)
+
+	now := time.Date(2025, 3, 9, 14, 30, 0, 0, time.UTC)
+
+	target, err := parseRecoveryTarget("2025-03-09 14:00:00+00")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, recoveryMessages(backups, archives, "", target, now), []string{
+		"repo1: PITR to 2025-03-09T14:00:00Z is possible from backup 20250302-010002F_20250309-010003I" +
+			" when WAL is archived through the target; latest archived WAL is 000000010000000000000031",
+		"repo2: PITR to 2025-03-09T14:00:00Z is possible from backup 20250302-020002F" +
+			" when WAL is archived through the target; latest archived WAL is 000000010000000000000012",
+	})
+
+	target, err = parseRecoveryTarget("2025-03-02T01:30:00Z")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, recoveryMessages(backups, archives, "repo2", target, now), []string{
+		"repo2: PITR to 2025-03-02T01:30:00Z is not possible: no backup set finished before the target",
+	})
+
+	target, err = parseRecoveryTarget("2025-03-10 00:00:00")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, recoveryMessages(backups, archives, "repo1", target, now), []string{
+		"repo1: PITR to 2025-03-10T00:00:00Z is not possible: the target is in the future",
+	})
+
+	// WAL archiving stopped before the end of the latest backup
+	archives[0].Max = "000000010000000000000029"
+	target, err = parseRecoveryTarget("2025-03-09T10:00:00-04:00")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, recoveryMessages(backups, archives, "repo1", target, now), []string{
+		"repo1: PITR to 2025-03-09T14:00:00Z is not possible: WAL after backup 20250302-010002F_20250309-010003I is not archived",
+	})
+
+	_, err = parseRecoveryTarget("yesterday")
+	assert.ErrorContains(t, err, `invalid time "yesterday"`)
+}
+
+func TestDependentBackups(t *testing.T) {
+	backups, _, err := parseBackupInfo(backupInfoJSON)
+	assert.NilError(t, err)
+
+	labels := func(backups []backupSet) []string {
+		var result []string
+		for _, backup := range backups {
+			result = append(result, backup.Label)
+		}
+		return result
+	}
+
+	assert.DeepEqual(t, labels(dependentBackups(backups, 1, "20250302-010002F")), []string{
+		"20250302-010002F_20250305-010002D",
+		"20250302-010002F_20250309-010003I",
+	})
+	assert.DeepEqual(t, labels(dependentBackups(backups, 1, "20250302-010002F_20250305-010002D")), []string{
+		"20250302-010002F_20250309-010003I",
+	})
+	assert.Assert(t, dependentBackups(backups, 1, "20250302-010002F_20250309-010003I") == nil)
+	assert.Assert(t, dependentBackups(backups, 2, "20250302-010002F") == nil)
+
+	now := time.Date(2025, 3, 9, 14, 30, 0, 0, time.UTC)
+
+	selected, removed, err := pgBackRestBackups{Set: "20250223-010002F"}.expireSelection(backups, 1, now)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, selected, []string{"20250223-010002F"})
+	assert.DeepEqual(t, labels(removed), []string{
+		"20250223-010002F",
+		"20250223-010002F_20250224-010002I",
+	})
+
+	var buf bytes.Buffer
+	assert.NilError(t, printExpiring(&buf, removed))
+	assert.Equal(t, buf.String(), ``+
+		"LABEL                               TYPE   STOP                   DEPENDS ON\n"+
+		"20250223-010002F                    full   2025-02-23T01:04:02Z   \n"+
+		"20250223-010002F_20250224-010002I   incr   2025-02-24T01:00:38Z   20250223-010002F\n")
+
+	_, _, err = pgBackRestBackups{Set: "20250302-020002F"}.expireSelection(backups, 1, now)
+	assert.ErrorContains(t, err, `backup set "20250302-020002F" not found in repo1`)
+
+	// The newest full backup set is never selected by age.
+	selected, _, err = pgBackRestBackups{OlderThan: "1d"}.expireSelection(backups, 1, now)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, selected, []string{"20250223-010002F"})
+
+	selected, _, err = pgBackRestBackups{OlderThan: "1d"}.expireSelection(backups, 2, now)
+	assert.NilError(t, err)
+	assert.Assert(t, selected == nil)
+
+	selected, _, err = pgBackRestBackups{OlderThan: "30d"}.expireSelection(backups, 1, now)
+	assert.NilError(t, err)
+	assert.Assert(t, selected == nil)
+}
+
+func TestParseAge(t *testing.T) {
+	for text, expected := range map[string]time.Duration{
+		"30d": 30 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"90m": 90 * time.Minute,
+	} {
+		age, err := parseAge(text)
+		assert.NilError(t, err)
+		assert.Equal(t, age, expected)
+	}
+
+	for _, text := range []string{"", "d", "-1d", "0h", "week"} {
+		_, err := parseAge(text)
+		assert.ErrorContains(t, err, "invalid age")
+	}
+}
+
+func TestBackupSetLabel(t *testing.T) {
+	for _, label := range []string{
+		"20250223-010002F",
+		"20250223-010002F_20250224-010002D",
+		"20250223-010002F_20250224-010002I",
+	} {
+		assert.Assert(t, backupSetLabel.MatchString(label), label)
+	}
+
+	for _, label := range []string{
+		"", "20250223-010002", "20250223-010002D", "20250223-010002F_",
+		"20250223-010002F;reboot", "20250223-010002F $(id)",
+	} {
+		assert.Assert(t, !backupSetLabel.MatchString(label), label)
+	}
+
+	// Invalid labels are refused before anything runs in the cluster.
+	err := pgBackRestBackups{RepoName: "repo1", Set: "x;reboot"}.Expire(context.Background(), time.Now())
+	assert.ErrorContains(t, err, `invalid backup set "x;reboot"`)
+}
+
+func TestBackupSetsRepoName(t *testing.T) {
+	for _, value := range []string{"1", "repo1"} {
+		name, err := pgBackRestBackups{RepoName: value}.repoName()
+		assert.NilError(t, err)
+		assert.Equal(t, name, "repo1")
+	}
+
+	for _, value := range []string{"", "5", "repo", "1;reboot", "repo1 --help"} {
+		_, err := pgBackRestBackups{RepoName: value}.repoName()
+		assert.ErrorContains(t, err, "invalid repository", value)
+	}
+
+	// Invalid repositories are refused before anything runs in the cluster.
+	err := pgBackRestBackups{RepoName: "repo1;reboot"}.List(context.Background(), time.Now())
+	assert.ErrorContains(t, err, `invalid repository "repo1;reboot"`)
+}
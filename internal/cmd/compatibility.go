@@ -0,0 +1,334 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+)
+
+// operatorVersion is the version of the Crunchy Postgres Operator.
+type operatorVersion struct{ Major, Minor, Patch int }
+
+// parseOperatorVersion parses versions such as "5.7.0", "v5.7.2", and
+// "5.8.0-0". A missing patch number is zero.
+func parseOperatorVersion(s string) (operatorVersion, error) {
+	var version operatorVersion
+
+	core, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(s), "v"), "-")
+	parts := strings.Split(core, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return version, fmt.Errorf("invalid operator version %q", s)
+	}
+
+	numbers := []*int{&version.Major, &version.Minor, &version.Patch}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return version, fmt.Errorf("invalid operator version %q", s)
+		}
+		*numbers[i] = number
+	}
+	return version, nil
+}
+
+// Less reports whether v is older than other.
+func (v operatorVersion) Less(other operatorVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor < other.Minor
+	}
+	return v.Patch < other.Patch
+}
+
+func (v operatorVersion) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+var (
+	// minimumOperatorVersion is the oldest operator this client supports.
+	minimumOperatorVersion = operatorVersion{5, 3, 0}
+
+	// latestOperatorVersion is the newest minor release of the operator this
+	// client is tested with. Its patch releases are supported, too.
+	latestOperatorVersion = operatorVersion{5, 8, 0}
+)
+
+// supportedOperatorVersions describes the operators this client supports.
+func supportedOperatorVersions() string {
+	return fmt.Sprintf("%s through v%d.%d.x", minimumOperatorVersion,
+		latestOperatorVersion.Major, latestOperatorVersion.Minor)
+}
+
+// operatorFeature is a command, or a flag of a command, that needs a newer
+// operator than the first release of v5.
+type operatorFeature struct {
+	// Command is the path of the command without the root command, such as
+	// "create postgrescluster".
+	Command string
+
+	// Flag, when set, limits the feature to the command with this flag.
+	Flag string
+
+	// Minimum is the oldest operator that has the feature.
+	Minimum operatorVersion
+
+	// Reason says what the feature needs from the operator.
+	Reason string
+}
+
+// Usage returns the command and flag of feature.
+func (feature operatorFeature) Usage() string {
+	if feature.Flag != "" {
+		return feature.Command + " --" + feature.Flag
+	}
+	return feature.Command
+}
+
+// operatorFeatures is the compatibility matrix of this client: the commands
+// and flags that need a newer operator than the first release of v5. Those
+// older than minimumOperatorVersion are refused when the operator is even
+// older than that.
+var operatorFeatures = []operatorFeature{
+	{
+		Command: "create pgadmin", Minimum: operatorVersion{5, 5, 0},
+		Reason: "the PGAdmin API",
+	},
+	{
+		Command: "create postgrescluster", Flag: "disable-backups",
+		Minimum: operatorVersion{5, 7, 0},
+		Reason:  "PostgresClusters without backups",
+	},
+	{
+		Command: "delete pgadmin", Minimum: operatorVersion{5, 5, 0},
+		Reason: "the PGAdmin API",
+	},
+	{
+		Command: "show pgadmin", Minimum: operatorVersion{5, 5, 0},
+		Reason: "the PGAdmin API",
+	},
+	{
+		Command: "upgrade", Minimum: operatorVersion{5, 1, 0},
+		Reason: "the PGUpgrade API",
+	},
+}
+
+// unsupportedFeatures returns the features in operatorFeatures that version
+// does not have.
+func unsupportedFeatures(version operatorVersion) []operatorFeature {
+	var features []operatorFeature
+	for _, feature := range operatorFeatures {
+		if version.Less(feature.Minimum) {
+			features = append(features, feature)
+		}
+	}
+	return features
+}
+
+// checkOperatorVersion returns a warning when version is older than this
+// client supports and an error when cmd needs a newer operator than version.
+func checkOperatorVersion(cmd *cobra.Command, version operatorVersion) (string, error) {
+	var warning string
+	if version.Less(minimumOperatorVersion) {
+		warning = fmt.Sprintf(
+			"Operator %s is older than this client supports (%s). Upgrade the operator to %s or later.",
+			version, supportedOperatorVersions(), minimumOperatorVersion)
+	}
+
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	for _, feature := range unsupportedFeatures(version) {
+		if feature.Command == path &&
+			(feature.Flag == "" || cmd.Flags().Changed(feature.Flag)) {
+			return warning, fmt.Errorf(
+				"%s needs operator %s or later for %s; the operator is %s",
+				feature.Usage(), feature.Minimum, feature.Reason, version)
+		}
+	}
+	return warning, nil
+}
+
+// getOperatorVersion returns the version label of the PostgresCluster CRD.
+// The version is empty when the CRD has no label.
+func getOperatorVersion(ctx context.Context, config *internal.Config) (string, error) {
+	restConfig, err := config.ToRESTConfig()
+	if err != nil {
+		return "", err
+	}
+	// get a client capable of retrieving the PostgresCluster CRD
+	client, err := v1.NewForConfig(restConfig)
+	if err != nil {
+		return "", err
+	}
+	crd, err := client.CustomResourceDefinitions().
+		Get(ctx, "postgresclusters.postgres-operator.crunchydata.com", metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return crd.Labels["app.kubernetes.io/version"], nil
+}
+
+const (
+	// operatorVersionTimeout limits how long a command waits for the operator
+	// version before it runs.
+	operatorVersionTimeout = 5 * time.Second
+
+	// operatorVersionCacheTTL is how long the operator version of an API
+	// is remembered.
+	operatorVersionCacheTTL = time.Hour
+)
+
+// operatorVersionCache remembers the operator version of each Kubernetes API
+// in a file. Entries are keyed by operatorVersionKey.
+type operatorVersionCache struct {
+	Path string
+	Now  func() time.Time
+}
+
+// cachedOperatorVersion is the operator version of one API. An empty
+// Version means the version could not be found.
+type cachedOperatorVersion struct {
+	Version string    `json:"version"`
+	Checked time.Time `json:"checked"`
+}
+
+// newOperatorVersionCache returns a cache in the user cache directory.
+func newOperatorVersionCache() (operatorVersionCache, error) {
+	dir, err := os.UserCacheDir()
+	return operatorVersionCache{
+		Path: filepath.Join(dir, "pgo", "operator-versions.json"),
+		Now:  time.Now,
+	}, err
+}
+
+func (cache operatorVersionCache) load() map[string]cachedOperatorVersion {
+	entries := map[string]cachedOperatorVersion{}
+	if data, err := os.ReadFile(cache.Path); err == nil {
+		_ = json.Unmarshal(data, &entries)
+	}
+	return entries
+}
+
+// Get returns the version of key when it was stored recently enough.
+func (cache operatorVersionCache) Get(key string) (string, bool) {
+	entry, ok := cache.load()[key]
+	if !ok || cache.Now().Sub(entry.Checked) > operatorVersionCacheTTL {
+		return "", false
+	}
+	return entry.Version, true
+}
+
+// Set stores the version of key.
+func (cache operatorVersionCache) Set(key, version string) error {
+	entries := cache.load()
+	entries[key] = cachedOperatorVersion{Version: version, Checked: cache.Now()}
+
+	data, err := json.Marshal(entries)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(cache.Path), 0o700)
+	}
+	if err == nil {
+		err = os.WriteFile(cache.Path, data, 0o600)
+	}
+	return err
+}
+
+// operatorVersionKey identifies the Kubernetes API in use by the name of the
+// kubeconfig context, its cluster, and the URL of the server. A context name
+// alone can refer to different APIs over time or across kubeconfig files.
+func operatorVersionKey(config *internal.Config) string {
+	var contextName, clusterName, server string
+
+	raw, err := config.ToRawKubeConfigLoader().RawConfig()
+	if err == nil {
+		contextName = raw.CurrentContext
+	}
+	if config.Context != nil && *config.Context != "" {
+		contextName = *config.Context
+	}
+	if kubeContext, ok := raw.Contexts[contextName]; ok {
+		clusterName = kubeContext.Cluster
+	}
+	if config.ClusterName != nil && *config.ClusterName != "" {
+		clusterName = *config.ClusterName
+	}
+	if rest, err := config.ToRESTConfig(); err == nil {
+		server = rest.Host
+	}
+
+	return strings.Join([]string{contextName, clusterName, server}, " ")
+}
+
+// skipsOperatorVersionCheck reports whether cmd runs without checking the
+// operator version, because it does not talk to the operator or checks the
+// version itself.
+func skipsOperatorVersionCheck(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case "version", "help", "completion",
+		cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	return !cmd.Runnable()
+}
+
+// checkOperatorCompatibility runs before every command. It warns when the
+// operator of the current context is not supported and refuses commands that
+// need a newer operator. The version is cached per context and server; when it
+// cannot be found, nothing is checked.
+func checkOperatorCompatibility(config *internal.Config) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		if skipsOperatorVersionCheck(cmd) {
+			return nil
+		}
+
+		key := operatorVersionKey(config)
+		cache, cacheErr := newOperatorVersionCache()
+
+		label, found := "", false
+		if cacheErr == nil {
+			label, found = cache.Get(key)
+		}
+		if !found {
+			ctx, cancel := context.WithTimeout(context.Background(), operatorVersionTimeout)
+			defer cancel()
+
+			// Answers such as missing permissions are remembered as an
+			// unknown version so that they do not slow every command. Other
+			// errors, such as an unreachable API, are tried again next time.
+			var err error
+			label, err = getOperatorVersion(ctx, config)
+			var status apierrors.APIStatus
+			if cacheErr == nil && (err == nil || errors.As(err, &status)) {
+				_ = cache.Set(key, label)
+			}
+		}
+
+		version, err := parseOperatorVersion(label)
+		if err != nil {
+			return nil
+		}
+
+		warning, err := checkOperatorVersion(cmd, version)
+		if warning != "" {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "WARNING: %s\n", warning)
+		}
+		return err
+	}
+}
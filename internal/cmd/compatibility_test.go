@@ -0,0 +1,187 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+)
+
+func TestParseOperatorVersion(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		output operatorVersion
+	}{
+		{"5.7.0", operatorVersion{5, 7, 0}},
+		{"v5.7.2", operatorVersion{5, 7, 2}},
+		{"5.8.0-0", operatorVersion{5, 8, 0}},
+		{" 5.10 ", operatorVersion{5, 10, 0}},
+	} {
+		version, err := parseOperatorVersion(tc.input)
+		assert.NilError(t, err, tc.input)
+		assert.Equal(t, version, tc.output)
+	}
+
+	for _, input := range []string{"", "5", "5.x.0", "5.7.0.1", "5.-1.0"} {
+		_, err := parseOperatorVersion(input)
+		assert.ErrorContains(t, err, "invalid operator version", input)
+	}
+
+	assert.Equal(t, operatorVersion{5, 7, 0}.String(), "v5.7.0")
+	assert.Assert(t, operatorVersion{5, 6, 9}.Less(operatorVersion{5, 7, 0}))
+	assert.Assert(t, operatorVersion{4, 9, 0}.Less(operatorVersion{5, 0, 0}))
+	assert.Assert(t, operatorVersion{5, 7, 0}.Less(operatorVersion{5, 7, 1}))
+	assert.Assert(t, !operatorVersion{5, 7, 0}.Less(operatorVersion{5, 7, 0}))
+}
+
+func TestCheckOperatorVersion(t *testing.T) {
+	root := &cobra.Command{Use: "pgo"}
+	create := &cobra.Command{Use: "create"}
+	cluster := &cobra.Command{Use: "postgrescluster CLUSTER_NAME", Run: func(*cobra.Command, []string) {}}
+	cluster.Flags().Bool("disable-backups", false, "")
+	pgadmin := &cobra.Command{Use: "pgadmin PGADMIN_NAME", Run: func(*cobra.Command, []string) {}}
+	upgrade := &cobra.Command{Use: "upgrade CLUSTER_NAME", Run: func(*cobra.Command, []string) {}}
+	root.AddCommand(create, upgrade)
+	create.AddCommand(cluster, pgadmin)
+
+	warning, err := checkOperatorVersion(cluster, operatorVersion{5, 6, 0})
+	assert.NilError(t, err)
+	assert.Equal(t, warning, "")
+
+	assert.NilError(t, cluster.Flags().Set("disable-backups", "true"))
+	_, err = checkOperatorVersion(cluster, operatorVersion{5, 6, 0})
+	assert.ErrorContains(t, err, "create postgrescluster --disable-backups needs operator v5.7.0 or later")
+	assert.ErrorContains(t, err, "the operator is v5.6.0")
+
+	_, err = checkOperatorVersion(cluster, operatorVersion{5, 7, 0})
+	assert.NilError(t, err)
+
+	// Commands older than the minimum are refused by even older operators.
+	warning, err = checkOperatorVersion(upgrade, operatorVersion{5, 0, 1})
+	assert.ErrorContains(t, err, "upgrade needs operator v5.1.0 or later for the PGUpgrade API")
+	assert.Assert(t, warning != "")
+
+	_, err = checkOperatorVersion(upgrade, operatorVersion{5, 2, 0})
+	assert.NilError(t, err)
+
+	warning, err = checkOperatorVersion(pgadmin, operatorVersion{5, 2, 0})
+	assert.ErrorContains(t, err, "create pgadmin needs operator v5.5.0 or later")
+	assert.Equal(t, warning, "Operator v5.2.0 is older than this client supports "+
+		"(v5.3.0 through v5.8.x). Upgrade the operator to v5.3.0 or later.")
+}
+
+func TestOperatorVersionCache(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	cache := operatorVersionCache{
+		Path: filepath.Join(t.TempDir(), "pgo", "operator-versions.json"),
+		Now:  func() time.Time { return now },
+	}
+
+	_, found := cache.Get("kind")
+	assert.Assert(t, !found)
+
+	assert.NilError(t, cache.Set("kind", "5.7.0"))
+	assert.NilError(t, cache.Set("prod", ""))
+
+	version, found := cache.Get("kind")
+	assert.Assert(t, found)
+	assert.Equal(t, version, "5.7.0")
+
+	// An unknown version is remembered, too.
+	version, found = cache.Get("prod")
+	assert.Assert(t, found)
+	assert.Equal(t, version, "")
+
+	// Versions expire.
+	now = now.Add(operatorVersionCacheTTL + time.Second)
+	_, found = cache.Get("kind")
+	assert.Assert(t, !found)
+
+	// A damaged file is replaced.
+	assert.NilError(t, os.WriteFile(cache.Path, []byte("{"), 0o600))
+	_, found = cache.Get("kind")
+	assert.Assert(t, !found)
+	assert.NilError(t, cache.Set("kind", "5.8.0"))
+	version, found = cache.Get("kind")
+	assert.Assert(t, found)
+	assert.Equal(t, version, "5.8.0")
+}
+
+func TestOperatorVersionKey(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	assert.NilError(t, os.WriteFile(kubeconfig, []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- name: east
+  cluster: {server: "https://east.example.com:6443"}
+- name: west
+  cluster: {server: "https://west.example.com:6443"}
+contexts:
+- name: prod
+  context: {cluster: east, user: admin}
+- name: dr
+  context: {cluster: west, user: admin}
+current-context: prod
+users:
+- name: admin
+  user: {token: secret}
+`), 0o600))
+
+	flags := genericclioptions.NewConfigFlags(false)
+	flags.KubeConfig = &kubeconfig
+	config := &internal.Config{ConfigFlags: flags}
+
+	assert.Equal(t, operatorVersionKey(config), "prod east https://east.example.com:6443")
+
+	contextName := "dr"
+	flags.Context = &contextName
+	assert.Equal(t, operatorVersionKey(config), "dr west https://west.example.com:6443")
+
+	// The same context name with another server is another key.
+	clusterName := "east"
+	flags.ClusterName = &clusterName
+	assert.Equal(t, operatorVersionKey(config), "dr east https://east.example.com:6443")
+}
+
+func TestPrintOperatorCompatibility(t *testing.T) {
+	var buf bytes.Buffer
+	printOperatorCompatibility(&buf, operatorVersion{5, 7, 1})
+	assert.Equal(t, buf.String(), "Operator v5.7.1 is supported.\n")
+
+	buf.Reset()
+	printOperatorCompatibility(&buf, operatorVersion{5, 6, 1})
+	assert.Equal(t, buf.String(), ``+
+		"Operator v5.6.1 is supported. Upgrade the operator to use:\n"+
+		"    create postgrescluster --disable-backups (v5.7.0 or later)\n")
+
+	buf.Reset()
+	printOperatorCompatibility(&buf, operatorVersion{5, 8, 4})
+	assert.Equal(t, buf.String(), "Operator v5.8.4 is supported.\n")
+
+	buf.Reset()
+	printOperatorCompatibility(&buf, operatorVersion{5, 9, 0})
+	assert.Equal(t, buf.String(),
+		"WARNING: Operator v5.9.0 is newer than this client supports. Upgrade the client.\n")
+
+	buf.Reset()
+	printOperatorCompatibility(&buf, operatorVersion{5, 2, 0})
+	assert.Equal(t, buf.String(), ``+
+		"WARNING: Operator v5.2.0 is older than this client supports. Upgrade the operator to v5.3.0 or later.\n"+
+		"The operator also lacks:\n"+
+		"    create pgadmin (v5.5.0 or later)\n"+
+		"    create postgrescluster --disable-backups (v5.7.0 or later)\n"+
+		"    delete pgadmin (v5.5.0 or later)\n"+
+		"    show pgadmin (v5.5.0 or later)\n")
+}
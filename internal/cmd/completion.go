@@ -0,0 +1,356 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// completionTimeout limits how long shell completion waits for the API so
+// that the shell is not blocked.
+const completionTimeout = 2 * time.Second
+
+// completer returns the names that complete an argument of cmd.
+type completer func(ctx context.Context, cmd *cobra.Command, args []string) ([]string, error)
+
+// complete calls fn with a short timeout and returns the names that start
+// with toComplete. Errors are ignored; completion offers nothing instead.
+func (fn completer) complete(cmd *cobra.Command, args []string, toComplete string) (
+	[]string, cobra.ShellCompDirective,
+) {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	// Not everything that fn does takes a context, such as the API discovery
+	// of its clients, so stop waiting for fn when the timeout passes.
+	result := make(chan []string, 1)
+	go func() {
+		names, _ := fn(ctx, cmd, args)
+		result <- names
+	}()
+
+	var names []string
+	select {
+	case names = <-result:
+	case <-ctx.Done():
+	}
+
+	var matches []string
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			matches = append(matches, name)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoFileComp
+}
+
+// usagePlaceholders returns the positional arguments in the usage line of a
+// command, such as CLUSTER_NAME in "restore CLUSTER_NAME --repoName REPO".
+func usagePlaceholders(use string) []string {
+	fields := strings.Fields(use)
+
+	var placeholders []string
+	for i := 1; i < len(fields); i++ {
+		if strings.HasPrefix(fields[i], "-") {
+			// Skip the flag and its value.
+			i++
+			continue
+		}
+		placeholders = append(placeholders, fields[i])
+	}
+	return placeholders
+}
+
+// existingPlaceholders returns the placeholders of cmd that name objects that
+// already exist. Names are not completed for commands that create things. An
+// add command, such as "repo add CLUSTER_NAME REPO_NAME", adds the object of
+// its last placeholder.
+func existingPlaceholders(cmd *cobra.Command) []string {
+	if cmd.Name() == "create" || (cmd.HasParent() && cmd.Parent().Name() == "create") {
+		return nil
+	}
+
+	placeholders := usagePlaceholders(cmd.Use)
+	if cmd.Name() == "add" && len(placeholders) > 0 {
+		placeholders = placeholders[:len(placeholders)-1]
+	}
+	return placeholders
+}
+
+// registerCompletions adds completion of names from the API to cmd and its
+// subcommands. Positional arguments complete according to their placeholder
+// in the usage line, when it names an object that already exists.
+func registerCompletions(cmd *cobra.Command, config *internal.Config) {
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub, config)
+	}
+
+	if placeholders := existingPlaceholders(cmd); len(placeholders) > 0 &&
+		cmd.ValidArgsFunction == nil {
+		cmd.ValidArgsFunction = completePositional(config, placeholders)
+	}
+
+	flags := map[string]completer{
+		"cluster":           completeClusterNames(config),
+		"from":              completeClusterNames(config),
+		"from-namespace":    completeNamespaces(config),
+		"instance":          completeClusterPods(config),
+		"instance-set":      completeInstanceSets(config),
+		"primary":           completeClusterNames(config),
+		"primary-namespace": completeNamespaces(config),
+		"repo":              completeRepoNames(config),
+		"repoName":          completeRepoNames(config),
+		"scratch-namespace": completeNamespaces(config),
+	}
+	for name, fn := range flags {
+		if cmd.LocalNonPersistentFlags().Lookup(name) != nil {
+			cobra.CheckErr(cmd.RegisterFlagCompletionFunc(name, fn.complete))
+		}
+	}
+
+	if !cmd.HasParent() {
+		cobra.CheckErr(cmd.RegisterFlagCompletionFunc("namespace", completeNamespaces(config).complete))
+	}
+}
+
+// completePositional completes the positional argument that is next in
+// placeholders.
+func completePositional(config *internal.Config, placeholders []string) func(
+	*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective,
+) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(placeholders) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var fn completer
+		switch placeholders[len(args)] {
+		case "CLUSTER_NAME", "STANDBY_NAME":
+			fn = completeClusterNames(config)
+		case "PGADMIN_NAME":
+			fn = completePGAdminNames(config)
+		case "REPO_NAME":
+			fn = completeRepoNames(config)
+		case "USER_NAME":
+			fn = completeUserNames(config)
+		default:
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return fn.complete(cmd, args, toComplete)
+	}
+}
+
+// listNames returns the names of the objects of client in namespace.
+func listNames(ctx context.Context, client dynamic.NamespaceableResourceInterface, namespace string) ([]string, error) {
+	list, err := client.Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	return names, nil
+}
+
+// completeClusterNames completes the names of PostgresClusters in the namespace.
+func completeClusterNames(config *internal.Config) completer {
+	return func(ctx context.Context, _ *cobra.Command, _ []string) ([]string, error) {
+		_, client, err := v1beta1.NewPostgresClusterClient(config)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := config.Namespace()
+		if err != nil {
+			return nil, err
+		}
+		return listNames(ctx, client, namespace)
+	}
+}
+
+// completePGAdminNames completes the names of PGAdmins in the namespace.
+func completePGAdminNames(config *internal.Config) completer {
+	return func(ctx context.Context, _ *cobra.Command, _ []string) ([]string, error) {
+		_, client, err := v1beta1.NewPgadminClient(config)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := config.Namespace()
+		if err != nil {
+			return nil, err
+		}
+		return listNames(ctx, client, namespace)
+	}
+}
+
+// completeCluster completes from the PostgresCluster in the first argument.
+func completeCluster(config *internal.Config,
+	names func(cluster *unstructured.Unstructured) []string,
+) completer {
+	return func(ctx context.Context, _ *cobra.Command, args []string) ([]string, error) {
+		if len(args) == 0 {
+			return nil, nil
+		}
+		_, client, err := v1beta1.NewPostgresClusterClient(config)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := config.Namespace()
+		if err != nil {
+			return nil, err
+		}
+		cluster, err := client.Namespace(namespace).Get(ctx, args[0], metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return names(cluster), nil
+	}
+}
+
+// repoNames returns the names in "spec.backups.pgbackrest.repos" of cluster.
+func repoNames(cluster *unstructured.Unstructured) []string {
+	var names []string
+	for _, repo := range clusterRepos(cluster) {
+		if name, ok := repo["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// instanceSetNames returns the names in "spec.instances" of cluster.
+func instanceSetNames(cluster *unstructured.Unstructured) []string {
+	sets, _, _ := unstructured.NestedSlice(cluster.Object, "spec", "instances")
+
+	var names []string
+	for _, set := range sets {
+		if name, ok := asMap(set)["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// completeRepoNames completes the repositories of the PostgresCluster in the
+// first argument.
+func completeRepoNames(config *internal.Config) completer {
+	return completeCluster(config, repoNames)
+}
+
+// completeInstanceSets completes the instance sets of the PostgresCluster in
+// the first argument.
+func completeInstanceSets(config *internal.Config) completer {
+	return completeCluster(config, instanceSetNames)
+}
+
+// completeCore completes using the core API in the namespace.
+func completeCore(config *internal.Config,
+	names func(ctx context.Context, core corev1client.CoreV1Interface, namespace string,
+		cmd *cobra.Command, args []string) ([]string, error),
+) completer {
+	return func(ctx context.Context, cmd *cobra.Command, args []string) ([]string, error) {
+		rest, err := config.ToRESTConfig()
+		if err != nil {
+			return nil, err
+		}
+		core, err := corev1client.NewForConfig(rest)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := config.Namespace()
+		if err != nil {
+			return nil, err
+		}
+		return names(ctx, core, namespace, cmd, args)
+	}
+}
+
+// listUserNames returns the Postgres users of cluster from their Secrets.
+func listUserNames(ctx context.Context, core corev1client.CoreV1Interface, namespace, cluster string) ([]string, error) {
+	secrets, err := core.Secrets(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.PostgresUserSecretLabels(cluster),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, secret := range secrets.Items {
+		if name := secret.Labels[util.LabelPostgresUser]; name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// listPodNames returns the names of Pods that match selector.
+func listPodNames(ctx context.Context, core corev1client.CoreV1Interface, namespace, selector string) ([]string, error) {
+	pods, err := core.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Name)
+	}
+	return names, nil
+}
+
+// completeUserNames completes the Postgres users of the --cluster flag.
+func completeUserNames(config *internal.Config) completer {
+	return completeCore(config, func(ctx context.Context, core corev1client.CoreV1Interface, namespace string,
+		cmd *cobra.Command, _ []string) ([]string, error) {
+		cluster, _ := cmd.Flags().GetString("cluster")
+		if cluster == "" {
+			return nil, nil
+		}
+		return listUserNames(ctx, core, namespace, cluster)
+	})
+}
+
+// completeClusterPods completes the Pods of the PostgresCluster in the first
+// argument.
+func completeClusterPods(config *internal.Config) completer {
+	return completeCore(config, func(ctx context.Context, core corev1client.CoreV1Interface, namespace string,
+		_ *cobra.Command, args []string) ([]string, error) {
+		if len(args) == 0 {
+			return nil, nil
+		}
+		return listPodNames(ctx, core, namespace, util.LabelCluster+"="+args[0])
+	})
+}
+
+// completeNamespaces completes the names of namespaces.
+func completeNamespaces(config *internal.Config) completer {
+	return completeCore(config, func(ctx context.Context, core corev1client.CoreV1Interface, _ string,
+		_ *cobra.Command, _ []string) ([]string, error) {
+		list, err := core.Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		var names []string
+		for _, namespace := range list.Items {
+			names = append(names, namespace.Name)
+		}
+		return names, nil
+	})
+}
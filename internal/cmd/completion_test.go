@@ -0,0 +1,142 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestUsagePlaceholders(t *testing.T) {
+	assert.Assert(t, usagePlaceholders("backup") == nil)
+	assert.DeepEqual(t, usagePlaceholders("restore CLUSTER_NAME"), []string{"CLUSTER_NAME"})
+	assert.DeepEqual(t, usagePlaceholders("set CLUSTER_NAME --repo REPO_NAME"), []string{"CLUSTER_NAME"})
+	assert.DeepEqual(t, usagePlaceholders("remove CLUSTER_NAME REPO_NAME"), []string{"CLUSTER_NAME", "REPO_NAME"})
+	assert.DeepEqual(t, usagePlaceholders("user USER_NAME --cluster CLUSTER_NAME"), []string{"USER_NAME"})
+}
+
+func TestCompleterComplete(t *testing.T) {
+	names := completer(func(ctx context.Context, _ *cobra.Command, args []string) ([]string, error) {
+		_, ok := ctx.Deadline()
+		assert.Assert(t, ok, "expected a timeout")
+		return []string{"hippo", "hippo-dr", "rhino"}, nil
+	})
+
+	matches, directive := names.complete(nil, nil, "hi")
+	assert.DeepEqual(t, matches, []string{"hippo", "hippo-dr"})
+	assert.Equal(t, directive, cobra.ShellCompDirectiveNoFileComp)
+
+	failing := completer(func(context.Context, *cobra.Command, []string) ([]string, error) {
+		return nil, errors.New("connection refused")
+	})
+	matches, directive = failing.complete(nil, nil, "")
+	assert.Assert(t, matches == nil)
+	assert.Equal(t, directive, cobra.ShellCompDirectiveNoFileComp)
+
+	// Completion returns when the timeout passes, even when fn does not.
+	blocked := make(chan struct{})
+	defer close(blocked)
+	stuck := completer(func(context.Context, *cobra.Command, []string) ([]string, error) {
+		<-blocked
+		return []string{"hippo"}, nil
+	})
+	started := time.Now()
+	matches, directive = stuck.complete(nil, nil, "")
+	assert.Assert(t, matches == nil)
+	assert.Equal(t, directive, cobra.ShellCompDirectiveNoFileComp)
+	assert.Assert(t, time.Since(started) < 2*completionTimeout)
+}
+
+func TestClusterNames(t *testing.T) {
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  instances:
+  - name: big
+  - name: small
+  backups:
+    pgbackrest:
+      repos:
+      - name: repo1
+      - name: repo3
+`), &cluster.Object))
+
+	assert.DeepEqual(t, repoNames(cluster), []string{"repo1", "repo3"})
+	assert.DeepEqual(t, instanceSetNames(cluster), []string{"big", "small"})
+}
+
+func TestListUserAndPodNames(t *testing.T) {
+	object := func(name string, labels map[string]string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: labels}
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: object("hippo-pguser-zoo", map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "hippo",
+			"postgres-operator.crunchydata.com/role":    "pguser",
+			"postgres-operator.crunchydata.com/pguser":  "zoo",
+		})},
+		&corev1.Secret{ObjectMeta: object("hippo-pguser-app", map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "hippo",
+			"postgres-operator.crunchydata.com/role":    "pguser",
+			"postgres-operator.crunchydata.com/pguser":  "app",
+		})},
+		&corev1.Secret{ObjectMeta: object("rhino-pguser-rhino", map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "rhino",
+			"postgres-operator.crunchydata.com/role":    "pguser",
+			"postgres-operator.crunchydata.com/pguser":  "rhino",
+		})},
+		&corev1.Pod{ObjectMeta: object("hippo-instance1-abcd-0", map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "hippo",
+		})},
+		&corev1.Pod{ObjectMeta: object("rhino-instance1-wxyz-0", map[string]string{
+			"postgres-operator.crunchydata.com/cluster": "rhino",
+		})},
+	)
+	ctx := context.Background()
+
+	users, err := listUserNames(ctx, client.CoreV1(), "ns", "hippo")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, users, []string{"app", "zoo"})
+
+	pods, err := listPodNames(ctx, client.CoreV1(), "ns", "postgres-operator.crunchydata.com/cluster=hippo")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pods, []string{"hippo-instance1-abcd-0"})
+}
+
+func TestRegisterCompletions(t *testing.T) {
+	var out bytes.Buffer
+	root := NewPGOCommand(strings.NewReader(""), &out, &out)
+
+	find := func(args ...string) *cobra.Command {
+		cmd, _, err := root.Find(args)
+		assert.NilError(t, err)
+		return cmd
+	}
+
+	assert.Assert(t, find("backup").ValidArgsFunction != nil)
+	assert.Assert(t, find("restore").ValidArgsFunction != nil)
+	assert.Assert(t, find("show", "user").ValidArgsFunction != nil)
+	assert.Assert(t, find("repo", "remove").ValidArgsFunction != nil)
+	assert.Assert(t, find("create", "postgrescluster").ValidArgsFunction == nil,
+		"expected no completion of new names")
+	assert.Assert(t, find("create").ValidArgsFunction == nil)
+
+	// Add commands complete the cluster but not the name of what they add.
+	assert.DeepEqual(t, existingPlaceholders(find("repo", "add")), []string{"CLUSTER_NAME"})
+	assert.DeepEqual(t, existingPlaceholders(find("repo", "remove")), []string{"CLUSTER_NAME", "REPO_NAME"})
+	assert.Assert(t, existingPlaceholders(find("standby", "create")) == nil)
+}
@@ -0,0 +1,559 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newConfigCommand returns the config subcommand of the PGO plugin. Subcommands
+// of config show and change the Postgres parameters of a PostgresCluster.
+func newConfigCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show or change the Postgres parameters of a PostgresCluster",
+		Long: `Show or change the Postgres parameters of a PostgresCluster.
+
+Parameters are read from the primary instance. Changes are sent using
+server-side apply, so only parameters set by this command can be changed by it.
+Overwriting parameters owned by another client may require the
+--force-conflicts flag.`,
+	}
+
+	cmd.AddCommand(
+		newConfigShowCommand(config),
+		newConfigSetCommand(config),
+	)
+
+	return cmd
+}
+
+func newConfigShowCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show CLUSTER_NAME",
+		Short: "Show the Postgres parameters of a PostgresCluster",
+		Long: `Show the Postgres parameters of a PostgresCluster and where each comes from.
+
+Parameters in "spec.config.parameters" or "spec.patroni.dynamicConfiguration"
+are reported as such. Parameters that PGO sets through Patroni are reported as
+"patroni". Others are reported with their source in "pg_settings". By default,
+parameters at their built-in defaults are not shown.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Show the Postgres parameters of the 'hippo' postgrescluster
+pgo config show hippo
+
+# Show every Postgres parameter as JSON
+pgo config show hippo --all --output json
+
+### Example output
+NAME                         SETTING   UNIT   SOURCE                              PENDING RESTART
+archive_mode                 on               patroni                             false
+log_min_duration_statement   1000      ms     spec.patroni.dynamicConfiguration   false
+max_connections              100              spec.config.parameters              true
+shared_buffers               16384     8kB    configuration file                  false`)
+
+	show := pgConfig{Config: config}
+
+	cmd.Flags().BoolVar(&show.All, "all", false, "also show parameters at their built-in defaults")
+
+	var outputEnum = util.TextOutput
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"output format. types supported: text,json")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		show.ClusterName = args[0]
+		show.JSON = outputEnum == util.JSONOutput
+		return show.Show(context.Background())
+	}
+
+	return cmd
+}
+
+func newConfigSetCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set CLUSTER_NAME NAME=VALUE...",
+		Short: "Change Postgres parameters of a PostgresCluster",
+		Long: `Change Postgres parameters of a PostgresCluster.
+
+Each parameter is checked against "pg_settings" on the primary instance before
+any are changed: its name must exist and its value must have the right type
+and be within its minimum, maximum, or allowed values.
+
+Parameters are set in "spec.config.parameters" when the PostgresCluster already
+uses that field and in "spec.patroni.dynamicConfiguration" otherwise. When a
+parameter only takes effect after a restart, you are asked whether to restart
+the PostgresCluster. Use --restart to restart without asking.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Log statements that take longer than one second
+pgo config set hippo log_min_duration_statement=1s
+
+# Allow more connections and restart Postgres for them to take effect
+pgo config set hippo max_connections=200 --restart
+
+### Example output
+postgresclusters/hippo config updated
+WARNING: max_connections requires a restart to take effect.
+postgresclusters/hippo restart initiated`)
+
+	set := pgConfig{Config: config}
+
+	cmd.Flags().BoolVar(&set.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the parameters")
+	cmd.Flags().BoolVar(&set.Restart, "restart", false, "restart without asking when a parameter requires it")
+
+	cmd.Args = cobra.MinimumNArgs(2)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		set.ClusterName = args[0]
+		parameters, err := parseParameterArgs(args[1:])
+		if err != nil {
+			return err
+		}
+		return set.Set(context.Background(), parameters)
+	}
+
+	return cmd
+}
+
+type pgConfig struct {
+	*internal.Config
+
+	All            bool
+	ClusterName    string
+	ForceConflicts bool
+	JSON           bool
+	Restart        bool
+}
+
+// pgSetting is a row of the pg_settings view.
+type pgSetting struct {
+	Name           string   `json:"name"`
+	Setting        string   `json:"setting"`
+	Unit           string   `json:"unit"`
+	Context        string   `json:"context"`
+	VarType        string   `json:"vartype"`
+	Source         string   `json:"source"`
+	MinVal         string   `json:"min_val"`
+	MaxVal         string   `json:"max_val"`
+	EnumVals       []string `json:"enumvals"`
+	PendingRestart bool     `json:"pending_restart"`
+}
+
+// configParameter is a parameter as printed by "pgo config show".
+type configParameter struct {
+	Name           string `json:"name"`
+	Setting        string `json:"setting"`
+	Unit           string `json:"unit,omitempty"`
+	Source         string `json:"source"`
+	PendingRestart bool   `json:"pendingRestart"`
+}
+
+// parameter is a NAME=VALUE pair passed to "pgo config set".
+type parameter struct {
+	Name, Value string
+}
+
+// parseParameterArgs returns the NAME=VALUE pairs of args in order.
+func parseParameterArgs(args []string) ([]parameter, error) {
+	var parameters []parameter
+	for _, arg := range args {
+		name, value, found := strings.Cut(arg, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid parameter %q: expected NAME=VALUE", arg)
+		}
+		parameters = append(parameters, parameter{Name: name, Value: value})
+	}
+	return parameters, nil
+}
+
+// parsePGSettings reads the JSON output of [Executor.pgSettings].
+func parsePGSettings(stdout string) ([]pgSetting, error) {
+	var settings []pgSetting
+	err := json.Unmarshal([]byte(strings.TrimSpace(stdout)), &settings)
+	return settings, err
+}
+
+// parameterSources returns a function that names where the value of a
+// parameter comes from. The spec of cluster takes precedence over the Patroni
+// configuration in patroniConfig, which takes precedence over pg_settings.
+func parameterSources(cluster *unstructured.Unstructured, patroniConfig string) func(pgSetting) string {
+	specConfig, _, _ := unstructured.NestedMap(cluster.Object,
+		"spec", "config", "parameters")
+	specPatroni, _, _ := unstructured.NestedMap(cluster.Object,
+		"spec", "patroni", "dynamicConfiguration", "postgresql", "parameters")
+
+	var patroni struct {
+		PostgreSQL struct {
+			Parameters map[string]any `json:"parameters"`
+		} `json:"postgresql"`
+	}
+	_ = yaml.Unmarshal([]byte(patroniConfig), &patroni)
+
+	return func(setting pgSetting) string {
+		if _, ok := specConfig[setting.Name]; ok {
+			return "spec.config.parameters"
+		}
+		if _, ok := specPatroni[setting.Name]; ok {
+			return "spec.patroni.dynamicConfiguration"
+		}
+		if _, ok := patroni.PostgreSQL.Parameters[setting.Name]; ok {
+			return "patroni"
+		}
+		return setting.Source
+	}
+}
+
+// configParameters returns the parameters in settings to show. Parameters at
+// their built-in defaults are left out unless all is true.
+func configParameters(settings []pgSetting, source func(pgSetting) string, all bool) []configParameter {
+	var parameters []configParameter
+	for _, setting := range settings {
+		if s := source(setting); all || s != "default" {
+			parameters = append(parameters, configParameter{
+				Name:           setting.Name,
+				Setting:        setting.Setting,
+				Unit:           setting.Unit,
+				Source:         s,
+				PendingRestart: setting.PendingRestart,
+			})
+		}
+	}
+	return parameters
+}
+
+// printParameters writes parameters to w as a table or as JSON.
+func printParameters(w io.Writer, parameters []configParameter, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if parameters == nil {
+			parameters = []configParameter{}
+		}
+		return encoder.Encode(parameters)
+	}
+
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "NAME\tSETTING\tUNIT\tSOURCE\tPENDING RESTART\n"); err != nil {
+		return err
+	}
+	for _, parameter := range parameters {
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\t%t\n",
+			parameter.Name, parameter.Setting, parameter.Unit,
+			parameter.Source, parameter.PendingRestart,
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// numericValue matches a number followed by an optional unit.
+var numericValue = regexp.MustCompile(`^\s*([-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?)\s*([a-zA-Z]*)\s*$`)
+
+// unitFactors are the multiples of bytes and microseconds that Postgres
+// accepts as units of memory and time parameters.
+var unitFactors = map[string]struct {
+	Kind   string
+	Factor float64
+}{
+	"B": {"memory", 1}, "kB": {"memory", 1 << 10}, "MB": {"memory", 1 << 20},
+	"GB": {"memory", 1 << 30}, "TB": {"memory", 1 << 40},
+
+	"us": {"time", 1}, "ms": {"time", 1e3}, "s": {"time", 1e6},
+	"min": {"time", 60e6}, "h": {"time", 3600e6}, "d": {"time", 86400e6},
+}
+
+// settingUnit returns the kind and size of the unit of setting, such as
+// "memory" and 8192 for "8kB".
+func settingUnit(unit string) (string, float64, bool) {
+	count := 1.0
+	if i := strings.IndexFunc(unit, func(r rune) bool { return r < '0' || r > '9' }); i > 0 {
+		count, _ = strconv.ParseFloat(unit[:i], 64)
+		unit = unit[i:]
+	}
+	u, ok := unitFactors[unit]
+	return u.Kind, count * u.Factor, ok
+}
+
+// validateParameter returns an error when value is not valid for setting.
+func validateParameter(setting pgSetting, value string) error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("invalid value for parameter %q: %q: %s",
+			setting.Name, value, fmt.Sprintf(format, args...))
+	}
+
+	switch setting.Context {
+	case "internal":
+		return fmt.Errorf("parameter %q cannot be changed", setting.Name)
+	}
+
+	switch setting.VarType {
+	case "bool":
+		if !slices.Contains([]string{"on", "off", "true", "false", "yes", "no", "1", "0"},
+			strings.ToLower(strings.TrimSpace(value))) {
+			return invalid(`expected "on" or "off"`)
+		}
+
+	case "enum":
+		if !slices.ContainsFunc(setting.EnumVals, func(v string) bool {
+			return strings.EqualFold(v, strings.TrimSpace(value))
+		}) {
+			return invalid("expected one of %s", strings.Join(setting.EnumVals, ", "))
+		}
+
+	case "integer", "real":
+		match := numericValue.FindStringSubmatch(value)
+		if match == nil {
+			return invalid("expected a number")
+		}
+		number, _ := strconv.ParseFloat(match[1], 64)
+
+		if unit := match[2]; unit != "" {
+			kind, size, ok := settingUnit(setting.Unit)
+			given, known := unitFactors[unit]
+			if !ok {
+				return invalid("parameter does not accept units")
+			}
+			if !known || given.Kind != kind {
+				return invalid("valid units are %s", strings.Join(unitNames(kind), ", "))
+			}
+			number = number * given.Factor / size
+		}
+		if setting.VarType == "integer" {
+			number = math.Round(number)
+		}
+
+		if min, err := strconv.ParseFloat(setting.MinVal, 64); err == nil && number < min {
+			return invalid("below the minimum of %s", withUnit(setting.MinVal, setting.Unit))
+		}
+		if max, err := strconv.ParseFloat(setting.MaxVal, 64); err == nil && number > max {
+			return invalid("above the maximum of %s", withUnit(setting.MaxVal, setting.Unit))
+		}
+	}
+
+	return nil
+}
+
+// withUnit returns value followed by unit, such as "30s" or "16 (8kB)".
+func withUnit(value, unit string) string {
+	if unit != "" && unit[0] >= '0' && unit[0] <= '9' {
+		return value + " (" + unit + ")"
+	}
+	return value + unit
+}
+
+// unitNames returns the names of units of kind in increasing size.
+func unitNames(kind string) []string {
+	var names []string
+	for name, u := range unitFactors {
+		if u.Kind == kind {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Compare(unitFactors[a].Factor, unitFactors[b].Factor)
+	})
+	return names
+}
+
+// validateParameters checks parameters against settings. It returns the names
+// of parameters that require a restart to take effect.
+func validateParameters(settings []pgSetting, parameters []parameter) ([]string, error) {
+	var restart []string
+	for _, p := range parameters {
+		index := slices.IndexFunc(settings, func(s pgSetting) bool { return s.Name == p.Name })
+		if index < 0 {
+			// Postgres accepts any parameter with a dot in its name, such as
+			// those of extensions that are not loaded.
+			if strings.Contains(p.Name, ".") {
+				continue
+			}
+			return nil, fmt.Errorf("unrecognized parameter %q", p.Name)
+		}
+		if err := validateParameter(settings[index], p.Value); err != nil {
+			return nil, err
+		}
+		if settings[index].Context == "postmaster" {
+			restart = append(restart, p.Name)
+		}
+	}
+	return restart, nil
+}
+
+// setIntent sets parameters in intent. They go in "spec.config.parameters"
+// when cluster already has that field.
+func (config pgConfig) setIntent(intent, cluster *unstructured.Unstructured, parameters []parameter) error {
+	fields := []string{"spec", "patroni", "dynamicConfiguration", "postgresql", "parameters"}
+	if _, found, _ := unstructured.NestedMap(cluster.Object, "spec", "config", "parameters"); found {
+		fields = []string{"spec", "config", "parameters"}
+	}
+
+	for _, p := range parameters {
+		if err := unstructured.SetNestedField(intent.Object, p.Value, append(fields, p.Name)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settings returns the rows of pg_settings on the primary instance of the
+// cluster and an executor for further commands there.
+func (config pgConfig) settings() ([]pgSetting, Executor, error) {
+	exec, err := getPrimaryExec(config.Config, []string{config.ClusterName})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stdout, stderr, err := Executor(exec).pgSettings()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+
+	settings, err := parsePGSettings(stdout)
+	return settings, exec, err
+}
+
+// Show prints the parameters of the cluster and where they come from.
+func (config pgConfig) Show(ctx context.Context) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(config)
+	if err != nil {
+		return err
+	}
+	namespace, err := config.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, config.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	settings, exec, err := config.settings()
+	if err != nil {
+		return err
+	}
+
+	patroniConfig, stderr, err := exec.patronictl("show-config", "")
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+
+	return printParameters(config.Out, configParameters(settings,
+		parameterSources(cluster, patroniConfig), config.All), config.JSON)
+}
+
+// Set validates parameters on the primary instance of the cluster and then
+// applies them to its spec.
+func (config pgConfig) Set(ctx context.Context, parameters []parameter) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(config)
+	if err != nil {
+		return err
+	}
+	namespace, err := config.Namespace()
+	if err != nil {
+		return err
+	}
+
+	// Fetch the cluster to (1) see if it exists and (2) extract CLI managed fields.
+	cluster, err := client.Namespace(namespace).Get(ctx, config.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	settings, _, err := config.settings()
+	if err != nil {
+		return err
+	}
+	restart, err := validateParameters(settings, parameters)
+	if err != nil {
+		return err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, config.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := config.setIntent(intent, cluster, parameters); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, config.Config, client.Namespace(namespace), config.ClusterName,
+		intent, config.ForceConflicts); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(config.Out, "%s/%s config updated\n",
+		mapping.Resource.Resource, config.ClusterName)
+
+	if len(restart) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(config.Out, "WARNING: %s requires a restart to take effect.\n",
+		strings.Join(restart, ", "))
+
+	if !config.Restart {
+		if !confirm(config.In, config.Out, fmt.Sprintf("Restart %s/%s now? (yes/no): ",
+			mapping.Resource.Resource, config.ClusterName)) {
+			return nil
+		}
+	}
+
+	// Patroni may not have seen the new parameters yet, so restart the Pods.
+	return pgRestart{
+		Config:      config.Config,
+		ClusterName: config.ClusterName,
+		Rolling:     true,
+	}.Run(ctx)
+}
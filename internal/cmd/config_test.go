@@ -0,0 +1,196 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestParseParameterArgs(t *testing.T) {
+	parameters, err := parseParameterArgs([]string{"Work_Mem=8MB", "search_path=a,b", "empty="})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, parameters, []parameter{
+		{Name: "work_mem", Value: "8MB"},
+		{Name: "search_path", Value: "a,b"},
+		{Name: "empty", Value: ""},
+	})
+
+	_, err = parseParameterArgs([]string{"work_mem"})
+	assert.ErrorContains(t, err, "expected NAME=VALUE")
+
+	_, err = parseParameterArgs([]string{"=on"})
+	assert.ErrorContains(t, err, "expected NAME=VALUE")
+}
+
+func TestPGSettingsShow(t *testing.T) {
+	settings, err := parsePGSettings(`[
+  {"name":"archive_mode","setting":"on","unit":null,"context":"postmaster","vartype":"enum","source":"configuration file","enumvals":["always","on","off"],"pending_restart":false},
+  {"name":"log_min_duration_statement","setting":"1000","unit":"ms","context":"superuser","vartype":"integer","source":"configuration file","min_val":"-1","max_val":"2147483647","pending_restart":false},
+  {"name":"max_connections","setting":"100","unit":null,"context":"postmaster","vartype":"integer","source":"configuration file","min_val":"1","max_val":"262143","pending_restart":true},
+  {"name":"shared_buffers","setting":"16384","unit":"8kB","context":"postmaster","vartype":"integer","source":"configuration file","min_val":"16","max_val":"1073741823","pending_restart":false},
+  {"name":"work_mem","setting":"4096","unit":"kB","context":"user","vartype":"integer","source":"default","min_val":"64","max_val":"2147483647","pending_restart":false}
+]
+`)
+	assert.NilError(t, err)
+	assert.Equal(t, len(settings), 5)
+	assert.DeepEqual(t, settings[0].EnumVals, []string{"always", "on", "off"})
+
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  config:
+    parameters:
+      max_connections: 200
+  patroni:
+    dynamicConfiguration:
+      postgresql:
+        parameters:
+          log_min_duration_statement: 1s
+`), &cluster.Object))
+
+	source := parameterSources(cluster, `
+loop_wait: 10
+postgresql:
+  parameters:
+    archive_mode: 'on'
+    max_connections: 200
+`)
+
+	var buf bytes.Buffer
+	assert.NilError(t, printParameters(&buf, configParameters(settings, source, false), false))
+	assert.Equal(t, buf.String(), ``+
+		"NAME                         SETTING   UNIT   SOURCE                              PENDING RESTART\n"+
+		"archive_mode                 on               patroni                             false\n"+
+		"log_min_duration_statement   1000      ms     spec.patroni.dynamicConfiguration   false\n"+
+		"max_connections              100              spec.config.parameters              true\n"+
+		"shared_buffers               16384     8kB    configuration file                  false\n")
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NilError(t, printParameters(&buf, configParameters(settings[3:], source, true), true))
+		assert.Equal(t, buf.String(), `[
+  {
+    "name": "shared_buffers",
+    "setting": "16384",
+    "unit": "8kB",
+    "source": "configuration file",
+    "pendingRestart": false
+  },
+  {
+    "name": "work_mem",
+    "setting": "4096",
+    "unit": "kB",
+    "source": "default",
+    "pendingRestart": false
+  }
+]
+`)
+
+		buf.Reset()
+		assert.NilError(t, printParameters(&buf, nil, true))
+		assert.Equal(t, buf.String(), "[]\n")
+	})
+}
+
+func TestValidateParameter(t *testing.T) {
+	boolean := pgSetting{Name: "hot_standby", VarType: "bool"}
+	enum := pgSetting{Name: "wal_level", VarType: "enum", EnumVals: []string{"minimal", "replica", "logical"}}
+	memory := pgSetting{Name: "shared_buffers", VarType: "integer", Unit: "8kB", MinVal: "16", MaxVal: "1073741823"}
+	count := pgSetting{Name: "max_connections", VarType: "integer", MinVal: "1", MaxVal: "262143"}
+	duration := pgSetting{Name: "checkpoint_timeout", VarType: "integer", Unit: "s", MinVal: "30", MaxVal: "86400"}
+	fraction := pgSetting{Name: "checkpoint_completion_target", VarType: "real", MinVal: "0", MaxVal: "1"}
+
+	for _, tt := range []struct {
+		Setting pgSetting
+		Value   string
+		Error   string
+	}{
+		{Setting: boolean, Value: "ON"},
+		{Setting: boolean, Value: "maybe", Error: `expected "on" or "off"`},
+		{Setting: enum, Value: "Logical"},
+		{Setting: enum, Value: "archive", Error: "expected one of minimal, replica, logical"},
+		{Setting: memory, Value: "128MB"},
+		{Setting: memory, Value: "16384"},
+		{Setting: memory, Value: "64kB", Error: "below the minimum of 16 (8kB)"},
+		{Setting: memory, Value: "1min", Error: "valid units are B, kB, MB, GB, TB"},
+		{Setting: count, Value: "200"},
+		{Setting: count, Value: "0", Error: "below the minimum of 1"},
+		{Setting: count, Value: "200MB", Error: "does not accept units"},
+		{Setting: count, Value: "many", Error: "expected a number"},
+		{Setting: duration, Value: "5min"},
+		{Setting: duration, Value: "2d", Error: "above the maximum of 86400s"},
+		{Setting: fraction, Value: "0.9"},
+		{Setting: fraction, Value: "1.5", Error: "above the maximum of 1"},
+		{Setting: pgSetting{Name: "block_size", Context: "internal"}, Value: "16", Error: "cannot be changed"},
+		{Setting: pgSetting{Name: "application_name", VarType: "string"}, Value: "anything"},
+	} {
+		t.Run(tt.Setting.Name+"="+tt.Value, func(t *testing.T) {
+			err := validateParameter(tt.Setting, tt.Value)
+			if tt.Error == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.Error)
+			}
+		})
+	}
+}
+
+func TestValidateParameters(t *testing.T) {
+	settings := []pgSetting{
+		{Name: "max_connections", VarType: "integer", Context: "postmaster", MinVal: "1", MaxVal: "262143"},
+		{Name: "work_mem", VarType: "integer", Context: "user", Unit: "kB", MinVal: "64", MaxVal: "2147483647"},
+	}
+
+	restart, err := validateParameters(settings, []parameter{
+		{Name: "work_mem", Value: "8MB"},
+		{Name: "max_connections", Value: "200"},
+		{Name: "pgaudit.log", Value: "all"},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, restart, []string{"max_connections"})
+
+	_, err = validateParameters(settings, []parameter{{Name: "max_conections", Value: "200"}})
+	assert.ErrorContains(t, err, `unrecognized parameter "max_conections"`)
+
+	_, err = validateParameters(settings, []parameter{{Name: "work_mem", Value: "1kB"}})
+	assert.ErrorContains(t, err, "below the minimum")
+}
+
+func TestPGConfigSetIntent(t *testing.T) {
+	parameters := []parameter{{Name: "work_mem", Value: "8MB"}}
+
+	cluster := new(unstructured.Unstructured)
+	intent := &unstructured.Unstructured{Object: map[string]any{}}
+	assert.NilError(t, pgConfig{}.setIntent(intent, cluster, parameters))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  patroni:
+    dynamicConfiguration:
+      postgresql:
+        parameters:
+          work_mem: 8MB
+	`))
+
+	t.Run("ConfigParameters", func(t *testing.T) {
+		cluster.Object = map[string]any{"spec": map[string]any{
+			"config": map[string]any{"parameters": map[string]any{"max_connections": int64(200)}},
+		}}
+		intent := &unstructured.Unstructured{Object: map[string]any{}}
+		assert.NilError(t, pgConfig{}.setIntent(intent, cluster, parameters))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  config:
+    parameters:
+      work_mem: 8MB
+		`))
+	})
+}
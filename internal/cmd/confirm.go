@@ -0,0 +1,26 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// confirm writes prompt to out and reads an answer from in. It returns true
+// only when the answer is yes.
+func confirm(in io.Reader, out io.Writer, prompt string) bool {
+	_, _ = fmt.Fprint(out, prompt)
+
+	var confirmed *bool
+	for i := 0; confirmed == nil && i < 10; i++ {
+		// retry 10 times or until a confirmation is given or denied,
+		// whichever comes first
+		confirmed = util.Confirm(in, out)
+	}
+	return confirmed != nil && *confirmed
+}
@@ -0,0 +1,27 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	var out bytes.Buffer
+	assert.Assert(t, confirm(strings.NewReader("yes\n"), &out, "Continue? (yes/no): "))
+	assert.Equal(t, out.String(), "Continue? (yes/no): ")
+
+	out.Reset()
+	assert.Assert(t, !confirm(strings.NewReader("no\n"), &out, "Continue? (yes/no): "))
+
+	// No answer is the same as no.
+	out.Reset()
+	assert.Assert(t, !confirm(strings.NewReader(""), &out, "Continue? (yes/no): "))
+	assert.Equal(t, strings.Count(out.String(), "Please type yes or no"), 10)
+}
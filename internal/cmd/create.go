@@ -7,6 +7,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 
@@ -14,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/printers"
 
 	"github.com/crunchydata/postgres-operator-client/internal"
 	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
@@ -30,6 +32,8 @@ func newCreateCommand(config *internal.Config) *cobra.Command {
 	}
 
 	cmd.AddCommand(newCreateClusterCommand(config))
+	cmd.AddCommand(newCreateProfileCommand(config))
+	cmd.AddCommand(newCreatePgadminCommand(config))
 
 	return cmd
 }
@@ -47,8 +51,23 @@ func newCreateClusterCommand(config *internal.Config) *cobra.Command {
 ### RBAC Requirements
     Resources                                           Verbs
     ---------                                           -----
+    configmaps                                          [list]
     postgresclusters.postgres-operator.crunchydata.com  [create]
 
+    Note: ConfigMaps are listed only when a profile is not found in the profile directory.
+
+### Profiles
+    With --profile, a YAML partial of a PostgresCluster is merged over the
+    basic PostgresCluster. Objects are merged recursively; lists and other
+    values replace those of the basic PostgresCluster, and null removes them.
+    See "pgo create profile --help" for where profiles are found.
+
+### Dry Run
+    With --dry-run=client, the PostgresCluster is printed and nothing is sent
+    to Kubernetes. With --dry-run=server, the request is validated and
+    defaulted by Kubernetes and any admission webhooks, but nothing is persisted.
+    Use --output to see the resulting object.
+
 ### Usage`,
 	}
 
@@ -61,6 +80,22 @@ func newCreateClusterCommand(config *internal.Config) *cobra.Command {
 	var backupsDisabled bool
 	cmd.Flags().BoolVar(&backupsDisabled, "disable-backups", false, "Disable backups")
 
+	var profileName string
+	cmd.Flags().StringVar(&profileName, "profile", "",
+		"Merge the named profile over the basic PostgresCluster")
+
+	var profiles profileFlags
+	profiles.AddFlags(cmd)
+
+	var dryRun = util.DryRunNone
+	cmd.Flags().Var(&dryRun, "dry-run",
+		`Must be "none", "client", or "server". If client, only print the object that would be sent. `+
+			`If server, submit the request without persisting the object.`)
+
+	var outputEnum = util.NoObject
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"Print the created object. types supported: yaml,json")
+
 	cmd.Example = internal.FormatExample(`# Create a postgrescluster with Postgres 15
 pgo create postgrescluster hippo --pg-major-version 15
 
@@ -68,6 +103,16 @@ pgo create postgrescluster hippo --pg-major-version 15
 # Requires confirmation
 pgo create postgrescluster hippo --disable-backups
 
+# Create a postgrescluster using the 'prod-ha' profile
+pgo create postgrescluster hippo --pg-major-version 15 --profile prod-ha
+
+# Print the postgrescluster manifest without sending it to Kubernetes
+pgo create postgrescluster hippo --pg-major-version 15 --dry-run=client -o yaml
+
+# Print the postgrescluster as defaulted and validated by Kubernetes and PGO,
+# without persisting it
+pgo create postgrescluster hippo --pg-major-version 15 --dry-run=server -o yaml
+
 ### Example output	
 postgresclusters/hippo created`)
 
@@ -81,7 +126,7 @@ postgresclusters/hippo created`)
 			return err
 		}
 
-		mapping, client, err := v1beta1.NewPostgresClusterClient(config)
+		mapping, _, err := v1beta1.NewPostgresClusterClient(config)
 		if err != nil {
 			return err
 		}
@@ -91,7 +136,34 @@ postgresclusters/hippo created`)
 			return err
 		}
 
-		if backupsDisabled {
+		if profileName != "" {
+			profile, err := profiles.find(ctx, config, profileName)
+			if err != nil {
+				return err
+			}
+
+			partial, err := profile.render(profileValues{
+				Name:      clusterName,
+				Namespace: namespace,
+				Version:   pgMajorVersion,
+			})
+			if err != nil {
+				return err
+			}
+
+			mergeProfile(cluster.Object, partial)
+			cluster.SetName(clusterName)
+		}
+
+		if backupsDisabled && dryRun != util.DryRunNone {
+			// Nothing is persisted during a dry run, so there is nothing to
+			// confirm. Keep stdout clean for the printed object.
+			cmd.PrintErrln("WARNING: Running a production postgrescluster without backups " +
+				"is not recommended.")
+
+			unstructured.RemoveNestedField(cluster.Object, "spec", "backups")
+
+		} else if backupsDisabled {
 			fmt.Print("WARNING: Running a production postgrescluster without backups " +
 				"is not recommended. \nAre you sure you want " +
 				"to continue without backups? (yes/no): ")
@@ -109,14 +181,34 @@ postgresclusters/hippo created`)
 			unstructured.RemoveNestedField(cluster.Object, "spec", "backups")
 		}
 
-		u, err := client.
-			Namespace(namespace).
-			Create(ctx, cluster, config.Patch.CreateOptions(metav1.CreateOptions{}))
+		// A client dry run prints the object exactly as it would be sent.
+		if dryRun == util.DryRunClient {
+			if outputEnum != util.NoObject {
+				return printObject(cmd.OutOrStdout(), cluster, outputEnum.String())
+			}
+			cmd.Printf("%s/%s created (client dry run)\n", mapping.Resource.Resource, cluster.GetName())
+			return nil
+		}
+
+		createOptions := metav1.CreateOptions{}
+		if dryRun == util.DryRunServer {
+			createOptions.DryRun = []string{metav1.DryRunAll}
+		}
+
+		u, err := createPostgresCluster(ctx, config, namespace, cluster, createOptions)
 		if err != nil {
 			return err
 		}
 
-		cmd.Printf("%s/%s created\n", mapping.Resource.Resource, u.GetName())
+		if outputEnum != util.NoObject {
+			return printObject(cmd.OutOrStdout(), u, outputEnum.String())
+		}
+
+		if dryRun == util.DryRunServer {
+			cmd.Printf("%s/%s created (server dry run)\n", mapping.Resource.Resource, u.GetName())
+		} else {
+			cmd.Printf("%s/%s created\n", mapping.Resource.Resource, u.GetName())
+		}
 
 		return nil
 	}
@@ -124,6 +216,34 @@ postgresclusters/hippo created`)
 	return cmd
 }
 
+// createPostgresCluster creates cluster in namespace with the field manager of
+// config.
+func createPostgresCluster(ctx context.Context, config *internal.Config,
+	namespace string, cluster *unstructured.Unstructured, options metav1.CreateOptions,
+) (*unstructured.Unstructured, error) {
+	_, client, err := v1beta1.NewPostgresClusterClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.
+		Namespace(namespace).
+		Create(ctx, cluster, config.Patch.CreateOptions(options))
+}
+
+// printObject writes object to w as YAML or JSON. Managed fields are omitted
+// the same as they are by 'kubectl get --output'.
+func printObject(w io.Writer, object *unstructured.Unstructured, output string) error {
+	var printer printers.ResourcePrinter = &printers.YAMLPrinter{}
+	if output == "json" {
+		printer = &printers.JSONPrinter{}
+	}
+
+	printer = &printers.OmitManagedFieldsPrinter{Delegate: printer}
+
+	return printer.PrintObj(object, w)
+}
+
 // generateUnstructuredClusterYaml takes a name and returns a PostgresCluster
 // in the unstructured format.
 func generateUnstructuredClusterYaml(name, pgMajorVersion string) (*unstructured.Unstructured, error) {
@@ -161,3 +281,44 @@ spec:
 
 	return &cluster, nil
 }
+
+// generateClusterLike returns a PostgresCluster named name that is built the
+// same as "pgo create postgrescluster" with the Postgres version, images, and
+// data volume of source.
+func generateClusterLike(source *unstructured.Unstructured, name string) (*unstructured.Unstructured, error) {
+	version, found, err := unstructured.NestedFieldNoCopy(source.Object, "spec", "postgresVersion")
+	if err != nil || !found {
+		return nil, fmt.Errorf("unable to read the Postgres version of %s", source.GetName())
+	}
+
+	cluster, err := generateUnstructuredClusterYaml(name, fmt.Sprint(version))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range [][]string{
+		{"spec", "image"},
+		{"spec", "imagePullSecrets"},
+		{"spec", "postGISVersion"},
+		{"spec", "backups", "pgbackrest", "image"},
+	} {
+		if value, found, _ := unstructured.NestedFieldCopy(source.Object, path...); found {
+			if err := unstructured.SetNestedField(cluster.Object, value, path...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// The data volume must be large enough to hold the data of source.
+	instances, _, _ := unstructured.NestedSlice(source.Object, "spec", "instances")
+	if len(instances) > 0 {
+		if claim, found, _ := unstructured.NestedMap(asMap(instances[0]), "dataVolumeClaimSpec"); found {
+			generated, _, _ := unstructured.NestedSlice(cluster.Object, "spec", "instances")
+			generated[0].(map[string]any)["dataVolumeClaimSpec"] = claim
+			if err := unstructured.SetNestedSlice(cluster.Object, generated, "spec", "instances"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cluster, nil
+}
@@ -5,9 +5,13 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
 )
@@ -49,3 +53,32 @@ spec:
 	))
 
 }
+
+func TestPrintObject(t *testing.T) {
+	u, err := generateUnstructuredClusterYaml("hippo", "15")
+	assert.NilError(t, err)
+
+	u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl-pgo"}})
+
+	t.Run("YAML", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NilError(t, printObject(&buf, u, "yaml"))
+
+		assert.Assert(t, strings.HasPrefix(buf.String(), "apiVersion: postgres-operator.crunchydata.com/v1beta1\n"))
+		assert.Assert(t, strings.Contains(buf.String(), "postgresVersion: 15\n"))
+		assert.Assert(t, !strings.Contains(buf.String(), "managedFields"))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NilError(t, printObject(&buf, u, "json"))
+
+		var printed map[string]any
+		assert.NilError(t, json.Unmarshal(buf.Bytes(), &printed))
+		assert.Equal(t, printed["kind"], "PostgresCluster")
+		assert.Assert(t, !strings.Contains(buf.String(), "managedFields"))
+	})
+
+	// The original object is not modified.
+	assert.Equal(t, len(u.GetManagedFields()), 1)
+}
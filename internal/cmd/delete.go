@@ -27,6 +27,7 @@ func newDeleteCommand(config *internal.Config) *cobra.Command {
 	}
 
 	cmd.AddCommand(newDeleteClusterCommand(config))
+	cmd.AddCommand(newDeletePgadminCommand(config))
 
 	return cmd
 }
@@ -81,22 +82,30 @@ postgresclusters/hippo deleted`)
 			return err
 		}
 
-		mapping, client, err := v1beta1.NewPostgresClusterClient(config)
+		resource, err := deletePostgresCluster(ctx, config, namespace, clusterName)
 		if err != nil {
 			return err
 		}
 
-		err = client.
-			Namespace(namespace).
-			Delete(ctx, clusterName, metav1.DeleteOptions{})
-		if err != nil {
-			return err
-		}
-
-		cmd.Printf("%s/%s deleted\n", mapping.Resource.Resource, clusterName)
+		cmd.Printf("%s/%s deleted\n", resource, clusterName)
 
 		return nil
 	}
 
 	return cmd
 }
+
+// deletePostgresCluster deletes the PostgresCluster named clusterName in
+// namespace. It returns the name of the resource for messages.
+func deletePostgresCluster(ctx context.Context, config *internal.Config,
+	namespace, clusterName string,
+) (string, error) {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(config)
+	if err != nil {
+		return "", err
+	}
+
+	return mapping.Resource.Resource, client.
+		Namespace(namespace).
+		Delete(ctx, clusterName, metav1.DeleteOptions{})
+}
@@ -0,0 +1,462 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newEventsCommand returns the events command of the PGO plugin. It prints
+// the Events of a PostgresCluster and the objects it owns.
+func newEventsCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "events CLUSTER_NAME",
+		Short: "Show the events of a PostgresCluster",
+		Long: `Show the Events of a PostgresCluster and the objects that belong to it,
+oldest first. Objects belong to a PostgresCluster when they have its cluster
+label or are owned, directly or indirectly, by the PostgresCluster or one of
+those objects.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    cronjobs.batch                                      [list]
+    deployments.apps                                    [list]
+    events                                              [list watch]
+    jobs.batch                                          [list]
+    persistentvolumeclaims                              [list]
+    pods                                                [list]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+    replicasets.apps                                    [list]
+    services                                            [list]
+    statefulsets.apps                                   [list]
+
+    Note: Objects that cannot be listed are left out.
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Show the events of the 'hippo' postgrescluster
+pgo events hippo
+
+# Show new warnings of the 'hippo' postgrescluster as they happen
+pgo events hippo --types Warning --watch
+
+# Show the events of the 'hippo' postgrescluster as JSON
+pgo events hippo --output json
+
+### Example output
+LAST SEEN         TYPE     REASON            OBJECT                          MESSAGE
+5m                Normal   SuccessfulCreate  StatefulSet/hippo-instance1-8gjt  create Pod hippo-instance1-8gjt-0 in StatefulSet hippo-instance1-8gjt successful
+4m                Normal   Started           Pod/hippo-instance1-8gjt-0      Started container database
+2m (x3 over 3m)   Warning  Unhealthy         Pod/hippo-instance1-8gjt-0      Readiness probe failed: HTTP probe failed with statuscode: 503`)
+
+	events := pgEvents{Config: config}
+
+	cmd.Flags().BoolVarP(&events.Watch, "watch", "w", false, "after listing events, print new events as they happen")
+	cmd.Flags().StringSliceVar(&events.Types, "types", nil, `only show events of these types: "Normal" or "Warning"`)
+
+	var outputEnum = util.TextOutput
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"output format. types supported: text,json")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		for _, t := range events.Types {
+			if t != corev1.EventTypeNormal && t != corev1.EventTypeWarning {
+				return fmt.Errorf(`invalid event type %q: must be "Normal" or "Warning"`, t)
+			}
+		}
+
+		events.ClusterName = args[0]
+		events.JSON = outputEnum == util.JSONOutput
+		return events.Run(context.Background())
+	}
+
+	return cmd
+}
+
+type pgEvents struct {
+	*internal.Config
+
+	ClusterName string
+	JSON        bool
+	Types       []string
+	Watch       bool
+}
+
+// clusterEvent is the JSON representation of an Event.
+type clusterEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Object  string    `json:"object"`
+	Count   int32     `json:"count,omitempty"`
+	Message string    `json:"message"`
+}
+
+// relatedObject is an object that may belong to a PostgresCluster.
+type relatedObject struct {
+	Kind string
+	metav1.Object
+}
+
+// relatedObjects identifies the objects that belong to a PostgresCluster by
+// UID and by kind and name.
+type relatedObjects struct {
+	UIDs  map[types.UID]bool
+	Names map[string]bool
+}
+
+// Has returns whether or not the object involved in event belongs to the
+// PostgresCluster. Objects that were deleted or recreated, such as Pods during
+// a failover, have other UIDs, so kind and name are checked, too.
+func (related relatedObjects) Has(event corev1.Event) bool {
+	if uid := event.InvolvedObject.UID; uid != "" && related.UIDs[uid] {
+		return true
+	}
+	return related.Names[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name]
+}
+
+// findRelatedObjects returns cluster and the objects that have the cluster
+// label of cluster or are owned, directly or indirectly, by cluster or one
+// of those objects.
+func findRelatedObjects(cluster relatedObject, objects []relatedObject) relatedObjects {
+	related := relatedObjects{UIDs: map[types.UID]bool{}, Names: map[string]bool{}}
+	add := func(object relatedObject) {
+		related.UIDs[object.GetUID()] = true
+		related.Names[object.Kind+"/"+object.GetName()] = true
+	}
+
+	add(cluster)
+	for _, object := range objects {
+		if object.GetLabels()[util.LabelCluster] == cluster.GetName() {
+			add(object)
+		}
+	}
+
+	// Owners can be several levels up, such as Deployment, ReplicaSet, and
+	// Pod. Repeat until there are no more objects to add.
+	for found := true; found; {
+		found = false
+		for _, object := range objects {
+			if related.UIDs[object.GetUID()] {
+				continue
+			}
+			for _, owner := range object.GetOwnerReferences() {
+				if related.UIDs[owner.UID] {
+					add(object)
+					found = true
+					break
+				}
+			}
+		}
+	}
+
+	return related
+}
+
+// eventTime returns the most recent time that event happened.
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// eventCount returns the number of times event happened.
+func eventCount(event corev1.Event) int32 {
+	if event.Series != nil {
+		return event.Series.Count
+	}
+	return event.Count
+}
+
+// eventInterval returns when event last happened, how many times, and when
+// it first happened in human-readable approximation, the same as kubectl.
+// - https://github.com/kubernetes/kubectl/blob/release-1.24/pkg/cmd/events/events.go#L262-L292
+func eventInterval(event corev1.Event) string {
+	// translateMicroTimestampSince returns the elapsed time since timestamp in
+	// human-readable approximation.
+	translateMicroTimestampSince := func(timestamp metav1.MicroTime) string {
+		if timestamp.IsZero() {
+			return "<unknown>"
+		}
+
+		return duration.HumanDuration(time.Since(timestamp.Time))
+	}
+
+	firstTimestampSince := translateMicroTimestampSince(event.EventTime)
+	if event.EventTime.IsZero() {
+		firstTimestampSince = translateTimestampSince(event.FirstTimestamp)
+	}
+	if event.Series != nil {
+		return fmt.Sprintf("%s (x%d over %s)", translateMicroTimestampSince(event.Series.LastObservedTime), event.Series.Count, firstTimestampSince)
+	}
+	return firstTimestampSince
+}
+
+// filter returns the events of related objects that have one of the wanted
+// types, oldest first.
+func (events pgEvents) filter(list []corev1.Event, related relatedObjects) []corev1.Event {
+	var result []corev1.Event
+	for _, event := range list {
+		if related.Has(event) && (len(events.Types) == 0 || slices.Contains(events.Types, event.Type)) {
+			result = append(result, event)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return eventTime(result[i]).Before(eventTime(result[j]))
+	})
+	return result
+}
+
+// print writes list to w as a table or as JSON. When header is false, table
+// rows are written without column names.
+func (events pgEvents) print(w io.Writer, list []corev1.Event, header bool) error {
+	if events.JSON {
+		records := make([]clusterEvent, 0, len(list))
+		for _, event := range list {
+			records = append(records, clusterEvent{
+				Time:    eventTime(event).UTC(),
+				Type:    event.Type,
+				Reason:  event.Reason,
+				Object:  event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+				Count:   eventCount(event),
+				Message: strings.TrimSpace(event.Message),
+			})
+		}
+
+		// Print one record per line while watching, otherwise an array.
+		encoder := json.NewEncoder(w)
+		if events.Watch {
+			for _, record := range records {
+				if err := encoder.Encode(record); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	}
+
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if header {
+		if _, err := fmt.Fprintf(p, "LAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE\n"); err != nil {
+			return err
+		}
+	}
+	for _, event := range list {
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s/%s\t%v\n",
+			eventInterval(event),
+			event.Type,
+			event.Reason,
+			event.InvolvedObject.Kind, event.InvolvedObject.Name,
+			strings.TrimSpace(event.Message),
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// listRelatedObjects returns the objects in namespace that may belong to a
+// PostgresCluster. Kinds that cannot be listed are left out.
+func (events pgEvents) listRelatedObjects(ctx context.Context,
+	client kubernetes.Interface, namespace string,
+) ([]relatedObject, error) {
+	var objects []relatedObject
+
+	for _, lister := range []struct {
+		Kind string
+		List func() (runtime.Object, error)
+	}{
+		{"Pod", func() (runtime.Object, error) {
+			return client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"PersistentVolumeClaim", func() (runtime.Object, error) {
+			return client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"Service", func() (runtime.Object, error) {
+			return client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"StatefulSet", func() (runtime.Object, error) {
+			return client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"Deployment", func() (runtime.Object, error) {
+			return client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"ReplicaSet", func() (runtime.Object, error) {
+			return client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"Job", func() (runtime.Object, error) {
+			return client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"CronJob", func() (runtime.Object, error) {
+			return client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+		}},
+	} {
+		list, err := lister.List()
+		if apierrors.IsForbidden(err) {
+			_, _ = fmt.Fprintln(events.ErrOut, err.Error())
+			continue
+		}
+
+		var items []runtime.Object
+		if err == nil {
+			items, err = meta.ExtractList(list)
+		}
+		for i := 0; err == nil && i < len(items); i++ {
+			var object metav1.Object
+			if object, err = meta.Accessor(items[i]); err == nil {
+				objects = append(objects, relatedObject{Kind: lister.Kind, Object: object})
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, nil
+}
+
+func (events pgEvents) Run(ctx context.Context) error {
+	namespace, err := events.Namespace()
+	if err != nil {
+		return err
+	}
+	_, clusterClient, err := v1beta1.NewPostgresClusterClient(events)
+	if err != nil {
+		return err
+	}
+	rest, err := events.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clusterClient.Namespace(namespace).Get(ctx, events.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	refresh := func() (relatedObjects, error) {
+		objects, err := events.listRelatedObjects(ctx, client, namespace)
+		return findRelatedObjects(relatedObject{Kind: cluster.GetKind(), Object: cluster}, objects), err
+	}
+
+	related, err := refresh()
+	if err != nil {
+		return err
+	}
+
+	list, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if err := events.print(events.Out, events.filter(list.Items, related), true); err != nil {
+		return err
+	}
+	if !events.Watch {
+		return nil
+	}
+
+	// The API server closes watches after a while. Start another from the
+	// last event seen until the command is interrupted.
+	resourceVersion := list.ResourceVersion
+	for {
+		watcher, err := client.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			return err
+		}
+
+		resourceVersion, err = events.follow(watcher, resourceVersion, &related, refresh)
+		watcher.Stop()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// follow prints the events of related objects from watcher until it closes.
+// It returns the resource version of the last event it received.
+func (events pgEvents) follow(watcher watch.Interface, resourceVersion string,
+	related *relatedObjects, refresh func() (relatedObjects, error),
+) (string, error) {
+	// Objects created after the first list, such as Pods and Jobs, are not
+	// yet related. Look for them again, but not more than every few seconds.
+	var refreshed time.Time
+	for change := range watcher.ResultChan() {
+		if change.Type == watch.Error {
+			return resourceVersion, fmt.Errorf("stopped watching events: %w",
+				apierrors.FromObject(change.Object))
+		}
+		event, ok := change.Object.(*corev1.Event)
+		if !ok || (change.Type != watch.Added && change.Type != watch.Modified) {
+			continue
+		}
+		resourceVersion = event.ResourceVersion
+
+		if !related.Has(*event) && time.Since(refreshed) > 5*time.Second {
+			var err error
+			if *related, err = refresh(); err != nil {
+				return resourceVersion, err
+			}
+			refreshed = time.Now()
+		}
+
+		if err := events.print(events.Out, events.filter([]corev1.Event{*event}, *related), false); err != nil {
+			return resourceVersion, err
+		}
+	}
+
+	return resourceVersion, nil
+}
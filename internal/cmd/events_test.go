@@ -0,0 +1,188 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+)
+
+func TestFindRelatedObjects(t *testing.T) {
+	object := func(kind, name, uid string, labels map[string]string, owner string) relatedObject {
+		meta := &metav1.ObjectMeta{Name: name, UID: types.UID(uid), Labels: labels}
+		if owner != "" {
+			meta.OwnerReferences = []metav1.OwnerReference{{UID: types.UID(owner)}}
+		}
+		return relatedObject{Kind: kind, Object: meta}
+	}
+
+	cluster := object("PostgresCluster", "hippo", "c1", nil, "")
+	related := findRelatedObjects(cluster, []relatedObject{
+		// Owned by a Deployment that has the cluster label.
+		object("Pod", "hippo-pgbouncer-abc-1", "p1", nil, "rs1"),
+		object("ReplicaSet", "hippo-pgbouncer-abc", "rs1", nil, "d1"),
+		object("Deployment", "hippo-pgbouncer", "d1", map[string]string{"postgres-operator.crunchydata.com/cluster": "hippo"}, ""),
+
+		// Owned by the cluster without a label.
+		object("Service", "hippo-primary", "s1", nil, "c1"),
+
+		// Another cluster and something unrelated.
+		object("StatefulSet", "rhino-instance1", "ss2", map[string]string{"postgres-operator.crunchydata.com/cluster": "rhino"}, ""),
+		object("Pod", "app", "p2", nil, ""),
+	})
+
+	assert.DeepEqual(t, related.UIDs, map[types.UID]bool{
+		"c1": true, "p1": true, "rs1": true, "d1": true, "s1": true,
+	})
+
+	event := func(kind, name, uid string) corev1.Event {
+		return corev1.Event{InvolvedObject: corev1.ObjectReference{Kind: kind, Name: name, UID: types.UID(uid)}}
+	}
+	assert.Assert(t, related.Has(event("Pod", "hippo-pgbouncer-abc-1", "p1")))
+	assert.Assert(t, related.Has(event("PostgresCluster", "hippo", "")))
+	assert.Assert(t, !related.Has(event("Pod", "app", "p2")))
+	assert.Assert(t, !related.Has(event("Pod", "app", "")))
+
+	// Objects that were deleted or recreated match by kind and name.
+	assert.Assert(t, related.Has(event("Pod", "hippo-pgbouncer-abc-1", "recreated")))
+	assert.Assert(t, !related.Has(event("Pod", "hippo-pgbouncer-abc-2", "deleted")))
+}
+
+func TestPGEventsFollow(t *testing.T) {
+	related := relatedObjects{UIDs: map[types.UID]bool{"hippo-0": true}, Names: map[string]bool{}}
+	refresh := func() (relatedObjects, error) { return related, nil }
+	event := func(version string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "e" + version, ResourceVersion: version},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "hippo-0", UID: "hippo-0"},
+			Type:           corev1.EventTypeNormal, Reason: "Started",
+		}
+	}
+
+	t.Run("Closed", func(t *testing.T) {
+		var buf bytes.Buffer
+		events := pgEvents{Config: &internal.Config{IOStreams: genericclioptions.IOStreams{Out: &buf}}}
+
+		watcher := watch.NewFakeWithChanSize(2, false)
+		watcher.Add(event("5"))
+		watcher.Modify(event("7"))
+		watcher.Stop()
+
+		version, err := events.follow(watcher, "1", &related, refresh)
+		assert.NilError(t, err)
+		assert.Equal(t, version, "7", "expected the last version to watch from")
+		assert.Equal(t, bytes.Count(buf.Bytes(), []byte("Started")), 2)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		events := pgEvents{Config: &internal.Config{IOStreams: genericclioptions.IOStreams{Out: new(bytes.Buffer)}}}
+
+		watcher := watch.NewFakeWithChanSize(1, false)
+		watcher.Error(&metav1.Status{Status: metav1.StatusFailure, Code: 410, Reason: metav1.StatusReasonExpired,
+			Message: "too old resource version"})
+
+		version, err := events.follow(watcher, "3", &related, refresh)
+		assert.ErrorContains(t, err, "stopped watching events: too old resource version")
+		assert.Equal(t, version, "3")
+	})
+}
+
+func TestPGEventsFilterPrint(t *testing.T) {
+	// The API stores timestamps with second precision.
+	now := time.Now().Truncate(time.Second)
+	event := func(name, kind, object, eventType string, ago time.Duration) corev1.Event {
+		return corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object, UID: types.UID(object)},
+			Type:           eventType, Reason: name, Message: name + " happened\n",
+			FirstTimestamp: metav1.NewTime(now.Add(-ago)),
+			LastTimestamp:  metav1.NewTime(now.Add(-ago)),
+			Count:          1,
+		}
+	}
+
+	list := []corev1.Event{
+		event("Second", "Pod", "hippo-0", corev1.EventTypeWarning, 2*time.Minute),
+		event("First", "PostgresCluster", "hippo", corev1.EventTypeNormal, 5*time.Minute),
+		event("Other", "Pod", "app", corev1.EventTypeWarning, time.Minute),
+	}
+	list[0].Series = &corev1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(now.Add(-time.Minute))}
+
+	related := relatedObjects{UIDs: map[types.UID]bool{"hippo": true, "hippo-0": true}}
+
+	events := pgEvents{}
+	filtered := events.filter(list, related)
+	assert.Equal(t, len(filtered), 2)
+	assert.Equal(t, filtered[0].Reason, "First")
+	assert.Equal(t, filtered[1].Reason, "Second")
+
+	var buf bytes.Buffer
+	assert.NilError(t, events.print(&buf, filtered, true))
+	assert.Equal(t, buf.String(), ``+
+		"LAST SEEN          TYPE      REASON   OBJECT                  MESSAGE\n"+
+		"5m                 Normal    First    PostgresCluster/hippo   First happened\n"+
+		"60s (x3 over 2m)   Warning   Second   Pod/hippo-0             Second happened\n")
+
+	t.Run("Types", func(t *testing.T) {
+		events := pgEvents{Types: []string{corev1.EventTypeWarning}}
+		filtered := events.filter(list, related)
+		assert.Equal(t, len(filtered), 1)
+		assert.Equal(t, filtered[0].Reason, "Second")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		events := pgEvents{JSON: true}
+
+		var buf bytes.Buffer
+		assert.NilError(t, events.print(&buf, filtered[:1], true))
+		assert.Equal(t, buf.String(), `[
+  {
+    "time": "`+now.Add(-5*time.Minute).UTC().Format(time.RFC3339)+`",
+    "type": "Normal",
+    "reason": "First",
+    "object": "PostgresCluster/hippo",
+    "count": 1,
+    "message": "First happened"
+  }
+]
+`)
+
+		events.Watch = true
+		buf.Reset()
+		assert.NilError(t, events.print(&buf, filtered, false))
+		assert.Equal(t, bytes.Count(buf.Bytes(), []byte("\n")), 2, "expected one line per event")
+	})
+}
+
+func TestListRelatedObjects(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "zoo", Name: "hippo-0", UID: "p1"}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "zoo", Name: "hippo", UID: "ss1"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "elsewhere", Name: "hippo", UID: "s1"}},
+	)
+
+	objects, err := pgEvents{}.listRelatedObjects(ctx, client, "zoo")
+	assert.NilError(t, err)
+
+	var names []string
+	for _, object := range objects {
+		names = append(names, object.Kind+"/"+object.GetName())
+	}
+	assert.DeepEqual(t, names, []string{"Pod/hippo-0", "StatefulSet/hippo"})
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 // Executor calls commands
@@ -20,12 +21,25 @@ type Executor func(
 func (exec Executor) pgBackRestInfo(output, repoNum string) (string, string, error) {
 	var stdout, stderr bytes.Buffer
 	var command string
+	var args []string
 
 	command = "pgbackrest info --output=" + output
 	if repoNum != "" {
-		command += " --repo=" + repoNum
+		command += ` --repo="$1"`
+		args = append(args, "-", repoNum)
 	}
-	err := exec(nil, &stdout, &stderr, "bash", "-ceu", "--", command)
+	err := exec(nil, &stdout, &stderr, append([]string{"bash", "-ceu", "--", command}, args...)...)
+
+	return stdout.String(), stderr.String(), err
+}
+
+// pgBackRestExpire defines a pgBackRest expire command that removes one backup
+// set and the backups that depend on it from a repository
+func (exec Executor) pgBackRestExpire(repoNum, set string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	command := `pgbackrest expire --stanza=db --log-level-console=info --repo="$1" --set="$2"`
+	err := exec(nil, &stdout, &stderr, "bash", "-ceu", "--", command, "-", repoNum, set)
 
 	return stdout.String(), stderr.String(), err
 }
@@ -47,14 +61,29 @@ func (exec Executor) pgBackRestCheck() (string, string, error) {
 	return stdout.String(), stderr.String(), err
 }
 
-// postgresqlListLogFiles returns the full path of numLogs log files.
-func (exec Executor) listPGLogFiles(numLogs int, hasInstrumentation bool) (string, string, error) {
+// pgBackRestVerify defines a pgBackRest verify command that checks the backups
+// and WAL in a repository. Problems with files are logged at the info level.
+func (exec Executor) pgBackRestVerify(repoNum string) (string, string, error) {
 	var stdout, stderr bytes.Buffer
+	command := `pgbackrest verify --stanza=db --output=text --log-level-console=info --repo="$1"`
+	err := exec(nil, &stdout, &stderr, "bash", "-ceu", "--", command, "-", repoNum)
+
+	return stdout.String(), stderr.String(), err
+}
 
-	location := "pgdata/pg[0-9][0-9]/log/*"
+// pgLogFilePattern returns the glob of the Postgres log files.
+func pgLogFilePattern(hasInstrumentation bool) string {
 	if hasInstrumentation {
-		location = "pgdata/logs/postgres/*.*"
+		return "pgdata/logs/postgres/*.*"
 	}
+	return "pgdata/pg[0-9][0-9]/log/*"
+}
+
+// postgresqlListLogFiles returns the full path of numLogs log files.
+func (exec Executor) listPGLogFiles(numLogs int, hasInstrumentation bool) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	location := pgLogFilePattern(hasInstrumentation)
 	// Check both the older and the newer log locations.
 	// If a cluster has used both locations, this will return logs from both.
 	// If a cluster does not have one location or the other, continue without error.
@@ -152,6 +181,60 @@ func (exec Executor) patronictl(cmd, output string) (string, string, error) {
 	return stdout.String(), stderr.String(), err
 }
 
+// pgSettings returns the rows of the pg_settings view as a JSON array
+func (exec Executor) pgSettings() (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	command := "psql --no-psqlrc --quiet --tuples-only --no-align" +
+		" --command 'SELECT json_agg(s ORDER BY s.name) FROM pg_settings s'"
+	err := exec(nil, &stdout, &stderr, "bash", "-ceu", "--", command)
+
+	return stdout.String(), stderr.String(), err
+}
+
+// walPositions returns the WAL positions of Postgres as a JSON object. The
+// current position is null during recovery, and the others are null otherwise.
+func (exec Executor) walPositions() (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	command := "psql --no-psqlrc --quiet --tuples-only --no-align --file=-"
+	query := `SELECT json_build_object(
+  'in_recovery', pg_is_in_recovery(),
+  'current_lsn', CASE WHEN NOT pg_is_in_recovery() THEN pg_current_wal_lsn() END,
+  'receive_lsn', pg_last_wal_receive_lsn(),
+  'replay_lsn', pg_last_wal_replay_lsn(),
+  'replay_timestamp', pg_last_xact_replay_timestamp())`
+	err := exec(strings.NewReader(query), &stdout, &stderr, "bash", "-ceu", "--", command)
+
+	return stdout.String(), stderr.String(), err
+}
+
+// psqlScript runs the SQL in script against database and stops at the first
+// error. Each query is printed before its result.
+func (exec Executor) psqlScript(database, script string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	command := `psql --no-psqlrc --set=ON_ERROR_STOP=1 --echo-queries --dbname="$1" --file=-`
+	err := exec(strings.NewReader(script), &stdout, &stderr, "bash", "-ceu", "--", command, "-", database)
+
+	return stdout.String(), stderr.String(), err
+}
+
+// pgBouncerShow runs a SHOW command in the PgBouncer admin console on port and
+// returns the result as CSV. The password is sent on stdin so it does not
+// appear in the list of processes.
+func (exec Executor) pgBouncerShow(port, password, what string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+
+	command := `read -r PGPASSWORD && export PGPASSWORD && ` +
+		`psql --no-psqlrc --csv --host=localhost --port="$1" --username=_crunchypgbouncer` +
+		` --dbname=pgbouncer --command="SHOW $2"`
+	err := exec(strings.NewReader(password+"\n"), &stdout, &stderr,
+		"bash", "-ceu", "--", command, "-", port, what)
+
+	return stdout.String(), stderr.String(), err
+}
+
 // processes returns the output of a ps command
 func (exec Executor) processes() (string, string, error) {
 	var stdout, stderr bytes.Buffer
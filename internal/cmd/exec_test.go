@@ -7,6 +7,7 @@ package cmd
 import (
 	"errors"
 	"io"
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -34,7 +35,7 @@ func TestPGBackRestInfo(t *testing.T) {
 		exec := func(
 			stdin io.Reader, stdout, stderr io.Writer, command ...string,
 		) error {
-			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--", "pgbackrest info --output=text --repo=1"})
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--", `pgbackrest info --output=text --repo="$1"`, "-", "1"})
 			assert.Assert(t, stdout != nil, "should capture stdout")
 			assert.Assert(t, stderr != nil, "should capture stderr")
 			return expected
@@ -49,7 +50,7 @@ func TestPGBackRestInfo(t *testing.T) {
 		exec := func(
 			stdin io.Reader, stdout, stderr io.Writer, command ...string,
 		) error {
-			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--", "pgbackrest info --output=json --repo=2"})
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--", `pgbackrest info --output=json --repo="$1"`, "-", "2"})
 			assert.Assert(t, stdout != nil, "should capture stdout")
 			assert.Assert(t, stderr != nil, "should capture stderr")
 			return expected
@@ -60,6 +61,116 @@ func TestPGBackRestInfo(t *testing.T) {
 	})
 }
 
+func TestPGBackRestExpire(t *testing.T) {
+
+	t.Run("default", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--",
+				`pgbackrest expire --stanza=db --log-level-console=info --repo="$1" --set="$2"`,
+				"-", "2", "20250301-010002F"})
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+		_, _, err := Executor(exec).pgBackRestExpire("2", "20250301-010002F")
+		assert.ErrorContains(t, err, "pass-through")
+
+	})
+}
+
+func TestPGBackRestVerify(t *testing.T) {
+
+	t.Run("default", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--",
+				`pgbackrest verify --stanza=db --output=text --log-level-console=info --repo="$1"`,
+				"-", "1"})
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+		_, _, err := Executor(exec).pgBackRestVerify("1")
+		assert.ErrorContains(t, err, "pass-through")
+
+	})
+}
+
+func TestPSQLScript(t *testing.T) {
+
+	t.Run("default", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--",
+				`psql --no-psqlrc --set=ON_ERROR_STOP=1 --echo-queries --dbname="$1" --file=-`,
+				"-", "app db"})
+			script, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Equal(t, string(script), "SELECT count(*) FROM t;")
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+		_, _, err := Executor(exec).psqlScript("app db", "SELECT count(*) FROM t;")
+		assert.ErrorContains(t, err, "pass-through")
+
+	})
+}
+
+func TestWALPositions(t *testing.T) {
+
+	t.Run("default", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--",
+				"psql --no-psqlrc --quiet --tuples-only --no-align --file=-"})
+			query, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Assert(t, strings.Contains(string(query), "pg_last_wal_replay_lsn()"))
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+		_, _, err := Executor(exec).walPositions()
+		assert.ErrorContains(t, err, "pass-through")
+
+	})
+}
+
+func TestPGBouncerShow(t *testing.T) {
+
+	t.Run("default", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--",
+				`read -r PGPASSWORD && export PGPASSWORD && ` +
+					`psql --no-psqlrc --csv --host=localhost --port="$1" --username=_crunchypgbouncer` +
+					` --dbname=pgbouncer --command="SHOW $2"`,
+				"-", "5432", "POOLS"})
+			password, err := io.ReadAll(stdin)
+			assert.NilError(t, err)
+			assert.Equal(t, string(password), "secret\n")
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+		_, _, err := Executor(exec).pgBouncerShow("5432", "secret", "POOLS")
+		assert.ErrorContains(t, err, "pass-through")
+
+	})
+}
+
 func TestListPGLogFiles(t *testing.T) {
 
 	t.Run("default", func(t *testing.T) {
@@ -151,6 +262,27 @@ func TestPatronictl(t *testing.T) {
 
 }
 
+func TestPGSettings(t *testing.T) {
+
+	t.Run("default", func(t *testing.T) {
+		expected := errors.New("pass-through")
+		exec := func(
+			stdin io.Reader, stdout, stderr io.Writer, command ...string,
+		) error {
+			assert.DeepEqual(t, command, []string{"bash", "-ceu", "--",
+				"psql --no-psqlrc --quiet --tuples-only --no-align" +
+					" --command 'SELECT json_agg(s ORDER BY s.name) FROM pg_settings s'"})
+			assert.Assert(t, stdout != nil, "should capture stdout")
+			assert.Assert(t, stderr != nil, "should capture stderr")
+			return expected
+		}
+		_, _, err := Executor(exec).pgSettings()
+		assert.ErrorContains(t, err, "pass-through")
+
+	})
+
+}
+
 func TestProcesses(t *testing.T) {
 
 	t.Run("default", func(t *testing.T) {
@@ -1200,16 +1200,6 @@ func gatherEvents(ctx context.Context,
 		return err
 	}
 
-	// translateMicroTimestampSince returns the elapsed time since timestamp in
-	// human-readable approximation.
-	translateMicroTimestampSince := func(timestamp metav1.MicroTime) string {
-		if timestamp.IsZero() {
-			return "<unknown>"
-		}
-
-		return duration.HumanDuration(time.Since(timestamp.Time))
-	}
-
 	// Most of this printing code is pulled from kubectl's get events command
 	// https://github.com/kubernetes/kubectl/blob/release-1.24/pkg/cmd/events/events.go#L262-L292
 	var buf bytes.Buffer
@@ -1218,19 +1208,8 @@ func gatherEvents(ctx context.Context,
 		return err
 	}
 	for _, event := range list.Items {
-		var interval string
-		firstTimestampSince := translateMicroTimestampSince(event.EventTime)
-		if event.EventTime.IsZero() {
-			firstTimestampSince = translateTimestampSince(event.FirstTimestamp)
-		}
-		if event.Series != nil {
-			interval = fmt.Sprintf("%s (x%d over %s)", translateMicroTimestampSince(event.Series.LastObservedTime), event.Series.Count, firstTimestampSince)
-		} else {
-			interval = firstTimestampSince
-		}
-
 		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s/%s\t%v\n",
-			interval,
+			eventInterval(event),
 			event.Type,
 			event.Reason,
 			event.InvolvedObject.Kind, event.InvolvedObject.Name,
@@ -0,0 +1,718 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// hbaFields is the path to the pg_hba rules in the spec of a PostgresCluster.
+var hbaFields = []string{"spec", "patroni", "dynamicConfiguration", "postgresql", "pg_hba"}
+
+// newHBACommand returns the hba subcommand of the PGO plugin. Subcommands
+// of hba list and change the pg_hba rules of a PostgresCluster.
+func newHBACommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hba",
+		Short: "Manage the pg_hba rules of a PostgresCluster",
+		Long: `Manage the pg_hba rules in "spec.patroni.dynamicConfiguration.postgresql.pg_hba"
+of a PostgresCluster.
+
+Rules are checked for valid syntax before they are added, and rules that can
+never match because an earlier rule matches first are reported.
+
+The rules are a single list, so they are changed only when no other client
+manages the list. When another client, such as a GitOps tool, manages the
+rules, add and remove fail and name that client; change the rules there.`,
+	}
+
+	cmd.AddCommand(
+		newHBAListCommand(config),
+		newHBAAddCommand(config),
+		newHBARemoveCommand(config),
+	)
+
+	return cmd
+}
+
+func newHBAListCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list CLUSTER_NAME",
+		Short: "List the pg_hba rules of a PostgresCluster",
+		Long: `List the pg_hba rules of a PostgresCluster in the order Postgres checks them.
+Invalid rules and rules that can never match are reported as warnings.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# List the pg_hba rules of the 'hippo' postgrescluster
+pgo hba list hippo
+
+### Example output
+INDEX   TYPE      DATABASE   USER    ADDRESS       METHOD          OPTIONS
+1       hostssl   all        all     10.0.0.0/8    scram-sha-256
+2       host      app        rhino   10.1.0.0/16   md5
+WARNING: rule 2 is shadowed by rule 1 and can never match`)
+
+	hba := pgHBA{Config: config}
+
+	var outputEnum = util.TextOutput
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"output format. types supported: text,json")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		hba.ClusterName = args[0]
+		hba.JSON = outputEnum == util.JSONOutput
+		return hba.List(context.Background())
+	}
+
+	return cmd
+}
+
+func newHBAAddCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add CLUSTER_NAME RULE",
+		Short: "Add a pg_hba rule to a PostgresCluster",
+		Long: `Add a pg_hba rule to a PostgresCluster. The rule is added after the other rules
+unless --position is passed.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Allow TLS connections from the 10.0.0.0/8 network
+pgo hba add hippo "hostssl all all 10.0.0.0/8 scram-sha-256"
+
+# Reject connections from one address before any other rule
+pgo hba add hippo "host all all 10.1.2.3/32 reject" --position 1
+
+### Example output
+postgresclusters/hippo pg_hba rule 1 added`)
+
+	hba := pgHBA{Config: config}
+
+	cmd.Flags().IntVar(&hba.Position, "position", 0, "the 1-based position of the new rule; default is last")
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		hba.ClusterName = args[0]
+		return hba.Add(context.Background(), args[1])
+	}
+
+	return cmd
+}
+
+func newHBARemoveCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove CLUSTER_NAME RULE",
+		Short: "Remove a pg_hba rule from a PostgresCluster",
+		Long: `Remove a pg_hba rule from a PostgresCluster. The rule must match an existing
+rule field by field; spacing and quotes do not matter.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Remove a pg_hba rule from the 'hippo' postgrescluster
+pgo hba remove hippo "host all all 10.1.2.3/32 reject"
+
+### Example output
+WARNING: Clients that rely on this rule will no longer be able to connect.
+Are you sure you want to continue? (yes/no): yes
+postgresclusters/hippo pg_hba rule 1 removed`)
+
+	hba := pgHBA{Config: config}
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		hba.ClusterName = args[0]
+		return hba.Remove(context.Background(), args[1])
+	}
+
+	return cmd
+}
+
+type pgHBA struct {
+	*internal.Config
+
+	ClusterName string
+	JSON        bool
+	Position    int
+}
+
+// hbaRule is a parsed line of pg_hba.conf.
+// - https://www.postgresql.org/docs/current/auth-pg-hba-conf.html
+type hbaRule struct {
+	Type      string   `json:"type"`
+	Databases []string `json:"database"`
+	Users     []string `json:"user"`
+	Address   string   `json:"address,omitempty"`
+	Method    string   `json:"method"`
+	Options   []string `json:"options,omitempty"`
+
+	// network is the parsed Address when it is an IP address or range.
+	network *net.IPNet
+}
+
+var (
+	hbaTypes = []string{
+		"local", "host", "hostssl", "hostnossl", "hostgssenc", "hostnogssenc",
+	}
+	hbaMethods = []string{
+		"trust", "reject", "scram-sha-256", "md5", "password", "gss", "sspi",
+		"ident", "peer", "ldap", "radius", "cert", "pam", "bsd",
+	}
+)
+
+// splitHBARule returns the fields of rule. Quoted parts of a field keep their
+// quotes, and a field that starts with "#" ends the rule.
+func splitHBARule(rule string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	var quoted bool
+
+	for _, r := range rule {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			field.WriteRune(r)
+		case !quoted && (r == ' ' || r == '\t'):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		case !quoted && r == '#' && field.Len() == 0:
+			return fields, nil
+		case r == '\n' || r == '\r':
+			return nil, errors.New("rule must be a single line")
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quoted string")
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
+
+// splitHBAList returns the comma-separated items of field without quotes.
+// Quoted items are prefixed with a quote so they are not taken as keywords.
+func splitHBAList(field string) []string {
+	var items []string
+	var item strings.Builder
+	var quoted, wasQuoted bool
+
+	for _, r := range field {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			wasQuoted = true
+		case r == ',' && !quoted:
+			items = append(items, hbaItem(item.String(), wasQuoted))
+			item.Reset()
+			wasQuoted = false
+		default:
+			item.WriteRune(r)
+		}
+	}
+	return append(items, hbaItem(item.String(), wasQuoted))
+}
+
+func hbaItem(item string, quoted bool) string {
+	if quoted {
+		return `"` + item
+	}
+	return item
+}
+
+// parseHBARule parses and validates one pg_hba rule.
+func parseHBARule(text string) (hbaRule, error) {
+	var rule hbaRule
+
+	fields, err := splitHBARule(text)
+	if err != nil {
+		return rule, err
+	}
+	next := func(name string) (string, error) {
+		if len(fields) == 0 {
+			return "", fmt.Errorf("missing %s", name)
+		}
+		field := fields[0]
+		fields = fields[1:]
+		return field, nil
+	}
+
+	if rule.Type, err = next("connection type"); err != nil {
+		return rule, err
+	}
+	if !slices.Contains(hbaTypes, rule.Type) {
+		return rule, fmt.Errorf("invalid connection type %q: expected one of %s",
+			rule.Type, strings.Join(hbaTypes, ", "))
+	}
+
+	for _, list := range []struct {
+		name  string
+		items *[]string
+	}{
+		{"database", &rule.Databases},
+		{"user", &rule.Users},
+	} {
+		field, err := next(list.name)
+		if err != nil {
+			return rule, err
+		}
+		*list.items = splitHBAList(field)
+		if slices.Contains(*list.items, "") {
+			return rule, fmt.Errorf("invalid %s %q: empty name", list.name, field)
+		}
+	}
+
+	if rule.Type != "local" {
+		if rule.Address, err = next("address"); err != nil {
+			return rule, err
+		}
+		if err := rule.parseAddress(&fields); err != nil {
+			return rule, err
+		}
+	}
+
+	if rule.Method, err = next("authentication method"); err != nil {
+		return rule, err
+	}
+	if !slices.Contains(hbaMethods, rule.Method) {
+		return rule, fmt.Errorf("invalid authentication method %q: expected one of %s",
+			rule.Method, strings.Join(hbaMethods, ", "))
+	}
+	if rule.Method == "peer" && rule.Type != "local" {
+		return rule, errors.New(`authentication method "peer" is only valid for "local" connections`)
+	}
+	if rule.Method == "cert" && rule.Type != "hostssl" {
+		return rule, errors.New(`authentication method "cert" is only valid for "hostssl" connections`)
+	}
+
+	for _, option := range fields {
+		if name, _, found := strings.Cut(option, "="); !found || name == "" {
+			return rule, fmt.Errorf("invalid authentication option %q: expected NAME=VALUE", option)
+		}
+	}
+	rule.Options = fields
+
+	return rule, nil
+}
+
+// parseAddress validates the address of rule. An IP address without a CIDR
+// mask consumes the next field as its netmask.
+func (rule *hbaRule) parseAddress(fields *[]string) error {
+	switch rule.Address {
+	case "all", "samehost", "samenet":
+		return nil
+	}
+
+	if strings.Contains(rule.Address, "/") {
+		_, network, err := net.ParseCIDR(rule.Address)
+		if err != nil {
+			return fmt.Errorf("invalid address %q: %w", rule.Address, err)
+		}
+		rule.network = network
+		return nil
+	}
+
+	if ip := net.ParseIP(rule.Address); ip != nil {
+		if len(*fields) == 0 {
+			return fmt.Errorf("invalid address %q: missing netmask", rule.Address)
+		}
+		mask := net.ParseIP((*fields)[0])
+		if ip.To4() != nil {
+			mask = mask.To4()
+		}
+		ones, bits := net.IPMask(mask).Size()
+		if mask == nil || bits == 0 {
+			return fmt.Errorf("invalid netmask %q for address %q", (*fields)[0], rule.Address)
+		}
+		*fields = (*fields)[1:]
+
+		rule.network = &net.IPNet{IP: ip.Mask(net.CIDRMask(ones, bits)), Mask: net.CIDRMask(ones, bits)}
+		rule.Address = rule.network.String()
+		return nil
+	}
+
+	// Anything else is a host name or a domain suffix starting with a dot.
+	if strings.ContainsAny(rule.Address, `,"`) {
+		return fmt.Errorf("invalid address %q", rule.Address)
+	}
+	return nil
+}
+
+// String returns rule as a single line of pg_hba.conf.
+func (rule hbaRule) String() string {
+	unquote := func(items []string) string {
+		quoted := make([]string, len(items))
+		for i, item := range items {
+			if s, ok := strings.CutPrefix(item, `"`); ok {
+				quoted[i] = strconv.Quote(s)
+			} else {
+				quoted[i] = item
+			}
+		}
+		return strings.Join(quoted, ",")
+	}
+
+	fields := []string{rule.Type, unquote(rule.Databases), unquote(rule.Users)}
+	if rule.Address != "" {
+		fields = append(fields, rule.Address)
+	}
+	fields = append(fields, rule.Method)
+	fields = append(fields, rule.Options...)
+	return strings.Join(fields, " ")
+}
+
+// covers returns whether or not every connection that matches other also
+// matches rule.
+func (rule hbaRule) covers(other hbaRule) bool {
+	switch {
+	case rule.Type == other.Type:
+	case rule.Type == "host" && other.Type != "local":
+	default:
+		return false
+	}
+
+	// The "all" keyword matches every database except "replication".
+	coversList := func(list, other []string, all string) bool {
+		return !slices.ContainsFunc(other, func(item string) bool {
+			return !slices.Contains(list, item) &&
+				!(slices.Contains(list, all) && item != "replication")
+		})
+	}
+	if !coversList(rule.Databases, other.Databases, "all") ||
+		!coversList(rule.Users, other.Users, "all") {
+		return false
+	}
+
+	switch {
+	case rule.Type == "local", rule.Address == "all", rule.Address == other.Address:
+		return true
+	case rule.network != nil && other.network != nil:
+		ones, _ := rule.network.Mask.Size()
+		otherOnes, _ := other.network.Mask.Size()
+		return ones <= otherOnes && rule.network.Contains(other.network.IP)
+	}
+	return false
+}
+
+// hbaEntry is a rule in the list of a PostgresCluster and its problems.
+type hbaEntry struct {
+	Index int    `json:"index"`
+	Rule  string `json:"rule"`
+	*hbaRule
+
+	Error      string `json:"error,omitempty"`
+	ShadowedBy int    `json:"shadowedBy,omitempty"`
+}
+
+// checkHBARules parses rules and finds those that can never match.
+func checkHBARules(rules []string) []hbaEntry {
+	entries := make([]hbaEntry, len(rules))
+	for i, text := range rules {
+		entries[i] = hbaEntry{Index: i + 1, Rule: text}
+
+		rule, err := parseHBARule(text)
+		if err != nil {
+			entries[i].Error = err.Error()
+			continue
+		}
+		entries[i].hbaRule = &rule
+
+		for j := range entries[:i] {
+			if entries[j].hbaRule != nil && entries[j].covers(rule) {
+				entries[i].ShadowedBy = j + 1
+				break
+			}
+		}
+	}
+	return entries
+}
+
+// warnHBAEntries writes a warning for each problem in entries to w.
+func warnHBAEntries(w io.Writer, entries []hbaEntry) {
+	for _, entry := range entries {
+		if entry.Error != "" {
+			_, _ = fmt.Fprintf(w, "WARNING: rule %d is invalid: %s\n", entry.Index, entry.Error)
+		}
+		if entry.ShadowedBy > 0 {
+			_, _ = fmt.Fprintf(w, "WARNING: rule %d is shadowed by rule %d and can never match\n",
+				entry.Index, entry.ShadowedBy)
+		}
+	}
+}
+
+// printHBAEntries writes entries to w as a table or as JSON.
+func printHBAEntries(w io.Writer, entries []hbaEntry, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if entries == nil {
+			entries = []hbaEntry{}
+		}
+		return encoder.Encode(entries)
+	}
+
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "INDEX\tTYPE\tDATABASE\tUSER\tADDRESS\tMETHOD\tOPTIONS\n"); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.hbaRule == nil {
+			continue
+		}
+		if _, err := fmt.Fprintf(p, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Index, entry.Type,
+			strings.Join(entry.Databases, ","), strings.Join(entry.Users, ","),
+			entry.Address, entry.Method, strings.Join(entry.Options, " "),
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// clusterRules returns the pg_hba rules in the spec of cluster.
+func clusterRules(cluster *unstructured.Unstructured) ([]string, error) {
+	rules, _, err := unstructured.NestedStringSlice(cluster.Object, hbaFields...)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected pg_hba rules: %w", err)
+	}
+	return rules, nil
+}
+
+// findHBARule returns the index of the rule in rules that is the same as rule.
+func findHBARule(rules []string, rule hbaRule) int {
+	return slices.IndexFunc(rules, func(text string) bool {
+		parsed, err := parseHBARule(text)
+		return err == nil && parsed.String() == rule.String()
+	})
+}
+
+// checkOwners returns an error when another client manages the pg_hba rules of
+// cluster. The rules are a single list, so applying them would take the list
+// from that client or conflict with it.
+func (hba pgHBA) checkOwners(cluster *unstructured.Unstructured, resource string) error {
+	managers, err := internal.FieldManagers(cluster, hbaFields...)
+	if err != nil {
+		return err
+	}
+
+	var others []string
+	for _, manager := range managers {
+		if manager != hba.Patch.FieldManager {
+			others = append(others, strconv.Quote(manager))
+		}
+	}
+	if len(others) > 0 {
+		return fmt.Errorf("the pg_hba rules of %s/%s are managed by %s; change them with that client",
+			resource, hba.ClusterName, strings.Join(others, ", "))
+	}
+	return nil
+}
+
+// setIntent sets the pg_hba rules of intent. The list is replaced as a whole.
+func (hba pgHBA) setIntent(intent *unstructured.Unstructured, rules []string) error {
+	return unstructured.SetNestedStringSlice(intent.Object, rules, hbaFields...)
+}
+
+// List prints the pg_hba rules of the cluster with warnings about them.
+func (hba pgHBA) List(ctx context.Context) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(hba)
+	if err != nil {
+		return err
+	}
+	namespace, err := hba.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, hba.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	rules, err := clusterRules(cluster)
+	if err != nil {
+		return err
+	}
+
+	entries := checkHBARules(rules)
+	if err := printHBAEntries(hba.Out, entries, hba.JSON); err != nil {
+		return err
+	}
+	if !hba.JSON {
+		warnHBAEntries(hba.ErrOut, entries)
+	}
+	return nil
+}
+
+// Add inserts text into the pg_hba rules of the cluster.
+func (hba pgHBA) Add(ctx context.Context, text string) error {
+	rule, err := parseHBARule(text)
+	if err != nil {
+		return fmt.Errorf("invalid pg_hba rule %q: %w", text, err)
+	}
+
+	mapping, client, err := v1beta1.NewPostgresClusterClient(hba)
+	if err != nil {
+		return err
+	}
+	namespace, err := hba.Namespace()
+	if err != nil {
+		return err
+	}
+
+	// Fetch the cluster to (1) see its current rules and (2) extract CLI managed fields.
+	cluster, err := client.Namespace(namespace).Get(ctx, hba.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := hba.checkOwners(cluster, mapping.Resource.Resource); err != nil {
+		return err
+	}
+	rules, err := clusterRules(cluster)
+	if err != nil {
+		return err
+	}
+	if index := findHBARule(rules, rule); index >= 0 {
+		return fmt.Errorf("pg_hba rule %q already exists at position %d", text, index+1)
+	}
+
+	position := hba.Position
+	if position == 0 {
+		position = len(rules) + 1
+	}
+	if position < 1 || position > len(rules)+1 {
+		return fmt.Errorf("invalid --position %d: expected 1 through %d", position, len(rules)+1)
+	}
+	rules = slices.Insert(rules, position-1, rule.String())
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, hba.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := hba.setIntent(intent, rules); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, hba.Config, client.Namespace(namespace), hba.ClusterName,
+		intent, false); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(hba.Out, "%s/%s pg_hba rule %d added\n",
+		mapping.Resource.Resource, hba.ClusterName, position)
+
+	// Report only the problems that involve the new rule.
+	entries := checkHBARules(rules)
+	warnHBAEntries(hba.ErrOut, slices.DeleteFunc(entries, func(entry hbaEntry) bool {
+		return entry.Index != position && entry.ShadowedBy != position
+	}))
+	return nil
+}
+
+// Remove deletes the rule that is the same as text from the pg_hba rules of
+// the cluster.
+func (hba pgHBA) Remove(ctx context.Context, text string) error {
+	rule, err := parseHBARule(text)
+	if err != nil {
+		return fmt.Errorf("invalid pg_hba rule %q: %w", text, err)
+	}
+
+	mapping, client, err := v1beta1.NewPostgresClusterClient(hba)
+	if err != nil {
+		return err
+	}
+	namespace, err := hba.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, hba.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if err := hba.checkOwners(cluster, mapping.Resource.Resource); err != nil {
+		return err
+	}
+	rules, err := clusterRules(cluster)
+	if err != nil {
+		return err
+	}
+	index := findHBARule(rules, rule)
+	if index < 0 {
+		return fmt.Errorf("pg_hba rule %q not found in %s/%s",
+			text, mapping.Resource.Resource, hba.ClusterName)
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, hba.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := hba.setIntent(intent, slices.Delete(rules, index, index+1)); err != nil {
+		return err
+	}
+
+	if !confirm(hba.In, hba.Out, "WARNING: Clients that rely on this rule will no longer be able to connect."+
+		"\nAre you sure you want to continue? (yes/no): ") {
+		return nil
+	}
+
+	if _, err := applyCluster(ctx, hba.Config, client.Namespace(namespace), hba.ClusterName,
+		intent, false); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(hba.Out, "%s/%s pg_hba rule %d removed\n",
+		mapping.Resource.Resource, hba.ClusterName, index+1)
+	return nil
+}
@@ -0,0 +1,207 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestParseHBARule(t *testing.T) {
+	for _, tt := range []struct {
+		Rule     string
+		Expected string
+	}{
+		{Rule: "local all postgres peer", Expected: "local all postgres peer"},
+		{Rule: "  hostssl  all\tall   0.0.0.0/0   scram-sha-256  ", Expected: "hostssl all all 0.0.0.0/0 scram-sha-256"},
+		{Rule: "host app,reports +readers 10.1.2.0 255.255.255.0 md5", Expected: "host app,reports +readers 10.1.2.0/24 md5"},
+		{Rule: "host all all 10.1.2.3/8 trust", Expected: "host all all 10.1.2.3/8 trust"},
+		{Rule: "host replication all ::1/128 scram-sha-256 # loopback", Expected: "host replication all ::1/128 scram-sha-256"},
+		{Rule: `host "all",zoo all .example.com ldap ldapserver=ldap.example.com ldapprefix="cn="`,
+			Expected: `host "all",zoo all .example.com ldap ldapserver=ldap.example.com ldapprefix="cn="`},
+		{Rule: "hostssl all all samenet cert clientname=DN", Expected: "hostssl all all samenet cert clientname=DN"},
+	} {
+		t.Run(tt.Rule, func(t *testing.T) {
+			rule, err := parseHBARule(tt.Rule)
+			assert.NilError(t, err)
+			assert.Equal(t, rule.String(), tt.Expected)
+		})
+	}
+
+	for _, tt := range []struct {
+		Rule  string
+		Error string
+	}{
+		{Rule: "", Error: "missing connection type"},
+		{Rule: "hostx all all all trust", Error: `invalid connection type "hostx"`},
+		{Rule: "host all", Error: "missing user"},
+		{Rule: "host all all", Error: "missing address"},
+		{Rule: "host all all 10.0.0.0/33 trust", Error: `invalid address "10.0.0.0/33"`},
+		{Rule: "host all all 10.0.0.0 trust", Error: `invalid netmask "trust"`},
+		{Rule: "host all all 10.0.0.0", Error: "missing netmask"},
+		{Rule: "host all all all", Error: "missing authentication method"},
+		{Rule: "host all all all scram", Error: `invalid authentication method "scram"`},
+		{Rule: "host all all all peer", Error: `"peer" is only valid for "local"`},
+		{Rule: "host all all all cert", Error: `"cert" is only valid for "hostssl"`},
+		{Rule: "host all all all ldap server", Error: `invalid authentication option "server"`},
+		{Rule: "host app, all all trust", Error: `invalid database "app,": empty name`},
+		{Rule: `host "app all all trust`, Error: "unterminated quoted string"},
+		{Rule: "host all all all trust\nlocal all all trust", Error: "single line"},
+	} {
+		t.Run(tt.Rule, func(t *testing.T) {
+			_, err := parseHBARule(tt.Rule)
+			assert.ErrorContains(t, err, tt.Error)
+		})
+	}
+}
+
+func TestCheckHBARules(t *testing.T) {
+	entries := checkHBARules([]string{
+		"hostssl all all 10.0.0.0/8 scram-sha-256",
+		"hostssl app rhino 10.1.0.0/16 md5",
+		"host all all 10.1.0.0/16 md5",
+		"hostnossl app all 10.1.2.0/24 reject",
+		"host replication all 10.0.0.0/8 scram-sha-256",
+		"host all all 0.0.0.0/0 scram-sha-256",
+		"host zoo rhino 192.168.0.0/16 md5",
+		"local all all peer",
+		"local all postgres trust",
+		"host all all",
+	})
+
+	shadowed := map[int]int{}
+	for _, entry := range entries {
+		if entry.ShadowedBy > 0 {
+			shadowed[entry.Index] = entry.ShadowedBy
+		}
+	}
+	assert.DeepEqual(t, shadowed, map[int]int{
+		2: 1, // same type; all covers app and rhino; network contains network
+		4: 3, // host covers hostnossl
+		7: 6, // 0.0.0.0/0 contains everything
+		9: 8, // all covers postgres
+	})
+	assert.Equal(t, entries[9].hbaRule == nil, true)
+	assert.Equal(t, entries[9].Error, "missing address")
+
+	var buf bytes.Buffer
+	warnHBAEntries(&buf, entries)
+	assert.Equal(t, buf.String(), ``+
+		"WARNING: rule 2 is shadowed by rule 1 and can never match\n"+
+		"WARNING: rule 4 is shadowed by rule 3 and can never match\n"+
+		"WARNING: rule 7 is shadowed by rule 6 and can never match\n"+
+		"WARNING: rule 9 is shadowed by rule 8 and can never match\n"+
+		"WARNING: rule 10 is invalid: missing address\n")
+}
+
+func TestPrintHBAEntries(t *testing.T) {
+	entries := checkHBARules([]string{
+		"hostssl all all 10.0.0.0/8 scram-sha-256",
+		"host app rhino 10.1.0.0/16 ldap ldapserver=ldap",
+		"bogus",
+	})
+
+	var buf bytes.Buffer
+	assert.NilError(t, printHBAEntries(&buf, entries, false))
+	assert.Equal(t, buf.String(), ``+
+		"INDEX   TYPE      DATABASE   USER    ADDRESS       METHOD          OPTIONS\n"+
+		"1       hostssl   all        all     10.0.0.0/8    scram-sha-256   \n"+
+		"2       host      app        rhino   10.1.0.0/16   ldap            ldapserver=ldap\n")
+
+	buf.Reset()
+	assert.NilError(t, printHBAEntries(&buf, entries[1:], true))
+	assert.Equal(t, buf.String(), `[
+  {
+    "index": 2,
+    "rule": "host app rhino 10.1.0.0/16 ldap ldapserver=ldap",
+    "type": "host",
+    "database": [
+      "app"
+    ],
+    "user": [
+      "rhino"
+    ],
+    "address": "10.1.0.0/16",
+    "method": "ldap",
+    "options": [
+      "ldapserver=ldap"
+    ]
+  },
+  {
+    "index": 3,
+    "rule": "bogus",
+    "error": "invalid connection type \"bogus\": expected one of local, host, hostssl, hostnossl, hostgssenc, hostnogssenc"
+  }
+]
+`)
+}
+
+func TestFindHBARule(t *testing.T) {
+	rules := []string{
+		"local all all peer",
+		"host  all  all  10.1.2.0 255.255.255.0  md5",
+	}
+
+	rule, err := parseHBARule("host all all 10.1.2.0/24 md5")
+	assert.NilError(t, err)
+	assert.Equal(t, findHBARule(rules, rule), 1)
+
+	rule, err = parseHBARule("host all all 10.1.2.0/24 trust")
+	assert.NilError(t, err)
+	assert.Equal(t, findHBARule(rules, rule), -1)
+}
+
+func TestPGHBASetIntent(t *testing.T) {
+	intent := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{"patroni": map[string]any{"switchover": map[string]any{"enabled": true}}},
+	}}
+
+	assert.NilError(t, pgHBA{}.setIntent(intent, []string{"local all all peer"}))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  patroni:
+    dynamicConfiguration:
+      postgresql:
+        pg_hba:
+        - local all all peer
+    switchover:
+      enabled: true
+	`))
+}
+
+func TestPGHBACheckOwners(t *testing.T) {
+	hba := pgHBA{
+		Config:      &internal.Config{Patch: internal.PatchConfig{FieldManager: "kubectl-pgo"}},
+		ClusterName: "hippo",
+	}
+	cluster := func(managers ...string) *unstructured.Unstructured {
+		u := new(unstructured.Unstructured)
+		var entries []metav1.ManagedFieldsEntry
+		for _, manager := range managers {
+			entries = append(entries, metav1.ManagedFieldsEntry{
+				Manager: manager, Operation: metav1.ManagedFieldsOperationApply,
+				FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(
+					`{"f:spec":{"f:patroni":{"f:dynamicConfiguration":{"f:postgresql":{"f:pg_hba":{}}}}}}`,
+				)},
+			})
+		}
+		u.SetManagedFields(entries)
+		return u
+	}
+
+	assert.NilError(t, hba.checkOwners(cluster(), "postgresclusters"))
+	assert.NilError(t, hba.checkOwners(cluster("kubectl-pgo"), "postgresclusters"))
+
+	err := hba.checkOwners(cluster("kubectl-pgo", "argocd", "helm"), "postgresclusters")
+	assert.ErrorContains(t, err,
+		`the pg_hba rules of postgresclusters/hippo are managed by "argocd", "helm"; change them with that client`)
+}
@@ -0,0 +1,533 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newLogsCommand returns the logs command of the PGO plugin. It prints the
+// logs of every component of a PostgresCluster, interleaved by timestamp.
+func newLogsCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs CLUSTER_NAME",
+		Short: "Print the logs of a PostgresCluster",
+		Long: `Print the logs of a PostgresCluster from every Pod, interleaved by timestamp.
+Each line is prefixed by the Pod and the container or log file it came from.
+
+The logs of each component are:
+    postgres    Postgres log files of each instance
+    patroni     Patroni log files and the database container of each instance
+    pgbackrest  pgBackRest log files and the pgbackrest containers of each
+                instance and the repo host
+    pgbouncer   the pgbouncer containers of PgBouncer
+    exporter    the exporter container of each instance
+
+Log files are read with "tail" in their containers. Lines without a timestamp,
+such as the continuation of a multiline message, keep the timestamp of the
+line before them. With --follow, lines that arrive within a second of each
+other are sorted before they are printed. When Postgres rotates its log to a
+new file, --follow switches to that file within ten seconds.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    pods/log                                            [get]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Print the logs of every component of the 'hippo' postgrescluster
+pgo logs hippo
+
+# Follow the Postgres logs of one instance of the 'hippo' postgrescluster
+pgo logs hippo --component postgres --instance hippo-instance1-8gjt-0 --follow
+
+# Print the pgBackRest logs from the last hour
+pgo logs hippo --component pgbackrest --since 1h
+
+### Example output
+hippo-instance1-8gjt-0 postgresql-Mon.log 2025-03-10 14:02:11.104 UTC [95] LOG:  database system is ready to accept connections
+hippo-instance1-8gjt-0 database 2025-03-10 14:02:12,011 INFO: no action. I am (hippo-instance1-8gjt-0), the leader with the lock
+hippo-repo-host-0 db-backup.log 2025-03-10 14:03:40.512 P00   INFO: backup command begin 2.54.2`)
+
+	logs := pgLogs{Config: config}
+
+	var componentEnum = util.AllComponents
+	cmd.Flags().Var(&componentEnum, "component",
+		"only print the logs of this component. types supported: postgres,patroni,pgbackrest,pgbouncer,exporter")
+	cmd.Flags().StringVar(&logs.Instance, "instance", "", "only print the logs of this Pod")
+	cmd.Flags().BoolVarP(&logs.Follow, "follow", "f", false, "print logs as they are written")
+	cmd.Flags().DurationVar(&logs.Since, "since", 0, "only print logs newer than a relative duration like 5s, 2m, or 3h")
+	cmd.Flags().Int64Var(&logs.Tail, "tail", -1, "lines of each container and log file to print, or -1 for all of them")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		logs.ClusterName = args[0]
+		logs.Component = componentEnum.String()
+		return logs.Run(context.Background())
+	}
+
+	return cmd
+}
+
+type pgLogs struct {
+	*internal.Config
+
+	ClusterName string
+	Component   string
+	Follow      bool
+	Instance    string
+	Since       time.Duration
+	Tail        int64
+}
+
+// logTarget is a container or the log files in a container.
+type logTarget struct {
+	Pod, Container string
+
+	// List returns the log files in Container, one per line, newest first.
+	// When nil, the target is the log stream of Container.
+	List func(Executor) (string, string, error)
+
+	// Newest indicates only the first file returned by List should be read.
+	Newest bool
+
+	// Rotate, when set, is the glob of the files that replace the newest one
+	// when its log rotates to a new file name. With --follow, a Newest target
+	// switches to the newest file that matches it.
+	Rotate string
+}
+
+// logSource is an open stream of log lines.
+type logSource struct {
+	Prefix string
+
+	// Timestamps indicates each line begins with an RFC 3339 timestamp
+	// and a space, the same as [corev1.PodLogOptions.Timestamps].
+	Timestamps bool
+
+	// Rotates indicates the stream switches to newer log files. A line
+	// "==> FILE <==" comes before the lines of each newer FILE.
+	Rotates bool
+
+	Open func(context.Context) (io.ReadCloser, error)
+}
+
+// logLine is one line of a log and when it was written and received.
+type logLine struct {
+	Prefix, Text string
+	Time         time.Time
+	received     time.Time
+}
+
+// logTargets returns the containers and log files of component in pods.
+// When instance is not blank, only that Pod is included.
+func logTargets(pods []corev1.Pod, component, instance string, hasInstrumentation bool) []logTarget {
+	var targets []logTarget
+
+	want := func(c string) bool { return component == "" || component == c }
+	has := func(pod corev1.Pod, container string) bool {
+		return slices.ContainsFunc(pod.Spec.Containers,
+			func(c corev1.Container) bool { return c.Name == container })
+	}
+
+	for _, pod := range pods {
+		if instance != "" && pod.Name != instance {
+			continue
+		}
+
+		labels := pod.GetLabels()
+		_, repoHost := labels[util.LabelPGBackRestDedicated]
+
+		switch {
+		case labels[util.LabelData] == util.DataPostgres:
+			if want("postgres") {
+				targets = append(targets, logTarget{
+					Pod: pod.Name, Container: util.ContainerDatabase, Newest: true,
+					List: func(exec Executor) (string, string, error) {
+						return exec.listPGLogFiles(1, hasInstrumentation)
+					},
+					Rotate: pgLogFilePattern(hasInstrumentation),
+				})
+			}
+			if want("patroni") {
+				targets = append(targets,
+					logTarget{Pod: pod.Name, Container: util.ContainerDatabase},
+					logTarget{Pod: pod.Name, Container: util.ContainerDatabase, Newest: true,
+						List: Executor.listPatroniLogFiles},
+				)
+			}
+			if want("pgbackrest") {
+				targets = append(targets, logTarget{
+					Pod: pod.Name, Container: util.ContainerDatabase,
+					List: Executor.listBackrestLogFiles,
+				})
+				if has(pod, util.ContainerPGBackrest) {
+					targets = append(targets, logTarget{Pod: pod.Name, Container: util.ContainerPGBackrest})
+				}
+			}
+			if want("exporter") && has(pod, util.ContainerExporter) {
+				targets = append(targets, logTarget{Pod: pod.Name, Container: util.ContainerExporter})
+			}
+
+		case repoHost:
+			if want("pgbackrest") {
+				targets = append(targets,
+					logTarget{Pod: pod.Name, Container: util.ContainerPGBackrest},
+					logTarget{Pod: pod.Name, Container: util.ContainerPGBackrest,
+						List: Executor.listBackrestRepoHostLogFiles},
+				)
+			}
+
+		case labels[util.LabelRole] == util.RolePGBouncer:
+			if want("pgbouncer") && has(pod, util.ContainerPGBouncer) {
+				targets = append(targets, logTarget{Pod: pod.Name, Container: util.ContainerPGBouncer})
+			}
+		}
+	}
+
+	return targets
+}
+
+// logTimestamp matches the timestamps at the start of Postgres, Patroni, and
+// pgBackRest log lines, and those of JSON log records.
+var logTimestamp = regexp.MustCompile(
+	`^(?:\{"timestamp":")?(\d{4}-\d{2}-\d{2})[ T](\d{2}:\d{2}:\d{2})(?:[.,](\d{1,9}))?(Z| ?[+-]\d{2}:?\d{2})?`)
+
+// parseLogTimestamp returns the time at the start of line. Times without
+// a numeric offset, including those with a zone abbreviation, are UTC.
+func parseLogTimestamp(line string) (time.Time, bool) {
+	m := logTimestamp.FindStringSubmatch(line)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	value := m[1] + "T" + m[2]
+	if m[3] != "" {
+		value += "." + m[3]
+	}
+
+	zone := strings.TrimSpace(m[4])
+	switch {
+	case zone == "" || zone == "Z":
+		value += "Z"
+	case len(zone) == 5:
+		value += zone[:3] + ":" + zone[3:]
+	default:
+		value += zone
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	return t, err == nil
+}
+
+// logFileHeader matches the line before the lines of each newer file in a
+// logSource that Rotates.
+var logFileHeader = regexp.MustCompile(`^==> (.+) <==$`)
+
+// followNewestLogFile is a bash script that follows a log file like "tail -F"
+// and switches to a newer file that matches a glob, such as when Postgres
+// rotates its log from "postgresql-Mon.log" to "postgresql-Tue.log". It prints
+// "==> FILE <==" before the lines of each newer file.
+// - $1: the number of lines of the first file to print, as with "tail -n"
+// - $2: the first file
+// - $3: the glob of newer files
+const followNewestLogFile = `file="$2"
+tail -n "$1" -F -- "$file" & pid=$!
+trap 'kill $pid 2>/dev/null' EXIT
+while sleep 10; do
+  newest=$(ls -1dt -- $3 2>/dev/null | head -n 1)
+  if [ -n "$newest" ] && [ "$newest" != "$file" ]; then
+    kill $pid; wait $pid || true
+    file="$newest"; printf '==> %s <==\n' "$file"
+    tail -n +1 -F -- "$file" & pid=$!
+  fi
+done`
+
+// readLogLines sends the lines of source to lines until it ends or ctx is
+// done. Lines older than since are skipped.
+func readLogLines(ctx context.Context, source logSource, since time.Time, lines chan<- logLine) error {
+	stream, err := source.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	var last time.Time
+	prefix := source.Prefix
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		if m := logFileHeader.FindStringSubmatch(scanner.Text()); source.Rotates && m != nil {
+			pod, _, _ := strings.Cut(source.Prefix, " ")
+			prefix = pod + " " + path.Base(m[1])
+			continue
+		}
+		line := logLine{Prefix: prefix, Text: scanner.Text(), Time: last}
+
+		if source.Timestamps {
+			if stamp, text, ok := strings.Cut(line.Text, " "); ok {
+				if t, err := time.Parse(time.RFC3339Nano, stamp); err == nil {
+					line.Text, line.Time = text, t
+				}
+			}
+		} else if t, ok := parseLogTimestamp(line.Text); ok {
+			line.Time = t
+		}
+
+		last = line.Time
+		if !since.IsZero() && line.Time.Before(since) {
+			continue
+		}
+
+		line.received = time.Now()
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return scanner.Err()
+}
+
+// mergeLogs writes lines to w sorted by their timestamps. When window is zero,
+// nothing is written until lines is closed. Otherwise, lines are written once
+// they have been received for window.
+func mergeLogs(w io.Writer, lines <-chan logLine, window time.Duration) error {
+	var pending []logLine
+
+	flush := func(before time.Time) error {
+		sort.SliceStable(pending, func(i, j int) bool {
+			return pending[i].Time.Before(pending[j].Time)
+		})
+
+		var buf bytes.Buffer
+		var keep []logLine
+		for _, line := range pending {
+			if !before.IsZero() && line.received.After(before) {
+				keep = append(keep, line)
+				continue
+			}
+			buf.WriteString(line.Prefix)
+			buf.WriteByte(' ')
+			buf.WriteString(line.Text)
+			buf.WriteByte('\n')
+		}
+		pending = keep
+
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+
+	var tick <-chan time.Time
+	if window > 0 {
+		ticker := time.NewTicker(window / 2)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return flush(time.Time{})
+			}
+			pending = append(pending, line)
+
+		case now := <-tick:
+			if err := flush(now.Add(-window)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sources opens the log files and containers of targets.
+func (logs pgLogs) sources(
+	namespace string, client kubernetes.Interface, executor func(pod, container string) Executor,
+	targets []logTarget,
+) []logSource {
+	var sources []logSource
+
+	tail := "+1"
+	if logs.Tail >= 0 {
+		tail = strconv.FormatInt(logs.Tail, 10)
+	}
+
+	for _, target := range targets {
+		if target.List == nil {
+			options := &corev1.PodLogOptions{
+				Container:  target.Container,
+				Follow:     logs.Follow,
+				Timestamps: true,
+			}
+			if logs.Since > 0 {
+				seconds := int64(logs.Since.Seconds())
+				options.SinceSeconds = &seconds
+			}
+			if logs.Tail >= 0 {
+				options.TailLines = &logs.Tail
+			}
+
+			pods := client.CoreV1().Pods(namespace)
+			sources = append(sources, logSource{
+				Prefix: target.Pod + " " + target.Container, Timestamps: true,
+				Open: func(ctx context.Context) (io.ReadCloser, error) {
+					return pods.GetLogs(target.Pod, options).Stream(ctx)
+				},
+			})
+			continue
+		}
+
+		// Some log files exist only when a component is configured to write
+		// them. Skip the target when there are none.
+		exec := executor(target.Pod, target.Container)
+		stdout, stderr, err := target.List(exec)
+		if err != nil || stderr != "" {
+			if !strings.Contains(stderr, "No such file or directory") {
+				_, _ = fmt.Fprintf(logs.ErrOut, "%s %s: unable to list log files: %v %s\n",
+					target.Pod, target.Container, err, strings.TrimSpace(stderr))
+			}
+			continue
+		}
+
+		files := strings.Fields(stdout)
+		if target.Newest && len(files) > 1 {
+			files = files[:1]
+		}
+
+		for _, file := range files {
+			command := []string{"tail", "-n", tail}
+			if logs.Follow {
+				command = append(command, "-F")
+			}
+			command = append(command, file)
+
+			rotates := logs.Follow && target.Newest && target.Rotate != ""
+			if rotates {
+				command = []string{"bash", "-ceu", "--", followNewestLogFile, "-", tail, file, target.Rotate}
+			}
+
+			sources = append(sources, logSource{
+				Prefix: target.Pod + " " + path.Base(file), Rotates: rotates,
+				Open: func(ctx context.Context) (io.ReadCloser, error) {
+					reader, writer := io.Pipe()
+					go func() {
+						var stderr bytes.Buffer
+						err := exec(nil, writer, &stderr, command...)
+						if err != nil && stderr.Len() > 0 {
+							err = fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+						}
+						writer.CloseWithError(err)
+					}()
+					return reader, nil
+				},
+			})
+		}
+	}
+
+	return sources
+}
+
+func (logs pgLogs) Run(ctx context.Context) error {
+	namespace, err := logs.Namespace()
+	if err != nil {
+		return err
+	}
+	_, clusterClient, err := v1beta1.NewPostgresClusterClient(logs)
+	if err != nil {
+		return err
+	}
+	rest, err := logs.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := kubernetes.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+	podExec, err := util.NewPodExecutor(rest)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clusterClient.Namespace(namespace).Get(ctx, logs.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	_, hasInstrumentation, _ := unstructured.NestedMap(cluster.Object, "spec", "instrumentation")
+
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.LabelCluster + "=" + logs.ClusterName,
+	})
+	if err != nil {
+		return err
+	}
+
+	targets := logTargets(pods.Items, logs.Component, logs.Instance, hasInstrumentation)
+	if len(targets) == 0 {
+		return fmt.Errorf("no Pods found for postgrescluster %q", logs.ClusterName)
+	}
+
+	sources := logs.sources(namespace, client, func(pod, container string) Executor {
+		return func(stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+			return podExec(namespace, pod, container, stdin, stdout, stderr, command...)
+		}
+	}, targets)
+
+	var since time.Time
+	if logs.Since > 0 {
+		since = time.Now().Add(-logs.Since)
+	}
+
+	lines := make(chan logLine)
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := readLogLines(ctx, source, since, lines); err != nil && ctx.Err() == nil {
+				_, _ = fmt.Fprintf(logs.ErrOut, "%s: %v\n", source.Prefix, err)
+			}
+		}()
+	}
+	go func() { wg.Wait(); close(lines) }()
+
+	var window time.Duration
+	if logs.Follow {
+		window = time.Second
+	}
+	return mergeLogs(logs.Out, lines, window)
+}
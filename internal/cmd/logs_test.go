@@ -0,0 +1,280 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+)
+
+func TestParseLogTimestamp(t *testing.T) {
+	for _, tt := range []struct {
+		Line     string
+		Expected string
+	}{
+		{Line: `2025-03-10 14:02:11.104 UTC [95] LOG:  ready`, Expected: "2025-03-10T14:02:11.104Z"},
+		{Line: `2025-03-10 14:02:11 UTC [95] LOG:  ready`, Expected: "2025-03-10T14:02:11Z"},
+		{Line: `2025-03-10 14:02:12,011 INFO: no action`, Expected: "2025-03-10T14:02:12.011Z"},
+		{Line: `2025-03-10 14:03:40.512 P00   INFO: backup`, Expected: "2025-03-10T14:03:40.512Z"},
+		{Line: `2025-03-10T14:03:40.512-0500 info`, Expected: "2025-03-10T19:03:40.512Z"},
+		{Line: `2025-03-10 14:03:40+02:00 info`, Expected: "2025-03-10T12:03:40Z"},
+		{Line: `{"timestamp":"2025-03-10 14:02:11.104 UTC","message":"ready"}`, Expected: "2025-03-10T14:02:11.104Z"},
+	} {
+		t.Run(tt.Line, func(t *testing.T) {
+			actual, ok := parseLogTimestamp(tt.Line)
+			assert.Assert(t, ok)
+			assert.Equal(t, actual.UTC().Format(time.RFC3339Nano), tt.Expected)
+		})
+	}
+
+	for _, line := range []string{"", "\tat continuation", "DETAIL: 2025-03-10 14:02:11"} {
+		_, ok := parseLogTimestamp(line)
+		assert.Assert(t, !ok, "%q", line)
+	}
+}
+
+func TestLogTargets(t *testing.T) {
+	pod := func(name string, labels map[string]string, containers ...string) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		for _, c := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+		}
+		return pod
+	}
+
+	pods := []corev1.Pod{
+		pod("hippo-instance1-abcd-0",
+			map[string]string{"postgres-operator.crunchydata.com/data": "postgres"},
+			"database", "pgbackrest", "exporter"),
+		pod("hippo-repo-host-0",
+			map[string]string{"postgres-operator.crunchydata.com/pgbackrest-dedicated": ""},
+			"pgbackrest"),
+		pod("hippo-pgbouncer-1234",
+			map[string]string{"postgres-operator.crunchydata.com/role": "pgbouncer"},
+			"pgbouncer"),
+	}
+
+	describe := func(targets []logTarget) []string {
+		var result []string
+		for _, target := range targets {
+			kind := "stream"
+			if target.List != nil {
+				kind = "files"
+			}
+			result = append(result, target.Pod+" "+target.Container+" "+kind)
+		}
+		return result
+	}
+
+	assert.DeepEqual(t, describe(logTargets(pods, "", "", false)), []string{
+		"hippo-instance1-abcd-0 database files",
+		"hippo-instance1-abcd-0 database stream",
+		"hippo-instance1-abcd-0 database files",
+		"hippo-instance1-abcd-0 database files",
+		"hippo-instance1-abcd-0 pgbackrest stream",
+		"hippo-instance1-abcd-0 exporter stream",
+		"hippo-repo-host-0 pgbackrest stream",
+		"hippo-repo-host-0 pgbackrest files",
+		"hippo-pgbouncer-1234 pgbouncer stream",
+	})
+
+	assert.DeepEqual(t, describe(logTargets(pods, "pgbackrest", "", false)), []string{
+		"hippo-instance1-abcd-0 database files",
+		"hippo-instance1-abcd-0 pgbackrest stream",
+		"hippo-repo-host-0 pgbackrest stream",
+		"hippo-repo-host-0 pgbackrest files",
+	})
+
+	assert.DeepEqual(t, describe(logTargets(pods, "", "hippo-pgbouncer-1234", false)), []string{
+		"hippo-pgbouncer-1234 pgbouncer stream",
+	})
+
+	assert.Equal(t, len(logTargets(pods, "exporter", "hippo-repo-host-0", false)), 0)
+}
+
+func TestReadLogLines(t *testing.T) {
+	ctx := context.Background()
+	source := func(prefix string, timestamps bool, text string) logSource {
+		return logSource{
+			Prefix: prefix, Timestamps: timestamps,
+			Open: func(context.Context) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(text)), nil
+			},
+		}
+	}
+
+	read := func(t *testing.T, source logSource, since time.Time) []logLine {
+		lines := make(chan logLine, 10)
+		assert.NilError(t, readLogLines(ctx, source, since, lines))
+		close(lines)
+
+		var result []logLine
+		for line := range lines {
+			result = append(result, line)
+		}
+		return result
+	}
+
+	t.Run("File", func(t *testing.T) {
+		lines := read(t, source("pod file", false, strings.Join([]string{
+			"2025-03-10 14:02:11.104 UTC [95] ERROR:  oops",
+			"\tcontinued",
+			"2025-03-10 14:02:12.000 UTC [95] LOG:  ready",
+		}, "\n")), time.Time{})
+
+		assert.Equal(t, len(lines), 3)
+		assert.Equal(t, lines[1].Text, "\tcontinued")
+		assert.Equal(t, lines[1].Time, lines[0].Time, "expected timestamp of the previous line")
+		assert.Equal(t, lines[2].Prefix, "pod file")
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		lines := read(t, source("pod container", true,
+			"2025-03-10T14:02:11.5Z first\n2025-03-10T14:02:13Z second\n"),
+			time.Date(2025, 3, 10, 14, 2, 12, 0, time.UTC))
+
+		assert.Equal(t, len(lines), 1)
+		assert.Equal(t, lines[0].Text, "second")
+		assert.Equal(t, lines[0].Time, time.Date(2025, 3, 10, 14, 2, 13, 0, time.UTC))
+	})
+
+	t.Run("Rotates", func(t *testing.T) {
+		text := strings.Join([]string{
+			"2025-03-10 23:59:59.000 UTC [95] LOG:  monday",
+			"==> pgdata/pg16/log/postgresql-Tue.log <==",
+			"2025-03-11 00:00:01.000 UTC [95] LOG:  tuesday",
+		}, "\n")
+
+		rotating := source("pod postgresql-Mon.log", false, text)
+		rotating.Rotates = true
+		lines := read(t, rotating, time.Time{})
+		assert.Equal(t, len(lines), 2)
+		assert.Equal(t, lines[0].Prefix, "pod postgresql-Mon.log")
+		assert.Equal(t, lines[1].Prefix, "pod postgresql-Tue.log")
+		assert.Equal(t, lines[1].Text, "2025-03-11 00:00:01.000 UTC [95] LOG:  tuesday")
+
+		// Other sources print such lines as they are.
+		assert.Equal(t, len(read(t, source("pod file", false, text), time.Time{})), 3)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		err := readLogLines(ctx, logSource{Open: func(context.Context) (io.ReadCloser, error) {
+			return nil, errors.New("boom")
+		}}, time.Time{}, nil)
+		assert.ErrorContains(t, err, "boom")
+	})
+}
+
+func TestMergeLogs(t *testing.T) {
+	at := func(second int) time.Time { return time.Date(2025, 3, 10, 14, 2, second, 0, time.UTC) }
+
+	lines := make(chan logLine, 10)
+	lines <- logLine{Prefix: "b", Text: "three", Time: at(3)}
+	lines <- logLine{Prefix: "a", Text: "one", Time: at(1)}
+	lines <- logLine{Prefix: "b", Text: "three, continued", Time: at(3)}
+	lines <- logLine{Prefix: "a", Text: "two", Time: at(2)}
+	close(lines)
+
+	var out bytes.Buffer
+	assert.NilError(t, mergeLogs(&out, lines, 0))
+	assert.Equal(t, out.String(), "a one\na two\nb three\nb three, continued\n")
+
+	t.Run("Window", func(t *testing.T) {
+		lines := make(chan logLine)
+		done := make(chan error)
+
+		var out bytes.Buffer
+		go func() { done <- mergeLogs(&out, lines, 10*time.Millisecond) }()
+
+		// Lines received together are sorted together.
+		received := time.Now()
+		lines <- logLine{Prefix: "a", Text: "late", Time: at(5), received: received}
+		lines <- logLine{Prefix: "b", Text: "early", Time: at(4), received: received}
+		time.Sleep(50 * time.Millisecond)
+		lines <- logLine{Prefix: "c", Text: "earliest", Time: at(1), received: time.Now()}
+		close(lines)
+
+		assert.NilError(t, <-done)
+		assert.Equal(t, out.String(), "b early\na late\nc earliest\n")
+	})
+}
+
+func TestPGLogsSources(t *testing.T) {
+	ctx := context.Background()
+
+	var errOut bytes.Buffer
+	logs := pgLogs{
+		Config: &internal.Config{IOStreams: genericclioptions.IOStreams{ErrOut: &errOut}},
+		Tail:   5, Follow: true,
+	}
+
+	var commands []string
+	executor := func(pod, container string) Executor {
+		return func(stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+			commands = append(commands, pod+"/"+container+": "+strings.Join(command, " "))
+			if command[0] == "tail" {
+				_, err := io.WriteString(stdout, "2025-03-10 14:02:11.104 UTC [95] LOG:  ready\n")
+				return err
+			}
+			if strings.Contains(command[len(command)-1], "patroni") {
+				_, err := io.WriteString(stderr, "ls: cannot access 'pgdata/patroni/log/*.*': No such file or directory\n")
+				return err
+			}
+			_, err := io.WriteString(stdout, "pgdata/pgbackrest/log/db-backup.log\npgdata/pgbackrest/log/db-expire.log\n")
+			return err
+		}
+	}
+
+	sources := logs.sources("zoo", fake.NewSimpleClientset(), executor, []logTarget{
+		{Pod: "pod", Container: "database"},
+		{Pod: "pod", Container: "database", Newest: true, List: Executor.listPatroniLogFiles},
+		{Pod: "pod", Container: "database", List: Executor.listBackrestLogFiles},
+	})
+
+	var prefixes []string
+	for _, source := range sources {
+		prefixes = append(prefixes, source.Prefix)
+	}
+	assert.DeepEqual(t, prefixes, []string{"pod database", "pod db-backup.log", "pod db-expire.log"})
+	assert.Equal(t, errOut.String(), "", "expected missing files to be skipped quietly")
+
+	lines := make(chan logLine, 10)
+	assert.NilError(t, readLogLines(ctx, sources[1], time.Time{}, lines))
+	assert.Equal(t, (<-lines).Text, "2025-03-10 14:02:11.104 UTC [95] LOG:  ready")
+	assert.Equal(t, commands[len(commands)-1],
+		"pod/database: tail -n 5 -F pgdata/pgbackrest/log/db-backup.log")
+
+	// Postgres logs that rotate to new file names are followed by a script.
+	sources = logs.sources("zoo", fake.NewSimpleClientset(), executor, []logTarget{
+		{Pod: "pod", Container: "database", Newest: true, List: Executor.listBackrestLogFiles,
+			Rotate: "pgdata/pg[0-9][0-9]/log/*"},
+	})
+	assert.Equal(t, len(sources), 1)
+	assert.Assert(t, sources[0].Rotates)
+	_ = readLogLines(ctx, sources[0], time.Time{}, make(chan logLine, 10))
+	assert.Equal(t, commands[len(commands)-1], "pod/database: bash -ceu -- "+followNewestLogFile+
+		" - 5 pgdata/pgbackrest/log/db-backup.log pgdata/pg[0-9][0-9]/log/*")
+
+	// Without --follow, the file is read once.
+	logs.Follow = false
+	sources = logs.sources("zoo", fake.NewSimpleClientset(), executor, []logTarget{
+		{Pod: "pod", Container: "database", Newest: true, List: Executor.listBackrestLogFiles,
+			Rotate: "pgdata/pg[0-9][0-9]/log/*"},
+	})
+	assert.Assert(t, !sources[0].Rotates)
+}
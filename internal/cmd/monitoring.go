@@ -0,0 +1,487 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newMonitoringCommand returns the monitoring command of the PGO plugin.
+// Subcommands of monitoring manage the Postgres metrics exporter in
+// "spec.monitoring.pgmonitor.exporter".
+func newMonitoringCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitoring",
+		Short: "Manage the metrics exporter of a PostgresCluster",
+		Long: `Manage the Postgres metrics exporter of a PostgresCluster and show its metrics.
+
+Changes are sent using server-side apply, so only settings made by this command
+can be changed by it. Overwriting settings owned by another client may require
+the --force-conflicts flag.`,
+	}
+
+	cmd.AddCommand(
+		newMonitoringDisableCommand(config),
+		newMonitoringEnableCommand(config),
+		newMonitoringMetricsCommand(config),
+	)
+
+	return cmd
+}
+
+func newMonitoringEnableCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable CLUSTER_NAME",
+		Short: "Add the metrics exporter to a PostgresCluster",
+		Long: `Add the metrics exporter to a PostgresCluster by setting
+"spec.monitoring.pgmonitor.exporter". PGO adds an exporter container to each
+instance Pod, which rolls out one Pod at a time.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Add the metrics exporter to the 'hippo' postgrescluster
+pgo monitoring enable hippo
+
+### Example output
+postgresclusters/hippo monitoring enabled`)
+
+	monitoring := pgMonitoring{Config: config}
+
+	cmd.Flags().BoolVar(&monitoring.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the exporter settings")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		monitoring.ClusterName = args[0]
+		return monitoring.Enable(context.Background())
+	}
+
+	return cmd
+}
+
+func newMonitoringDisableCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable CLUSTER_NAME",
+		Short: "Remove the metrics exporter from a PostgresCluster",
+		Long: `Remove the metrics exporter from a PostgresCluster by removing
+"spec.monitoring". PGO removes the exporter container from each instance Pod,
+which rolls out one Pod at a time. Only an exporter added by
+"pgo monitoring enable" can be removed.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Remove the metrics exporter from the 'hippo' postgrescluster
+pgo monitoring disable hippo
+
+### Example output
+postgresclusters/hippo monitoring disabled`)
+
+	monitoring := pgMonitoring{Config: config}
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		monitoring.ClusterName = args[0]
+		return monitoring.Disable(context.Background())
+	}
+
+	return cmd
+}
+
+func newMonitoringMetricsCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics CLUSTER_NAME",
+		Short: "Show the metrics of the exporter of each instance",
+		Long: `Show the metrics of the exporter of each instance Pod. Metrics are read through
+the pod proxy of the Kubernetes API server.
+
+By default, metrics of replication lag, connections, and WAL archiving are
+shown. Use --filter to show every metric whose name starts with a prefix.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/proxy                                          [get]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Show the replication, connection, and archive metrics of 'hippo'
+pgo monitoring metrics hippo
+
+# Show every metric about database sizes
+pgo monitoring metrics hippo --filter ccp_database_size
+
+### Example output
+POD                      METRIC                                                  LABELS             VALUE
+hippo-instance1-8kds-0   ccp_archive_command_status_failed_count                 server=localhost   0
+hippo-instance1-8kds-0   ccp_archive_command_status_seconds_since_last_archive   server=localhost   41.2
+hippo-instance1-8kds-0   ccp_connection_stats_active                             server=localhost   3
+hippo-instance1-8kds-0   ccp_connection_stats_max_connections                    server=localhost   100
+hippo-instance1-8kds-0   ccp_connection_stats_total                              server=localhost   12
+hippo-instance1-8kds-0   ccp_replication_lag_size_bytes                          replica=10.0.3.9   0
+hippo-instance1-z4rt-0   ccp_replication_lag_replay_time                         server=localhost   0.8`)
+
+	monitoring := pgMonitoring{Config: config}
+
+	cmd.Flags().StringVar(&monitoring.Filter, "filter", "", "show every metric whose name starts with this, such as ccp_")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		monitoring.ClusterName = args[0]
+		return monitoring.Metrics(context.Background())
+	}
+
+	return cmd
+}
+
+type pgMonitoring struct {
+	*internal.Config
+
+	ClusterName    string
+	Filter         string
+	ForceConflicts bool
+}
+
+// exporterPort is the port of the metrics endpoint of the exporter.
+const exporterPort = "9187"
+
+// selectedMetrics are the pgMonitor metrics that are shown by default.
+// - https://github.com/CrunchyData/pgmonitor/tree/main/postgres_exporter
+var selectedMetrics = []string{
+	"ccp_archive_command_status_failed_count",
+	"ccp_archive_command_status_seconds_since_last_archive",
+	"ccp_archive_command_status_seconds_since_last_fail",
+	"ccp_connection_stats_active",
+	"ccp_connection_stats_idle",
+	"ccp_connection_stats_idle_in_txn",
+	"ccp_connection_stats_max_connections",
+	"ccp_connection_stats_total",
+	"ccp_is_in_recovery_status",
+	"ccp_replication_lag_received_time",
+	"ccp_replication_lag_replay_time",
+	"ccp_replication_lag_size_bytes",
+}
+
+// metricSample is one sample of the Prometheus text format.
+type metricSample struct {
+	Name   string
+	Labels []string
+	Value  string
+}
+
+// parseMetrics returns the samples of the Prometheus text format.
+// - https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format
+func parseMetrics(text string) ([]metricSample, error) {
+	var samples []metricSample
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var sample metricSample
+		end := strings.IndexAny(line, "{ \t")
+		if end < 0 {
+			return nil, fmt.Errorf("invalid sample %q", line)
+		}
+		sample.Name, line = line[:end], line[end:]
+
+		if strings.HasPrefix(line, "{") {
+			labels, rest, err := parseLabels(line[1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid sample of %s: %w", sample.Name, err)
+			}
+			sample.Labels, line = labels, rest
+		}
+
+		// The value may be followed by a timestamp.
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid sample of %s: missing value", sample.Name)
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sample of %s: %w", sample.Name, err)
+		}
+		sample.Value = strconv.FormatFloat(value, 'f', -1, 64)
+
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
+
+// parseLabels returns the labels that begin text as "name=value" and the text
+// after them.
+func parseLabels(text string) ([]string, string, error) {
+	var labels []string
+	for {
+		text = strings.TrimLeft(text, " \t,")
+		if strings.HasPrefix(text, "}") {
+			return labels, text[1:], nil
+		}
+
+		name, rest, ok := strings.Cut(text, "=")
+		if !ok || !strings.HasPrefix(rest, `"`) {
+			return nil, "", fmt.Errorf("invalid labels")
+		}
+
+		var value strings.Builder
+		i := 1
+		for ; i < len(rest) && rest[i] != '"'; i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+				switch rest[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(rest[i])
+				}
+				continue
+			}
+			value.WriteByte(rest[i])
+		}
+		if i >= len(rest) {
+			return nil, "", fmt.Errorf("unterminated label value")
+		}
+
+		labels = append(labels, strings.TrimSpace(name)+"="+value.String())
+		text = rest[i+1:]
+	}
+}
+
+// filterMetrics returns the samples that are selected by default or, when
+// prefix is not empty, whose name starts with prefix.
+func filterMetrics(samples []metricSample, prefix string) []metricSample {
+	var filtered []metricSample
+	for _, sample := range samples {
+		if (prefix == "" && slices.Contains(selectedMetrics, sample.Name)) ||
+			(prefix != "" && strings.HasPrefix(sample.Name, prefix)) {
+			filtered = append(filtered, sample)
+		}
+	}
+	slices.SortStableFunc(filtered, func(a, b metricSample) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return filtered
+}
+
+// podMetrics are the metrics of one Pod.
+type podMetrics struct {
+	Pod     string
+	Samples []metricSample
+}
+
+func printMetrics(w io.Writer, metrics []podMetrics) error {
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "POD\tMETRIC\tLABELS\tVALUE\n"); err != nil {
+		return err
+	}
+	for _, m := range metrics {
+		for _, sample := range m.Samples {
+			if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\n",
+				m.Pod, sample.Name, strings.Join(sample.Labels, ","), sample.Value,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// enableIntent adds the exporter to intent.
+func (monitoring pgMonitoring) enableIntent(intent *unstructured.Unstructured) error {
+	fields := []string{"spec", "monitoring", "pgmonitor", "exporter"}
+	if _, found, _ := unstructured.NestedMap(intent.Object, fields...); found {
+		return nil
+	}
+	return unstructured.SetNestedMap(intent.Object, map[string]any{}, fields...)
+}
+
+// disableIntent removes the exporter from intent. It returns false when this
+// client did not add the exporter.
+func (monitoring pgMonitoring) disableIntent(intent *unstructured.Unstructured) bool {
+	if _, found, _ := unstructured.NestedMap(intent.Object, "spec", "monitoring", "pgmonitor", "exporter"); !found {
+		return false
+	}
+
+	unstructured.RemoveNestedField(intent.Object, "spec", "monitoring")
+	if m, found, _ := unstructured.NestedMap(intent.Object, "spec"); found && len(m) == 0 {
+		unstructured.RemoveNestedField(intent.Object, "spec")
+	}
+	return true
+}
+
+// modify applies the changes of change to the fields of the cluster that this
+// client manages. It returns the mapping of the cluster.
+func (monitoring pgMonitoring) modify(ctx context.Context,
+	change func(mapping *meta.RESTMapping, intent *unstructured.Unstructured) error,
+) (*meta.RESTMapping, error) {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(monitoring)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := monitoring.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, monitoring.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, monitoring.Patch.FieldManager); err != nil {
+		return nil, err
+	}
+	if err := change(mapping, intent); err != nil {
+		return nil, err
+	}
+	_, err = applyCluster(ctx, monitoring.Config, client.Namespace(namespace), monitoring.ClusterName,
+		intent, monitoring.ForceConflicts)
+	return mapping, err
+}
+
+// Enable adds the exporter to the cluster.
+func (monitoring pgMonitoring) Enable(ctx context.Context) error {
+	mapping, err := monitoring.modify(ctx, func(_ *meta.RESTMapping, intent *unstructured.Unstructured) error {
+		return monitoring.enableIntent(intent)
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(monitoring.Out, "%s/%s monitoring enabled\n", mapping.Resource.Resource, monitoring.ClusterName)
+	return nil
+}
+
+// Disable removes the exporter from the cluster.
+func (monitoring pgMonitoring) Disable(ctx context.Context) error {
+	mapping, err := monitoring.modify(ctx, func(mapping *meta.RESTMapping, intent *unstructured.Unstructured) error {
+		if !monitoring.disableIntent(intent) {
+			return fmt.Errorf("monitoring of %s/%s was not enabled by this command",
+				mapping.Resource.Resource, monitoring.ClusterName)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(monitoring.Out, "%s/%s monitoring disabled\n", mapping.Resource.Resource, monitoring.ClusterName)
+	return nil
+}
+
+// Metrics reads the metrics of the exporter of each running instance Pod and
+// prints those that are selected.
+func (monitoring pgMonitoring) Metrics(ctx context.Context) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(monitoring)
+	if err != nil {
+		return err
+	}
+	namespace, err := monitoring.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, monitoring.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	exporter, found, _ := unstructured.NestedMap(cluster.Object, "spec", "monitoring", "pgmonitor", "exporter")
+	if !found {
+		return fmt.Errorf("postgrescluster %q does not have the metrics exporter", monitoring.ClusterName)
+	}
+
+	// The exporter serves HTTPS when it has a certificate.
+	scheme := "http"
+	if _, ok := exporter["customTLSSecret"]; ok {
+		scheme = "https"
+	}
+
+	rest, err := monitoring.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	core, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+
+	pods, err := core.Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.DBInstanceLabels(monitoring.ClusterName),
+	})
+	if err != nil {
+		return err
+	}
+
+	var metrics []podMetrics
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || !slices.ContainsFunc(pod.Spec.Containers,
+			func(c corev1.Container) bool { return c.Name == util.ContainerExporter }) {
+			continue
+		}
+
+		body, err := core.Pods(namespace).ProxyGet(scheme, pod.Name, exporterPort, "/metrics", nil).DoRaw(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", pod.Name, err)
+		}
+		samples, err := parseMetrics(string(body))
+		if err != nil {
+			return fmt.Errorf("%s: %w", pod.Name, err)
+		}
+		metrics = append(metrics, podMetrics{Pod: pod.Name, Samples: filterMetrics(samples, monitoring.Filter)})
+	}
+	if len(metrics) == 0 {
+		return fmt.Errorf("no running exporters found for postgrescluster %q", monitoring.ClusterName)
+	}
+
+	return printMetrics(monitoring.Out, metrics)
+}
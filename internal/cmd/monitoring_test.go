@@ -0,0 +1,104 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+const exporterMetrics = `# HELP ccp_connection_stats_active Total non-idle connections
+# TYPE ccp_connection_stats_active gauge
+ccp_connection_stats_active{server="localhost:5432"} 3
+ccp_connection_stats_max_connections{server="localhost:5432"} 100
+ccp_database_size_bytes{dbname="hippo",server="localhost:5432"} 8.4017823e+07
+ccp_database_size_bytes{dbname="postgres",server="localhost:5432"} 7.6e+06 1741530000000
+ccp_replication_lag_size_bytes{replica="10.0.3.9",replica_hostname="",replica_port="44380",server="localhost:5432"} 0
+ccp_archive_command_status_seconds_since_last_archive{server="localhost:5432"} 41.2
+pg_exporter_last_scrape_error 0
+odd_label{path="C:\\pg",quote="a \"b\", c",line="x\ny"} NaN
+`
+
+func TestParseMetrics(t *testing.T) {
+	samples, err := parseMetrics(exporterMetrics)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, samples, []metricSample{
+		{Name: "ccp_connection_stats_active", Labels: []string{"server=localhost:5432"}, Value: "3"},
+		{Name: "ccp_connection_stats_max_connections", Labels: []string{"server=localhost:5432"}, Value: "100"},
+		{Name: "ccp_database_size_bytes", Labels: []string{"dbname=hippo", "server=localhost:5432"}, Value: "84017823"},
+		{Name: "ccp_database_size_bytes", Labels: []string{"dbname=postgres", "server=localhost:5432"}, Value: "7600000"},
+		{Name: "ccp_replication_lag_size_bytes", Labels: []string{
+			"replica=10.0.3.9", "replica_hostname=", "replica_port=44380", "server=localhost:5432",
+		}, Value: "0"},
+		{Name: "ccp_archive_command_status_seconds_since_last_archive", Labels: []string{"server=localhost:5432"}, Value: "41.2"},
+		{Name: "pg_exporter_last_scrape_error", Value: "0"},
+		{Name: "odd_label", Labels: []string{`path=C:\pg`, `quote=a "b", c`, "line=x\ny"}, Value: "NaN"},
+	})
+
+	for _, text := range []string{
+		"no_value\n",
+		"no_value{server=\"x\"}\n",
+		"bad_value 1x\n",
+		"bad_labels{server=x} 1\n",
+		"unterminated{server=\"x} 1\n",
+	} {
+		_, err := parseMetrics(text)
+		assert.ErrorContains(t, err, "invalid", "%q", text)
+	}
+}
+
+func TestFilterAndPrintMetrics(t *testing.T) {
+	samples, err := parseMetrics(exporterMetrics)
+	assert.NilError(t, err)
+
+	var buf bytes.Buffer
+	assert.NilError(t, printMetrics(&buf, []podMetrics{
+		{Pod: "hippo-instance1-8kds-0", Samples: filterMetrics(samples, "")},
+		{Pod: "hippo-instance1-z4rt-0", Samples: filterMetrics(samples, "ccp_database_size")},
+	}))
+	assert.Equal(t, buf.String(), ``+
+		"POD                      METRIC                                                  LABELS                                                                        VALUE\n"+
+		"hippo-instance1-8kds-0   ccp_archive_command_status_seconds_since_last_archive   server=localhost:5432                                                         41.2\n"+
+		"hippo-instance1-8kds-0   ccp_connection_stats_active                             server=localhost:5432                                                         3\n"+
+		"hippo-instance1-8kds-0   ccp_connection_stats_max_connections                    server=localhost:5432                                                         100\n"+
+		"hippo-instance1-8kds-0   ccp_replication_lag_size_bytes                          replica=10.0.3.9,replica_hostname=,replica_port=44380,server=localhost:5432   0\n"+
+		"hippo-instance1-z4rt-0   ccp_database_size_bytes                                 dbname=hippo,server=localhost:5432                                            84017823\n"+
+		"hippo-instance1-z4rt-0   ccp_database_size_bytes                                 dbname=postgres,server=localhost:5432                                         7600000\n")
+}
+
+func TestMonitoringIntents(t *testing.T) {
+	intent := func(text string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{}}
+		assert.NilError(t, yaml.Unmarshal([]byte(text), &u.Object))
+		return u
+	}
+
+	enabled := intent(`{}`)
+	assert.NilError(t, pgMonitoring{}.enableIntent(enabled))
+	assert.Assert(t, cmp.MarshalMatches(enabled.Object, `
+spec:
+  monitoring:
+    pgmonitor:
+      exporter: {}
+	`))
+
+	// Settings of the exporter are kept.
+	enabled = intent(`{spec: {monitoring: {pgmonitor: {exporter: {image: example.com/exporter}}}}}`)
+	assert.NilError(t, pgMonitoring{}.enableIntent(enabled))
+	image, _, _ := unstructured.NestedString(enabled.Object, "spec", "monitoring", "pgmonitor", "exporter", "image")
+	assert.Equal(t, image, "example.com/exporter")
+
+	assert.Assert(t, !pgMonitoring{}.disableIntent(intent(`{spec: {port: 5432}}`)))
+
+	disabled := intent(`{spec: {monitoring: {pgmonitor: {exporter: {}}}}}`)
+	assert.Assert(t, pgMonitoring{}.disableIntent(disabled))
+	assert.DeepEqual(t, disabled.Object, map[string]any{})
+}
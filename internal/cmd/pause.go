@@ -0,0 +1,138 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+)
+
+// newPauseCommand returns the pause command of the PGO plugin. It puts Patroni
+// in maintenance mode so that it stops managing Postgres.
+// - https://patroni.readthedocs.io/en/latest/pause.html
+func newPauseCommand(config *internal.Config) *cobra.Command {
+	cmdPause := &cobra.Command{
+		Use:   "pause CLUSTER_NAME",
+		Short: "Pause automatic failover of a PostgresCluster",
+		Long: `Pause puts Patroni in maintenance mode, allowing you to work on the storage
+or network of a PostgresCluster without Patroni reacting to it. Postgres keeps
+running, but there is no automatic failover while the cluster is paused.
+Run "pgo resume" to turn automatic failover back on.
+
+### RBAC Requirements
+    Resources  Verbs
+    ---------  -----
+    pods       [list]
+    pods/exec  [create]
+
+### Usage`,
+	}
+	cmdPause.Example = internal.FormatExample(`# Pause the 'hippo' postgrescluster
+pgo pause hippo
+
+### Example output
+WARNING: While a postgrescluster is paused, Patroni does not start, stop, or
+fail over Postgres. If the primary fails, the cluster stays unavailable until
+you resume it or repair the primary yourself.
+Are you sure you want to continue? (yes/no): yes
+'pause' request sent, waiting until it is recognized by all nodes
+Success: cluster management is paused`)
+
+	// Limit the number of args, that is, only one cluster name
+	cmdPause.Args = cobra.ExactArgs(1)
+
+	cmdPause.RunE = func(cmd *cobra.Command, args []string) error {
+		if !confirm(config.In, config.Out, "WARNING: While a postgrescluster is paused, "+
+			"Patroni does not start, stop, or\nfail over Postgres. If the primary fails, "+
+			"the cluster stays unavailable until\nyou resume it or repair the primary yourself."+
+			"\nAre you sure you want to continue? (yes/no): ") {
+			return nil
+		}
+
+		return patroniMaintenance(config, args[0], "pause")
+	}
+
+	return cmdPause
+}
+
+// newResumeCommand returns the resume command of the PGO plugin. It takes
+// Patroni out of maintenance mode.
+func newResumeCommand(config *internal.Config) *cobra.Command {
+	cmdResume := &cobra.Command{
+		Use:   "resume CLUSTER_NAME",
+		Short: "Resume automatic failover of a PostgresCluster",
+		Long: `Resume takes Patroni out of maintenance mode, turning automatic failover of a
+PostgresCluster back on after "pgo pause".
+
+### RBAC Requirements
+    Resources  Verbs
+    ---------  -----
+    pods       [list]
+    pods/exec  [create]
+
+### Usage`,
+	}
+	cmdResume.Example = internal.FormatExample(`# Resume the 'hippo' postgrescluster
+pgo resume hippo
+
+### Example output
+'resume' request sent, waiting until it is recognized by all nodes
+Success: cluster management is resumed`)
+
+	// Limit the number of args, that is, only one cluster name
+	cmdResume.Args = cobra.ExactArgs(1)
+
+	cmdResume.RunE = func(cmd *cobra.Command, args []string) error {
+		return patroniMaintenance(config, args[0], "resume")
+	}
+
+	return cmdResume
+}
+
+// patroniMaintenance runs the 'patronictl pause' or 'patronictl resume'
+// command on the primary Pod of clusterName and prints its output.
+func patroniMaintenance(config *internal.Config, clusterName, command string) error {
+	exec, err := getPrimaryExec(config, []string{clusterName})
+	if err != nil {
+		return err
+	}
+
+	stdout, stderr, err := Executor(exec).patronictl(command+" --wait", "")
+	_, _ = fmt.Fprint(config.Out, stdout)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return nil
+}
+
+// patroniPaused returns whether or not the output of 'patronictl show-config'
+// has Patroni in maintenance mode.
+func patroniPaused(showConfig string) bool {
+	var config struct {
+		Pause bool `json:"pause"`
+	}
+	_ = yaml.Unmarshal([]byte(showConfig), &config)
+	return config.Pause
+}
+
+// getPaused execs into the primary Pod and returns whether or not Patroni is
+// in maintenance mode.
+func getPaused(config *internal.Config, args []string) (bool, error) {
+	exec, err := getPrimaryExec(config, args)
+	if err != nil {
+		return false, err
+	}
+
+	stdout, stderr, err := Executor(exec).patronictl("show-config", "")
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return patroniPaused(stdout), nil
+}
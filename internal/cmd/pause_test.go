@@ -0,0 +1,32 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPatroniPaused(t *testing.T) {
+	assert.Assert(t, patroniPaused(`
+loop_wait: 10
+pause: true
+postgresql:
+  parameters:
+    archive_mode: 'on'
+`))
+
+	assert.Assert(t, !patroniPaused(`
+loop_wait: 10
+postgresql:
+  parameters:
+    archive_mode: 'on'
+`))
+
+	assert.Assert(t, !patroniPaused(`pause: false`))
+	assert.Assert(t, !patroniPaused(``))
+	assert.Assert(t, !patroniPaused(`Error: not a config`))
+}
@@ -0,0 +1,718 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// pgAdminPort is the port on which pgAdmin listens in its Pod.
+const pgAdminPort = 5050
+
+// newCreatePgadminCommand returns the create pgadmin subcommand.
+// create pgadmin will take a name as an argument and create a PGAdmin
+// using a kube client
+func newCreatePgadminCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pgadmin PGADMIN_NAME",
+		Aliases: []string{"pgadmins"},
+		Short:   "Create PGAdmin with a given name",
+		Long: `Create a PGAdmin with a given name.
+
+PostgresClusters are added to pgAdmin in server groups. Each server group
+selects PostgresClusters in the same namespace by their labels. An empty
+selector selects every PostgresCluster in the namespace.
+
+### RBAC Requirements
+    Resources                                   Verbs
+    ---------                                   -----
+    pgadmins.postgres-operator.crunchydata.com  [create]
+    secrets                                     [create]
+    services                                    [create]
+
+    Note: Secrets are created only with --admin-user. Services are created only
+    with a --service-type other than ClusterIP.
+
+### Usage`,
+	}
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	create := pgAdminCreate{Config: config}
+
+	cmd.Flags().StringVar(&create.StorageSize, "storage-size", "1Gi",
+		"Size of the volume that stores pgAdmin data")
+	cmd.Flags().StringArrayVar(&create.ServerGroups, "server-group", nil,
+		`Server group of PostgresClusters as NAME:SELECTOR; can be used multiple times`)
+	cmd.Flags().StringVar(&create.ServiceName, "service-name", "",
+		"Name of a Service for pgAdmin; defaults to PGADMIN_NAME-pgadmin when --service-type is not ClusterIP")
+	cmd.Flags().StringVar(&create.ServiceType, "service-type", string(corev1.ServiceTypeClusterIP),
+		"Type of the pgAdmin Service. types supported: ClusterIP,NodePort,LoadBalancer")
+	cmd.Flags().StringVar(&create.AdminUser, "admin-user", "",
+		"Email address of an initial pgAdmin Administrator; a password is generated")
+
+	cmd.Example = internal.FormatExample(`# Create a pgadmin that shows every postgrescluster in the namespace
+pgo create pgadmin rhino --server-group 'all:'
+
+# Create a pgadmin that shows postgresclusters labeled 'owner=logistics'
+pgo create pgadmin rhino --server-group 'logistics:owner=logistics'
+
+# Create a pgadmin with an administrator and a LoadBalancer Service
+pgo create pgadmin rhino --admin-user admin@example.com --service-type LoadBalancer
+
+### Example output
+pgadmins/rhino created`)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		create.Name = args[0]
+
+		msg, err := create.Run(context.Background())
+		if msg != "" {
+			cmd.Print(msg)
+		}
+		return err
+	}
+
+	return cmd
+}
+
+// newDeletePgadminCommand returns the delete pgadmin subcommand.
+// delete pgadmin will take a name as an argument
+func newDeletePgadminCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pgadmin PGADMIN_NAME",
+		Short: "Delete a PGAdmin",
+		Long: `Delete a PGAdmin with a given name.
+
+### RBAC Requirements
+    Resources                                   Verbs
+    ---------                                   -----
+    pgadmins.postgres-operator.crunchydata.com  [delete]
+
+### Usage`,
+	}
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.Example = internal.FormatExample(`# Delete a pgadmin
+pgo delete pgadmin rhino
+
+### Example output
+WARNING: Deleting a pgadmin is destructive and its users and settings will be lost.
+Are you sure you want to continue? (yes/no): yes
+pgadmins/rhino deleted`)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		pgadminName := args[0]
+
+		if !confirm(config.In, config.Out, "WARNING: Deleting a pgadmin is destructive and its users "+
+			"and settings will be lost. \nAre you sure you want "+
+			"to continue? (yes/no): ") {
+			return nil
+		}
+
+		namespace, err := config.Namespace()
+		if err != nil {
+			return err
+		}
+
+		mapping, client, err := v1beta1.NewPgadminClient(config)
+		if err != nil {
+			return err
+		}
+
+		err = client.
+			Namespace(namespace).
+			Delete(ctx, pgadminName, metav1.DeleteOptions{})
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("%s/%s deleted\n", mapping.Resource.Resource, pgadminName)
+
+		return nil
+	}
+
+	return cmd
+}
+
+type pgAdminCreate struct {
+	*internal.Config
+
+	Name         string
+	AdminUser    string
+	ServerGroups []string
+	ServiceName  string
+	ServiceType  string
+	StorageSize  string
+}
+
+// adminSecretName returns the name of the Secret that holds the password of
+// the initial administrator.
+func (create pgAdminCreate) adminSecretName() string {
+	return create.Name + "-admin"
+}
+
+// serviceName returns the name of the Service created by the CLI.
+func (create pgAdminCreate) serviceName() string {
+	if create.ServiceName != "" {
+		return create.ServiceName
+	}
+	return create.Name + "-pgadmin"
+}
+
+// generatePgadmin returns a PGAdmin in the unstructured format.
+func (create pgAdminCreate) generatePgadmin() (*unstructured.Unstructured, error) {
+	storage, err := resource.ParseQuantity(create.StorageSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage size %q: %w", create.StorageSize, err)
+	}
+
+	pgadmin := new(unstructured.Unstructured)
+	pgadmin.SetGroupVersionKind(v1beta1.GroupVersion.WithKind("PGAdmin"))
+	pgadmin.SetName(create.Name)
+
+	if err := unstructured.SetNestedField(pgadmin.Object, map[string]any{
+		"accessModes": []any{string(corev1.ReadWriteOnce)},
+		"resources": map[string]any{
+			"requests": map[string]any{"storage": storage.String()},
+		},
+	}, "spec", "dataVolumeClaimSpec"); err != nil {
+		return nil, err
+	}
+
+	groups := make([]any, 0, len(create.ServerGroups))
+	for _, flag := range create.ServerGroups {
+		group, err := parseServerGroup(flag)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	if len(groups) > 0 {
+		if err := unstructured.SetNestedSlice(pgadmin.Object, groups, "spec", "serverGroups"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch corev1.ServiceType(create.ServiceType) {
+	case corev1.ServiceTypeClusterIP:
+		// PGO creates and manages a ClusterIP Service when it has a name.
+		if create.ServiceName != "" {
+			if err := unstructured.SetNestedField(pgadmin.Object,
+				create.ServiceName, "spec", "serviceName"); err != nil {
+				return nil, err
+			}
+		}
+	case corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer:
+		// The CLI creates these after the PGAdmin.
+	default:
+		return nil, fmt.Errorf(`invalid service type %q: must be one of "ClusterIP", "NodePort", "LoadBalancer"`,
+			create.ServiceType)
+	}
+
+	if create.AdminUser != "" {
+		if err := unstructured.SetNestedSlice(pgadmin.Object, []any{
+			map[string]any{
+				"username": create.AdminUser,
+				"role":     "Administrator",
+				"passwordRef": map[string]any{
+					"name": create.adminSecretName(),
+					"key":  "password",
+				},
+			},
+		}, "spec", "users"); err != nil {
+			return nil, err
+		}
+	}
+
+	return pgadmin, nil
+}
+
+// parseServerGroup converts a NAME:SELECTOR flag value into a PGAdmin server
+// group. A blank selector matches every PostgresCluster.
+func parseServerGroup(flag string) (map[string]any, error) {
+	name, selector, found := strings.Cut(flag, ":")
+	if !found || name == "" {
+		return nil, fmt.Errorf("invalid server group %q: expected NAME:SELECTOR", flag)
+	}
+
+	parsed, err := metav1.ParseToLabelSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server group %q: %w", flag, err)
+	}
+
+	converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"name":                    name,
+		"postgresClusterSelector": converted,
+	}, nil
+}
+
+// Run creates the PGAdmin and any Secret and Service it needs. The Secret and
+// Service are owned by the PGAdmin so they are deleted along with it.
+func (create pgAdminCreate) Run(ctx context.Context) (string, error) {
+	pgadmin, err := create.generatePgadmin()
+	if err != nil {
+		return "", err
+	}
+
+	namespace, err := create.Namespace()
+	if err != nil {
+		return "", err
+	}
+
+	mapping, client, err := v1beta1.NewPgadminClient(create)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := client.
+		Namespace(namespace).
+		Create(ctx, pgadmin, create.Patch.CreateOptions(metav1.CreateOptions{}))
+	if err != nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("%s/%s created\n", mapping.Resource.Resource, u.GetName())
+
+	owner := metav1.OwnerReference{
+		APIVersion: u.GetAPIVersion(),
+		Kind:       u.GetKind(),
+		Name:       u.GetName(),
+		UID:        u.GetUID(),
+	}
+
+	var core corev1client.CoreV1Interface
+	if create.AdminUser != "" || create.ServiceType != string(corev1.ServiceTypeClusterIP) {
+		rest, err := create.ToRESTConfig()
+		if err != nil {
+			return msg, err
+		}
+		if core, err = corev1client.NewForConfig(rest); err != nil {
+			return msg, err
+		}
+	}
+
+	if create.AdminUser != "" {
+		password, err := generatePassword(24)
+		if err != nil {
+			return msg, err
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            create.adminSecretName(),
+				Namespace:       namespace,
+				Labels:          map[string]string{util.LabelPgadmin: create.Name},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			StringData: map[string]string{"password": password},
+		}
+
+		if _, err := core.Secrets(namespace).Create(ctx, secret,
+			create.Patch.CreateOptions(metav1.CreateOptions{})); err != nil {
+			return msg, err
+		}
+		msg += fmt.Sprintf("secrets/%s created\n", secret.Name)
+	}
+
+	if create.ServiceType != string(corev1.ServiceTypeClusterIP) {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            create.serviceName(),
+				Namespace:       namespace,
+				Labels:          map[string]string{util.LabelPgadmin: create.Name},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceType(create.ServiceType),
+				Selector: map[string]string{util.LabelPgadmin: create.Name},
+				Ports: []corev1.ServicePort{{
+					Name:       "pgadmin",
+					Port:       pgAdminPort,
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(pgAdminPort),
+				}},
+			},
+		}
+
+		if _, err := core.Services(namespace).Create(ctx, service,
+			create.Patch.CreateOptions(metav1.CreateOptions{})); err != nil {
+			return msg, err
+		}
+		msg += fmt.Sprintf("services/%s created\n", service.Name)
+	}
+
+	return msg, nil
+}
+
+// generatePassword returns a random alphanumeric string of length n.
+func generatePassword(n int) (string, error) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(alphabet[index.Int64()])
+	}
+	return b.String(), nil
+}
+
+// newShowPgadminCommand returns the pgadmin subcommand of the show command. It
+// displays the server groups of a PGAdmin, the PostgresClusters they select,
+// and optionally how to log in.
+func newShowPgadminCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pgadmin PGADMIN_NAME",
+		Short: "Show details for a PGAdmin",
+		Long: `Show the server groups of a PGAdmin and the PostgresClusters each one
+currently selects. Use the "--show-connection-info" flag to get the
+endpoints and login details, including passwords.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pgadmins.postgres-operator.crunchydata.com          [get]
+    postgresclusters.postgres-operator.crunchydata.com  [list]
+    secrets                                             [get list]
+    services                                            [get list]
+
+    Note: Secrets are read only with --show-connection-info.
+
+### Usage`,
+	}
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	var showSensitive bool
+	cmd.Flags().BoolVar(&showSensitive, "show-connection-info", false,
+		"show endpoints and login details, including passwords")
+
+	var outputEnum = util.TextOutput
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"output format. types supported: text,json")
+
+	cmd.Example = internal.FormatExample(`# Show the server groups of the 'rhino' pgadmin
+pgo show pgadmin rhino
+
+# Show the login details of the 'rhino' pgadmin as JSON
+pgo show pgadmin rhino --show-connection-info --output json
+
+### Example output
+PGADMIN: rhino
+
+SERVER GROUP  SELECTOR         POSTGRESCLUSTERS
+all           <all>            hippo,zebra
+supply        owner=logistics  <none>
+
+ENDPOINTS
+rhino-pgadmin.postgres-operator.svc:5050  (ClusterIP)
+
+USERNAME           ROLE           PASSWORD
+admin@example.com  Administrator  <password>`)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		if showSensitive {
+			// Write the prompt to stderr so it does not mix with JSON output.
+			if !confirm(config.In, config.ErrOut,
+				"WARNING: This command will show sensitive password information."+
+					"\nAre you sure you want to continue? (yes/no): ") {
+				return nil
+			}
+		}
+
+		namespace, err := config.Namespace()
+		if err != nil {
+			return err
+		}
+
+		_, pgadminClient, err := v1beta1.NewPgadminClient(config)
+		if err != nil {
+			return err
+		}
+		_, clusterClient, err := v1beta1.NewPostgresClusterClient(config)
+		if err != nil {
+			return err
+		}
+		rest, err := config.ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		core, err := corev1client.NewForConfig(rest)
+		if err != nil {
+			return err
+		}
+
+		pgadmin, err := pgadminClient.Namespace(namespace).Get(ctx, args[0], metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		details, err := describePgadmin(ctx, pgadmin,
+			clusterClient.Namespace(namespace), core, showSensitive)
+		if err != nil {
+			return err
+		}
+
+		if outputEnum == util.JSONOutput {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(details)
+		}
+
+		return details.print(cmd.OutOrStdout(), showSensitive)
+	}
+
+	return cmd
+}
+
+// pgAdminDetails describes a PGAdmin for 'pgo show pgadmin'.
+type pgAdminDetails struct {
+	Name         string                `json:"name"`
+	Namespace    string                `json:"namespace"`
+	ServerGroups []pgAdminServerGroup  `json:"serverGroups"`
+	Endpoints    []pgAdminEndpoint     `json:"endpoints,omitempty"`
+	Users        []pgAdminLoginDetails `json:"users,omitempty"`
+}
+
+type pgAdminServerGroup struct {
+	Name             string   `json:"name"`
+	Selector         string   `json:"selector"`
+	PostgresClusters []string `json:"postgresClusters"`
+}
+
+type pgAdminEndpoint struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+}
+
+type pgAdminLoginDetails struct {
+	Username string `json:"username"`
+	Role     string `json:"role,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// describePgadmin gathers the details of pgadmin. The PostgresClusters that
+// each server group selects are listed using clusters. Endpoints and login
+// details are gathered only when showSensitive is true.
+func describePgadmin(ctx context.Context,
+	pgadmin *unstructured.Unstructured,
+	clusters dynamic.ResourceInterface,
+	core corev1client.CoreV1Interface,
+	showSensitive bool,
+) (pgAdminDetails, error) {
+	details := pgAdminDetails{
+		Name:         pgadmin.GetName(),
+		Namespace:    pgadmin.GetNamespace(),
+		ServerGroups: []pgAdminServerGroup{},
+	}
+
+	groups, _, err := unstructured.NestedSlice(pgadmin.Object, "spec", "serverGroups")
+	if err != nil {
+		return details, err
+	}
+
+	for i := range groups {
+		group, _ := groups[i].(map[string]any)
+		name, _, _ := unstructured.NestedString(group, "name")
+		raw, _, _ := unstructured.NestedMap(group, "postgresClusterSelector")
+
+		var parsed metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &parsed); err != nil {
+			return details, err
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&parsed)
+		if err != nil {
+			return details, err
+		}
+
+		list, err := clusters.List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return details, err
+		}
+
+		names := make([]string, 0, len(list.Items))
+		for _, item := range list.Items {
+			names = append(names, item.GetName())
+		}
+		sort.Strings(names)
+
+		details.ServerGroups = append(details.ServerGroups, pgAdminServerGroup{
+			Name: name, Selector: selector.String(), PostgresClusters: names,
+		})
+	}
+
+	if !showSensitive {
+		return details, nil
+	}
+
+	// PGO creates a ClusterIP Service when the PGAdmin has a service name.
+	// The CLI creates Services of other types with the PGAdmin label.
+	if name, _, _ := unstructured.NestedString(pgadmin.Object, "spec", "serviceName"); name != "" {
+		details.Endpoints = append(details.Endpoints, pgAdminEndpoint{
+			Address: fmt.Sprintf("%s.%s.svc:%d", name, details.Namespace, pgAdminPort),
+			Type:    string(corev1.ServiceTypeClusterIP),
+		})
+	}
+
+	services, err := core.Services(details.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.LabelPgadmin + "=" + details.Name,
+	})
+	if err != nil {
+		return details, err
+	}
+	for _, service := range services.Items {
+		details.Endpoints = append(details.Endpoints, serviceEndpoints(service)...)
+	}
+
+	// Users defined in the spec refer to their password in a Secret.
+	users, _, _ := unstructured.NestedSlice(pgadmin.Object, "spec", "users")
+	for i := range users {
+		user, _ := users[i].(map[string]any)
+
+		var login pgAdminLoginDetails
+		login.Username, _, _ = unstructured.NestedString(user, "username")
+		login.Role, _, _ = unstructured.NestedString(user, "role")
+
+		secretName, _, _ := unstructured.NestedString(user, "passwordRef", "name")
+		secretKey, _, _ := unstructured.NestedString(user, "passwordRef", "key")
+		if secretName != "" {
+			secret, err := core.Secrets(details.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+			if err != nil {
+				return details, err
+			}
+			login.Password = string(secret.Data[secretKey])
+		}
+
+		details.Users = append(details.Users, login)
+	}
+
+	// PGO generates an administrator and stores its login in a Secret with
+	// the PGAdmin label.
+	secrets, err := core.Secrets(details.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.LabelPgadmin + "=" + details.Name,
+	})
+	if err != nil {
+		return details, err
+	}
+	for _, secret := range secrets.Items {
+		if username, ok := secret.Data["username"]; ok {
+			details.Users = append(details.Users, pgAdminLoginDetails{
+				Username: string(username),
+				Role:     "Administrator",
+				Password: string(secret.Data["password"]),
+			})
+		}
+	}
+
+	return details, nil
+}
+
+// serviceEndpoints returns the addresses at which service exposes pgAdmin.
+func serviceEndpoints(service corev1.Service) []pgAdminEndpoint {
+	var endpoints []pgAdminEndpoint
+	for _, port := range service.Spec.Ports {
+		endpoints = append(endpoints, pgAdminEndpoint{
+			Address: fmt.Sprintf("%s.%s.svc:%d", service.Name, service.Namespace, port.Port),
+			Type:    string(corev1.ServiceTypeClusterIP),
+		})
+
+		if service.Spec.Type == corev1.ServiceTypeNodePort && port.NodePort != 0 {
+			endpoints = append(endpoints, pgAdminEndpoint{
+				Address: fmt.Sprintf("<node>:%d", port.NodePort),
+				Type:    string(corev1.ServiceTypeNodePort),
+			})
+		}
+
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				host := ingress.Hostname
+				if host == "" {
+					host = ingress.IP
+				}
+				endpoints = append(endpoints, pgAdminEndpoint{
+					Address: fmt.Sprintf("%s:%d", host, port.Port),
+					Type:    string(corev1.ServiceTypeLoadBalancer),
+				})
+			}
+		}
+	}
+	return endpoints
+}
+
+// print writes details to w in a human-readable format.
+func (details pgAdminDetails) print(w io.Writer, showSensitive bool) error {
+	var buf bytes.Buffer
+
+	_, _ = fmt.Fprintf(&buf, "PGADMIN: %s\n\n", details.Name)
+
+	_, _ = fmt.Fprintln(&buf, "SERVER GROUP\tSELECTOR\tPOSTGRESCLUSTERS")
+	for _, group := range details.ServerGroups {
+		selector, clusters := group.Selector, strings.Join(group.PostgresClusters, ",")
+		if selector == "" {
+			selector = "<all>"
+		}
+		if clusters == "" {
+			clusters = "<none>"
+		}
+		_, _ = fmt.Fprintf(&buf, "%s\t%s\t%s\n", group.Name, selector, clusters)
+	}
+
+	if showSensitive {
+		_, _ = fmt.Fprintln(&buf, "\nENDPOINTS")
+		if len(details.Endpoints) == 0 {
+			_, _ = fmt.Fprintln(&buf, "<none>")
+		}
+		for _, endpoint := range details.Endpoints {
+			_, _ = fmt.Fprintf(&buf, "%s\t(%s)\n", endpoint.Address, endpoint.Type)
+		}
+
+		_, _ = fmt.Fprintln(&buf, "\nUSERNAME\tROLE\tPASSWORD")
+		for _, user := range details.Users {
+			_, _ = fmt.Fprintf(&buf, "%s\t%s\t%s\n", user.Username, user.Role, user.Password)
+		}
+	}
+
+	writer := tabwriter.NewWriter(w, 10, 2, 2, ' ', 0)
+	if _, err := writer.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,283 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestParseServerGroup(t *testing.T) {
+	for _, tt := range []struct {
+		Flag, Expected string
+	}{
+		{
+			Flag: "all:",
+			Expected: `
+name: all
+postgresClusterSelector: {}
+`,
+		},
+		{
+			Flag: "supply:owner=logistics,tier",
+			Expected: `
+name: supply
+postgresClusterSelector:
+  matchExpressions:
+  - key: tier
+    operator: Exists
+  matchLabels:
+    owner: logistics
+`,
+		},
+		{
+			Flag: "prod:env in (prod,staging)",
+			Expected: `
+name: prod
+postgresClusterSelector:
+  matchExpressions:
+  - key: env
+    operator: In
+    values:
+    - prod
+    - staging
+`,
+		},
+	} {
+		t.Run(tt.Flag, func(t *testing.T) {
+			group, err := parseServerGroup(tt.Flag)
+			assert.NilError(t, err)
+			assert.Assert(t, cmp.MarshalMatches(group, tt.Expected))
+		})
+	}
+
+	for _, flag := range []string{"", "all", ":owner=me", "bad:owner in"} {
+		t.Run("Invalid "+flag, func(t *testing.T) {
+			_, err := parseServerGroup(flag)
+			assert.ErrorContains(t, err, "invalid server group")
+		})
+	}
+}
+
+func TestGeneratePgadmin(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		pgadmin, err := pgAdminCreate{
+			Name: "rhino", StorageSize: "1Gi", ServiceType: "ClusterIP",
+		}.generatePgadmin()
+		assert.NilError(t, err)
+
+		assert.Assert(t, cmp.MarshalMatches(pgadmin, `
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PGAdmin
+metadata:
+  name: rhino
+spec:
+  dataVolumeClaimSpec:
+    accessModes:
+    - ReadWriteOnce
+    resources:
+      requests:
+        storage: 1Gi
+`))
+	})
+
+	t.Run("Everything", func(t *testing.T) {
+		pgadmin, err := pgAdminCreate{
+			Name:         "rhino",
+			AdminUser:    "admin@example.com",
+			ServerGroups: []string{"all:", "mine:owner=me"},
+			ServiceName:  "rhino-svc",
+			ServiceType:  "ClusterIP",
+			StorageSize:  "2048Mi",
+		}.generatePgadmin()
+		assert.NilError(t, err)
+
+		assert.Assert(t, cmp.MarshalMatches(pgadmin, `
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PGAdmin
+metadata:
+  name: rhino
+spec:
+  dataVolumeClaimSpec:
+    accessModes:
+    - ReadWriteOnce
+    resources:
+      requests:
+        storage: 2Gi
+  serverGroups:
+  - name: all
+    postgresClusterSelector: {}
+  - name: mine
+    postgresClusterSelector:
+      matchLabels:
+        owner: me
+  serviceName: rhino-svc
+  users:
+  - passwordRef:
+      key: password
+      name: rhino-admin
+    role: Administrator
+    username: admin@example.com
+`))
+	})
+
+	t.Run("LoadBalancer", func(t *testing.T) {
+		create := pgAdminCreate{
+			Name: "rhino", StorageSize: "1Gi", ServiceType: "LoadBalancer",
+		}
+		pgadmin, err := create.generatePgadmin()
+		assert.NilError(t, err)
+
+		// The CLI creates the Service rather than PGO.
+		_, found := pgadmin.Object["spec"].(map[string]any)["serviceName"]
+		assert.Assert(t, !found)
+		assert.Equal(t, create.serviceName(), "rhino-pgadmin")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := pgAdminCreate{Name: "rhino", StorageSize: "lots", ServiceType: "ClusterIP"}.generatePgadmin()
+		assert.ErrorContains(t, err, "invalid storage size")
+
+		_, err = pgAdminCreate{Name: "rhino", StorageSize: "1Gi", ServiceType: "ExternalName"}.generatePgadmin()
+		assert.ErrorContains(t, err, "invalid service type")
+	})
+}
+
+func TestGeneratePassword(t *testing.T) {
+	a, err := generatePassword(24)
+	assert.NilError(t, err)
+	assert.Equal(t, len(a), 24)
+
+	b, err := generatePassword(24)
+	assert.NilError(t, err)
+	assert.Assert(t, a != b)
+}
+
+func TestDescribePgadmin(t *testing.T) {
+	ctx := context.Background()
+
+	cluster := func(name string, labels map[string]string) *unstructured.Unstructured {
+		u := new(unstructured.Unstructured)
+		u.SetGroupVersionKind(v1beta1.GroupVersion.WithKind("PostgresCluster"))
+		u.SetNamespace("zoo")
+		u.SetName(name)
+		u.SetLabels(labels)
+		return u
+	}
+
+	gvr := v1beta1.GroupVersion.WithResource("postgresclusters")
+	clusters := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "PostgresClusterList"},
+		cluster("hippo", map[string]string{"owner": "logistics"}),
+		cluster("zebra", nil),
+	).Resource(gvr).Namespace("zoo")
+
+	core := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "zoo", Name: "rhino-admin",
+				Labels: map[string]string{"postgres-operator.crunchydata.com/pgadmin": "rhino"}},
+			Data: map[string][]byte{"password": []byte("from-ref")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "zoo", Name: "pgadmin-generated",
+				Labels: map[string]string{"postgres-operator.crunchydata.com/pgadmin": "rhino"}},
+			Data: map[string][]byte{"username": []byte("admin@rhino"), "password": []byte("generated")},
+		},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "zoo", Name: "rhino-pgadmin",
+				Labels: map[string]string{"postgres-operator.crunchydata.com/pgadmin": "rhino"}},
+			Spec: corev1.ServiceSpec{
+				Type:  corev1.ServiceTypeNodePort,
+				Ports: []corev1.ServicePort{{Port: 5050, NodePort: 30050}},
+			},
+		},
+	).CoreV1()
+
+	var pgadmin unstructured.Unstructured
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+metadata:
+  name: rhino
+  namespace: zoo
+spec:
+  serverGroups:
+  - name: all
+    postgresClusterSelector: {}
+  - name: supply
+    postgresClusterSelector:
+      matchLabels:
+        owner: logistics
+  serviceName: rhino-svc
+  users:
+  - username: admin@example.com
+    role: Administrator
+    passwordRef: { name: rhino-admin, key: password }
+`), &pgadmin.Object))
+
+	t.Run("ServerGroups", func(t *testing.T) {
+		details, err := describePgadmin(ctx, &pgadmin, clusters, core, false)
+		assert.NilError(t, err)
+
+		assert.Assert(t, cmp.MarshalMatches(details, `
+name: rhino
+namespace: zoo
+serverGroups:
+- name: all
+  postgresClusters:
+  - hippo
+  - zebra
+  selector: ""
+- name: supply
+  postgresClusters:
+  - hippo
+  selector: owner=logistics
+`))
+
+		var buf bytes.Buffer
+		assert.NilError(t, details.print(&buf, false))
+		assert.Equal(t, buf.String(), `PGADMIN: rhino
+
+SERVER GROUP  SELECTOR         POSTGRESCLUSTERS
+all           <all>            hippo,zebra
+supply        owner=logistics  hippo
+`)
+	})
+
+	t.Run("ConnectionInfo", func(t *testing.T) {
+		details, err := describePgadmin(ctx, &pgadmin, clusters, core, true)
+		assert.NilError(t, err)
+
+		assert.Assert(t, cmp.MarshalMatches(details.Endpoints, `
+- address: rhino-svc.zoo.svc:5050
+  type: ClusterIP
+- address: rhino-pgadmin.zoo.svc:5050
+  type: ClusterIP
+- address: <node>:30050
+  type: NodePort
+`))
+		assert.Assert(t, cmp.MarshalMatches(details.Users, `
+- password: from-ref
+  role: Administrator
+  username: admin@example.com
+- password: generated
+  role: Administrator
+  username: admin@rhino
+`))
+	})
+}
@@ -0,0 +1,620 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newPGBouncerCommand returns the pgbouncer command of the PGO plugin.
+// Subcommands of pgbouncer manage the PgBouncer connection pooler in
+// "spec.proxy.pgBouncer".
+func newPGBouncerCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pgbouncer",
+		Short: "Manage the PgBouncer connection pooler of a PostgresCluster",
+		Long: `Manage the PgBouncer connection pooler of a PostgresCluster.
+
+Changes are sent using server-side apply, so only settings made by this command
+can be changed by it. Overwriting settings owned by another client may require
+the --force-conflicts flag.`,
+	}
+
+	cmd.AddCommand(
+		newPGBouncerConfigCommand(config),
+		newPGBouncerDisableCommand(config),
+		newPGBouncerEnableCommand(config),
+		newPGBouncerShowCommand(config),
+	)
+
+	return cmd
+}
+
+func newPGBouncerEnableCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable CLUSTER_NAME",
+		Short: "Add PgBouncer to a PostgresCluster",
+		Long: `Add PgBouncer to a PostgresCluster by setting "spec.proxy.pgBouncer". PGO
+creates the PgBouncer Deployment and Service and adds "pgbouncer-uri" to the
+user Secrets.
+
+Unless "stats_users" is already set, it is set to the PgBouncer user of PGO so
+that "pgo pgbouncer show" can read the admin console.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Add two PgBouncer Pods to the 'hippo' postgrescluster
+pgo pgbouncer enable hippo --replicas 2
+
+### Example output
+postgresclusters/hippo PgBouncer enabled`)
+
+	bouncer := pgBouncer{Config: config}
+
+	cmd.Flags().BoolVar(&bouncer.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the PgBouncer settings")
+	cmd.Flags().IntVar(&bouncer.Replicas, "replicas", 1, "the number of PgBouncer Pods")
+	cmd.Flags().IntVar(&bouncer.Port, "port", 0, "the port of PgBouncer; default is 5432")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		bouncer.ClusterName = args[0]
+		if bouncer.Replicas < 0 {
+			return fmt.Errorf("--replicas must not be negative")
+		}
+		if bouncer.Port != 0 && (bouncer.Port < 1024 || bouncer.Port > 65535) {
+			return fmt.Errorf("--port must be between 1024 and 65535")
+		}
+		return bouncer.Enable(context.Background())
+	}
+
+	return cmd
+}
+
+func newPGBouncerDisableCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable CLUSTER_NAME",
+		Short: "Remove PgBouncer from a PostgresCluster",
+		Long: `Remove PgBouncer from a PostgresCluster by removing "spec.proxy". PGO deletes
+the PgBouncer Deployment and Service, and clients connected through PgBouncer
+are disconnected. Only PgBouncer added by "pgo pgbouncer enable" can be removed.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Remove PgBouncer from the 'hippo' postgrescluster
+pgo pgbouncer disable hippo
+
+### Example output
+WARNING: Clients connected through PgBouncer of postgresclusters/hippo will be disconnected.
+Are you sure you want to continue? (yes/no): yes
+postgresclusters/hippo PgBouncer disabled`)
+
+	bouncer := pgBouncer{Config: config}
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		bouncer.ClusterName = args[0]
+		return bouncer.Disable(context.Background())
+	}
+
+	return cmd
+}
+
+func newPGBouncerConfigCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config CLUSTER_NAME",
+		Short: "Change the pooling settings of PgBouncer",
+		Long: `Change the pooling settings of PgBouncer in "spec.proxy.pgBouncer.config.global".
+PgBouncer reloads its configuration without disconnecting clients.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Pool server connections per transaction, 20 per user and database
+pgo pgbouncer config hippo --pool-mode transaction --default-pool-size 20
+
+### Example output
+postgresclusters/hippo PgBouncer configured`)
+
+	bouncer := pgBouncer{Config: config}
+
+	cmd.Flags().BoolVar(&bouncer.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the PgBouncer settings")
+	cmd.Flags().StringVar(&bouncer.PoolMode, "pool-mode", "", "when a server connection is returned to the pool: session, transaction, or statement")
+	cmd.Flags().IntVar(&bouncer.DefaultPoolSize, "default-pool-size", 0, "the number of server connections for each user and database")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		bouncer.ClusterName = args[0]
+		if err := bouncer.validateConfig(); err != nil {
+			return err
+		}
+		return bouncer.Configure(context.Background())
+	}
+
+	return cmd
+}
+
+func newPGBouncerShowCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show CLUSTER_NAME",
+		Short: "Show the pools, statistics, and clients of PgBouncer",
+		Long: `Show the pools, statistics, and clients of each PgBouncer Pod. They are read
+from the PgBouncer admin console with "SHOW POOLS", "SHOW STATS", and
+"SHOW CLIENTS" as the PgBouncer user of PGO, which must be in "stats_users".
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+    secrets                                             [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Show PgBouncer of the 'hippo' postgrescluster
+pgo pgbouncer show hippo
+
+### Example output
+POOLS
+POD                            DATABASE   USER    CL_ACTIVE   CL_WAITING   SV_ACTIVE   SV_IDLE   SV_USED   MAXWAIT   POOL_MODE
+hippo-pgbouncer-7d9f8b-x2kqp   hippo      hippo   4           0            2           1         0         0         transaction
+
+STATS
+POD                            DATABASE   TOTAL_XACT_COUNT   TOTAL_QUERY_COUNT   TOTAL_RECEIVED   TOTAL_SENT   AVG_XACT_TIME   AVG_QUERY_TIME   AVG_WAIT_TIME
+hippo-pgbouncer-7d9f8b-x2kqp   hippo      10417              31256               2871043          9310264      512             168              3
+
+CLIENTS
+POD                            USER    DATABASE   STATE    ADDR        PORT    CONNECT_TIME              APPLICATION_NAME
+hippo-pgbouncer-7d9f8b-x2kqp   hippo   hippo      active   10.0.3.17   51234   2025-03-09 14:02:11 UTC   api`)
+
+	bouncer := pgBouncer{Config: config}
+
+	var outputEnum = util.TextOutput
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"output format. types supported: text,json")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		bouncer.ClusterName = args[0]
+		bouncer.JSON = outputEnum == util.JSONOutput
+		return bouncer.Show(context.Background())
+	}
+
+	return cmd
+}
+
+type pgBouncer struct {
+	*internal.Config
+
+	ClusterName     string
+	DefaultPoolSize int
+	ForceConflicts  bool
+	JSON            bool
+	PoolMode        string
+	Port            int
+	Replicas        int
+}
+
+// pgBouncerUser is the user that PGO creates for PgBouncer.
+const pgBouncerUser = "_crunchypgbouncer"
+
+// pgBouncerView is a SHOW command of the PgBouncer admin console and the
+// columns of its result that are printed as text.
+// - https://www.pgbouncer.org/usage.html#admin-console
+type pgBouncerView struct {
+	Name    string
+	Columns []string
+}
+
+var pgBouncerViews = []pgBouncerView{
+	{Name: "POOLS", Columns: []string{
+		"database", "user", "cl_active", "cl_waiting", "sv_active", "sv_idle", "sv_used", "maxwait", "pool_mode",
+	}},
+	{Name: "STATS", Columns: []string{
+		"database", "total_xact_count", "total_query_count", "total_received", "total_sent",
+		"avg_xact_time", "avg_query_time", "avg_wait_time",
+	}},
+	{Name: "CLIENTS", Columns: []string{
+		"user", "database", "state", "addr", "port", "connect_time", "application_name",
+	}},
+}
+
+func (bouncer pgBouncer) validateConfig() error {
+	if bouncer.PoolMode == "" && bouncer.DefaultPoolSize == 0 {
+		return fmt.Errorf("at least one of --pool-mode or --default-pool-size is required")
+	}
+	if bouncer.PoolMode != "" && !slices.Contains([]string{"session", "transaction", "statement"}, bouncer.PoolMode) {
+		return fmt.Errorf(`--pool-mode must be one of "session", "transaction", "statement"`)
+	}
+	if bouncer.DefaultPoolSize < 0 {
+		return fmt.Errorf("--default-pool-size must be positive")
+	}
+	return nil
+}
+
+// enableIntent sets the replicas and port of PgBouncer in intent. It also sets
+// "stats_users" when cluster does not have it.
+func (bouncer pgBouncer) enableIntent(intent, cluster *unstructured.Unstructured) error {
+	fields := []string{"spec", "proxy", "pgBouncer"}
+	if err := unstructured.SetNestedField(intent.Object, int64(bouncer.Replicas), append(fields, "replicas")...); err != nil {
+		return err
+	}
+	if bouncer.Port != 0 {
+		if err := unstructured.SetNestedField(intent.Object, int64(bouncer.Port), append(fields, "port")...); err != nil {
+			return err
+		}
+	}
+
+	global := append(fields, "config", "global")
+	if _, found, _ := unstructured.NestedString(cluster.Object, append(global, "stats_users")...); !found {
+		if err := unstructured.SetNestedField(intent.Object, pgBouncerUser, append(global, "stats_users")...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// disableIntent removes PgBouncer from intent. It returns false when this
+// client did not add PgBouncer.
+func (bouncer pgBouncer) disableIntent(intent *unstructured.Unstructured) bool {
+	if _, found, _ := unstructured.NestedMap(intent.Object, "spec", "proxy", "pgBouncer"); !found {
+		return false
+	}
+
+	unstructured.RemoveNestedField(intent.Object, "spec", "proxy")
+	if m, found, _ := unstructured.NestedMap(intent.Object, "spec"); found && len(m) == 0 {
+		unstructured.RemoveNestedField(intent.Object, "spec")
+	}
+	return true
+}
+
+// configIntent sets the pooling settings in intent. It returns an error when
+// cluster does not have PgBouncer.
+func (bouncer pgBouncer) configIntent(intent, cluster *unstructured.Unstructured) error {
+	if _, found, _ := unstructured.NestedMap(cluster.Object, "spec", "proxy", "pgBouncer"); !found {
+		return fmt.Errorf("postgrescluster %q does not have PgBouncer", bouncer.ClusterName)
+	}
+
+	global := []string{"spec", "proxy", "pgBouncer", "config", "global"}
+	if bouncer.PoolMode != "" {
+		if err := unstructured.SetNestedField(intent.Object, bouncer.PoolMode, append(global, "pool_mode")...); err != nil {
+			return err
+		}
+	}
+	if bouncer.DefaultPoolSize != 0 {
+		if err := unstructured.SetNestedField(intent.Object, strconv.Itoa(bouncer.DefaultPoolSize),
+			append(global, "default_pool_size")...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// modify applies the changes of change to the fields of the cluster that this
+// client manages. It returns the mapping of the cluster.
+func (bouncer pgBouncer) modify(ctx context.Context,
+	change func(intent, cluster *unstructured.Unstructured) error,
+) (*meta.RESTMapping, error) {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(bouncer)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := bouncer.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, bouncer.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, bouncer.Patch.FieldManager); err != nil {
+		return nil, err
+	}
+	if err := change(intent, cluster); err != nil {
+		return nil, err
+	}
+	_, err = applyCluster(ctx, bouncer.Config, client.Namespace(namespace), bouncer.ClusterName,
+		intent, bouncer.ForceConflicts)
+	return mapping, err
+}
+
+// Enable adds PgBouncer to the cluster.
+func (bouncer pgBouncer) Enable(ctx context.Context) error {
+	mapping, err := bouncer.modify(ctx, bouncer.enableIntent)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(bouncer.Out, "%s/%s PgBouncer enabled\n", mapping.Resource.Resource, bouncer.ClusterName)
+	return nil
+}
+
+// Configure changes the pooling settings of PgBouncer.
+func (bouncer pgBouncer) Configure(ctx context.Context) error {
+	mapping, err := bouncer.modify(ctx, bouncer.configIntent)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(bouncer.Out, "%s/%s PgBouncer configured\n", mapping.Resource.Resource, bouncer.ClusterName)
+	return nil
+}
+
+// Disable asks for confirmation and then removes PgBouncer from the cluster.
+func (bouncer pgBouncer) Disable(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(bouncer)
+	if err != nil {
+		return err
+	}
+	namespace, err := bouncer.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, bouncer.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, bouncer.Patch.FieldManager); err != nil {
+		return err
+	}
+
+	// Check the cluster before asking for confirmation.
+	if !bouncer.disableIntent(intent) {
+		return fmt.Errorf("PgBouncer of %s/%s was not enabled by this command",
+			mapping.Resource.Resource, bouncer.ClusterName)
+	}
+
+	if !confirm(bouncer.In, bouncer.Out, fmt.Sprintf(
+		"WARNING: Clients connected through PgBouncer of %s/%s will be disconnected."+
+			"\nAre you sure you want to continue? (yes/no): ",
+		mapping.Resource.Resource, bouncer.ClusterName)) {
+		return nil
+	}
+
+	if _, err := applyCluster(ctx, bouncer.Config, client.Namespace(namespace), bouncer.ClusterName,
+		intent, bouncer.ForceConflicts); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(bouncer.Out, "%s/%s PgBouncer disabled\n", mapping.Resource.Resource, bouncer.ClusterName)
+	return nil
+}
+
+// parseCSV returns the header and rows of CSV output from psql.
+func parseCSV(text string) ([]string, []map[string]string, error) {
+	records, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, nil, err
+	}
+
+	header := records[0]
+	rows := []map[string]string{}
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return header, rows, nil
+}
+
+// pgBouncerResult is the result of one SHOW command on every PgBouncer Pod.
+// Each row has a "pod" column.
+type pgBouncerResult struct {
+	View    pgBouncerView
+	Columns []string
+	Rows    []map[string]string
+}
+
+// add adds the CSV result of the view from pod.
+func (result *pgBouncerResult) add(pod, text string) error {
+	header, rows, err := parseCSV(text)
+	if err != nil {
+		return fmt.Errorf("unexpected output of SHOW %s: %w", result.View.Name, err)
+	}
+	if result.Columns == nil {
+		for _, column := range result.View.Columns {
+			if slices.Contains(header, column) {
+				result.Columns = append(result.Columns, column)
+			}
+		}
+		// Print every column when PgBouncer has none of the expected ones.
+		if len(result.Columns) == 0 {
+			result.Columns = header
+		}
+	}
+	for _, row := range rows {
+		row["pod"] = pod
+		result.Rows = append(result.Rows, row)
+	}
+	return nil
+}
+
+func printPGBouncerResults(w io.Writer, results []pgBouncerResult) error {
+	var buf bytes.Buffer
+	for i, result := range results {
+		if i > 0 {
+			_, _ = fmt.Fprintln(&buf)
+		}
+		_, _ = fmt.Fprintln(&buf, result.View.Name)
+
+		columns := append([]string{"pod"}, result.Columns...)
+		p := printers.GetNewTabWriter(&buf)
+		if _, err := fmt.Fprintln(p, strings.ToUpper(strings.Join(columns, "\t"))); err != nil {
+			return err
+		}
+		for _, row := range result.Rows {
+			values := make([]string, len(columns))
+			for j, column := range columns {
+				values[j] = row[column]
+			}
+			if _, err := fmt.Fprintln(p, strings.Join(values, "\t")); err != nil {
+				return err
+			}
+		}
+		if err := p.Flush(); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func printPGBouncerResultsJSON(w io.Writer, results []pgBouncerResult) error {
+	object := make(map[string][]map[string]string, len(results))
+	for _, result := range results {
+		object[strings.ToLower(result.View.Name)] = result.Rows
+		if result.Rows == nil {
+			object[strings.ToLower(result.View.Name)] = []map[string]string{}
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(object)
+}
+
+// Show runs each SHOW command on every running PgBouncer Pod and prints them.
+func (bouncer pgBouncer) Show(ctx context.Context) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(bouncer)
+	if err != nil {
+		return err
+	}
+	namespace, err := bouncer.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, bouncer.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, found, _ := unstructured.NestedMap(cluster.Object, "spec", "proxy", "pgBouncer"); !found {
+		return fmt.Errorf("postgrescluster %q does not have PgBouncer", bouncer.ClusterName)
+	}
+	port, found, _ := unstructured.NestedInt64(cluster.Object, "spec", "proxy", "pgBouncer", "port")
+	if !found {
+		port = 5432
+	}
+
+	rest, err := bouncer.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	core, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+
+	secret, err := core.Secrets(namespace).Get(ctx, bouncer.ClusterName+"-pgbouncer", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	password := string(secret.Data["pgbouncer-password"])
+
+	pods, err := core.Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.PGBouncerLabels(bouncer.ClusterName),
+	})
+	if err != nil {
+		return err
+	}
+
+	podExec, err := util.NewPodExecutor(rest)
+	if err != nil {
+		return err
+	}
+
+	results := make([]pgBouncerResult, len(pgBouncerViews))
+	for i := range pgBouncerViews {
+		results[i].View = pgBouncerViews[i]
+	}
+
+	var running int
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		running++
+
+		exec := func(stdin io.Reader, stdout, stderr io.Writer, command ...string) error {
+			return podExec(pod.Namespace, pod.Name, util.ContainerPGBouncer, stdin, stdout, stderr, command...)
+		}
+		for i := range results {
+			stdout, stderr, err := Executor(exec).pgBouncerShow(
+				strconv.FormatInt(port, 10), password, results[i].View.Name)
+			if err != nil {
+				if strings.Contains(stderr, "not allowed") {
+					_, _ = fmt.Fprintf(bouncer.Out, "SUGGESTION: Add %q to %q in %q.\n",
+						pgBouncerUser, "stats_users", "spec.proxy.pgBouncer.config.global")
+				}
+				return fmt.Errorf("%s: %w: %s", pod.Name, err, strings.TrimSpace(stderr))
+			}
+			if err := results[i].add(pod.Name, stdout); err != nil {
+				return err
+			}
+		}
+	}
+	if running == 0 {
+		return fmt.Errorf("no running PgBouncer Pods found for postgrescluster %q", bouncer.ClusterName)
+	}
+
+	if bouncer.JSON {
+		return printPGBouncerResultsJSON(bouncer.Out, results)
+	}
+	return printPGBouncerResults(bouncer.Out, results)
+}
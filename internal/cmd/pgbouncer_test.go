@@ -0,0 +1,152 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestPGBouncerValidateConfig(t *testing.T) {
+	assert.ErrorContains(t, pgBouncer{}.validateConfig(), "at least one of")
+	assert.ErrorContains(t, pgBouncer{PoolMode: "query"}.validateConfig(), "--pool-mode must be one of")
+	assert.ErrorContains(t, pgBouncer{DefaultPoolSize: -1}.validateConfig(), "--default-pool-size")
+	assert.NilError(t, pgBouncer{PoolMode: "transaction", DefaultPoolSize: 20}.validateConfig())
+}
+
+func TestPGBouncerIntents(t *testing.T) {
+	cluster := func(text string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{}}
+		assert.NilError(t, yaml.Unmarshal([]byte(text), &u.Object))
+		return u
+	}
+
+	t.Run("Enable", func(t *testing.T) {
+		intent := cluster(`{}`)
+		assert.NilError(t, pgBouncer{Replicas: 2, Port: 6432}.enableIntent(intent, cluster(`{}`)))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  proxy:
+    pgBouncer:
+      config:
+        global:
+          stats_users: _crunchypgbouncer
+      port: 6432
+      replicas: 2
+		`))
+
+		// Other stats users are left alone.
+		intent = cluster(`{}`)
+		assert.NilError(t, pgBouncer{Replicas: 1}.enableIntent(intent, cluster(`
+spec:
+  proxy:
+    pgBouncer:
+      config:
+        global:
+          stats_users: monitor
+`)))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  proxy:
+    pgBouncer:
+      replicas: 1
+		`))
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		assert.Assert(t, !pgBouncer{}.disableIntent(cluster(`{spec: {port: 5432}}`)))
+
+		intent := cluster(`{spec: {proxy: {pgBouncer: {replicas: 1}}}}`)
+		assert.Assert(t, pgBouncer{}.disableIntent(intent))
+		assert.DeepEqual(t, intent.Object, map[string]any{})
+
+		intent = cluster(`{spec: {port: 5432, proxy: {pgBouncer: {replicas: 1}}}}`)
+		assert.Assert(t, pgBouncer{}.disableIntent(intent))
+		assert.DeepEqual(t, intent.Object, map[string]any{"spec": map[string]any{"port": float64(5432)}})
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		intent := cluster(`{}`)
+		err := pgBouncer{ClusterName: "hippo", PoolMode: "transaction"}.configIntent(intent, cluster(`{}`))
+		assert.ErrorContains(t, err, `postgrescluster "hippo" does not have PgBouncer`)
+
+		err = pgBouncer{PoolMode: "transaction", DefaultPoolSize: 20}.configIntent(intent,
+			cluster(`{spec: {proxy: {pgBouncer: {}}}}`))
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  proxy:
+    pgBouncer:
+      config:
+        global:
+          default_pool_size: "20"
+          pool_mode: transaction
+		`))
+	})
+}
+
+func TestParseCSV(t *testing.T) {
+	header, rows, err := parseCSV("")
+	assert.NilError(t, err)
+	assert.Assert(t, header == nil && rows == nil)
+
+	header, rows, err = parseCSV("database,user,cl_active\npgbouncer,pgbouncer,1\nhippo,\"hi,ppo\",4\n")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, header, []string{"database", "user", "cl_active"})
+	assert.DeepEqual(t, rows, []map[string]string{
+		{"database": "pgbouncer", "user": "pgbouncer", "cl_active": "1"},
+		{"database": "hippo", "user": "hi,ppo", "cl_active": "4"},
+	})
+
+	_, _, err = parseCSV("a,b\n\"unterminated\n")
+	assert.Assert(t, err != nil)
+}
+
+func TestPrintPGBouncerResults(t *testing.T) {
+	pools := pgBouncerResult{View: pgBouncerViews[0]}
+	assert.NilError(t, pools.add("hippo-pgbouncer-a",
+		"database,user,cl_active,cl_waiting,cl_active_cancel_req,sv_active,sv_idle,sv_used,maxwait,pool_mode\n"+
+			"hippo,hippo,4,0,0,2,1,0,0,transaction\n"))
+	assert.NilError(t, pools.add("hippo-pgbouncer-b",
+		"database,user,cl_active,cl_waiting,cl_active_cancel_req,sv_active,sv_idle,sv_used,maxwait,pool_mode\n"+
+			"hippo,hippo,1,0,0,1,0,0,0,transaction\n"))
+
+	// Unexpected columns are all printed.
+	other := pgBouncerResult{View: pgBouncerView{Name: "OTHER", Columns: []string{"missing"}}}
+	assert.NilError(t, other.add("hippo-pgbouncer-a", "name,value\nx,1\n"))
+
+	var buf bytes.Buffer
+	assert.NilError(t, printPGBouncerResults(&buf, []pgBouncerResult{pools, other}))
+	assert.Equal(t, buf.String(), ``+
+		"POOLS\n"+
+		"POD                 DATABASE   USER    CL_ACTIVE   CL_WAITING   SV_ACTIVE   SV_IDLE   SV_USED   MAXWAIT   POOL_MODE\n"+
+		"hippo-pgbouncer-a   hippo      hippo   4           0            2           1         0         0         transaction\n"+
+		"hippo-pgbouncer-b   hippo      hippo   1           0            1           0         0         0         transaction\n"+
+		"\n"+
+		"OTHER\n"+
+		"POD                 NAME   VALUE\n"+
+		"hippo-pgbouncer-a   x      1\n")
+
+	buf.Reset()
+	empty := pgBouncerResult{View: pgBouncerViews[2]}
+	assert.NilError(t, printPGBouncerResultsJSON(&buf, []pgBouncerResult{other, empty}))
+	assert.Equal(t, buf.String(), `{
+  "clients": [],
+  "other": [
+    {
+      "name": "x",
+      "pod": "hippo-pgbouncer-a",
+      "value": "1"
+    }
+  ]
+}
+`)
+}
@@ -114,14 +114,35 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	root.SetOut(stdout)
 
 	root.AddCommand(newBackupCommand(config))
+	root.AddCommand(newConfigCommand(config))
 	root.AddCommand(newCreateCommand(config))
 	root.AddCommand(newDeleteCommand(config))
+	root.AddCommand(newEventsCommand(config))
+	root.AddCommand(newHBACommand(config))
+	root.AddCommand(newLogsCommand(config))
+	root.AddCommand(newMonitoringCommand(config))
+	root.AddCommand(newPauseCommand(config))
+	root.AddCommand(newPGBouncerCommand(config))
+	root.AddCommand(newRepoCommand(config))
+	root.AddCommand(newRestartCommand(config))
 	root.AddCommand(newRestoreCommand(config))
+	root.AddCommand(newResumeCommand(config))
 	root.AddCommand(newShowCommand(config))
+	root.AddCommand(newStandbyCommand(config))
 	root.AddCommand(newSupportCommand(config))
+	root.AddCommand(newTLSCommand(config))
 	root.AddCommand(newVersionCommand(config))
 	root.AddCommand(newStopCommand(config))
 	root.AddCommand(newStartCommand(config))
+	root.AddCommand(newUpgradeCommand(config))
+	root.AddCommand(newUserCommand(config))
+
+	// Warn about unsupported operators and refuse commands that need a newer
+	// one before any subcommand runs.
+	root.PersistentPreRunE = checkOperatorCompatibility(config)
+
+	// Complete the names of clusters, repositories, users, and such from the API.
+	registerCompletions(root, config)
 
 	return root
 }
@@ -0,0 +1,436 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// profileConfigMapKey is the ConfigMap data key that holds a profile.
+const profileConfigMapKey = "profile.yaml"
+
+// clusterProfile is a YAML partial of a PostgresCluster that is merged over the
+// built-in template by 'pgo create postgrescluster --profile'.
+type clusterProfile struct {
+	Name string
+
+	// Source describes where the profile was found: a file path or a
+	// namespace and ConfigMap name.
+	Source string
+
+	// Template is the unrendered content of the profile.
+	Template string
+}
+
+// profileValues are the variables available to profile templates.
+type profileValues struct {
+	Name      string
+	Namespace string
+	Version   int
+}
+
+// profileFlags locates profiles for the commands that use them.
+type profileFlags struct {
+	Directory string
+	Namespace string
+}
+
+func (flags *profileFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flags.Directory, "profile-dir", "",
+		`Directory containing profile files; defaults to "pgo/profiles" in the user configuration directory`)
+	cmd.Flags().StringVar(&flags.Namespace, "profile-namespace", "",
+		"Namespace containing profile ConfigMaps; defaults to the current namespace")
+}
+
+// directory returns the directory in which to find profile files. By default,
+// this is "pgo/profiles" in the user's configuration directory, or blank when
+// that cannot be determined.
+// - https://pkg.go.dev/os#UserConfigDir
+func (flags profileFlags) directory() string {
+	if flags.Directory != "" {
+		return flags.Directory
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "pgo", "profiles")
+	}
+	return ""
+}
+
+// newCreateProfileCommand returns the profile subcommand of the create command.
+// Its subcommands inspect the profiles available to 'create postgrescluster'.
+func newCreateProfileCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "profile",
+		Aliases: []string{"profiles"},
+		Short:   "Inspect PostgresCluster profiles",
+		Long: `Inspect the profiles available to "pgo create postgrescluster --profile".
+
+Profiles are YAML partials of a PostgresCluster. They are read from files named
+PROFILE.yaml in the profile directory and from ConfigMaps in a namespace that
+have the "` + util.LabelProfile + `=PROFILE" label and a
+"` + profileConfigMapKey + `" key. A file takes precedence over a ConfigMap of the same name.
+
+Profiles are Go templates with the following variables:
+    {{ .Name }}       the name of the PostgresCluster
+    {{ .Namespace }}  the namespace of the PostgresCluster
+    {{ .Version }}    the Postgres major version`,
+	}
+
+	cmd.AddCommand(
+		newCreateProfileListCommand(config),
+		newCreateProfileShowCommand(config),
+	)
+
+	return cmd
+}
+
+// newCreateProfileListCommand returns the list subcommand of the profile command.
+func newCreateProfileListCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List PostgresCluster profiles",
+		Long: `List the profiles available to "pgo create postgrescluster --profile".
+
+### RBAC Requirements
+    Resources   Verbs
+    ---------   -----
+    configmaps  [list]
+
+### Usage`,
+	}
+
+	cmd.Args = cobra.NoArgs
+
+	var flags profileFlags
+	flags.AddFlags(cmd)
+
+	cmd.Example = internal.FormatExample(`# List the profiles in the profile directory and current namespace
+pgo create profile list
+
+### Example output
+NAME      SOURCE
+dev       /home/user/.config/pgo/profiles/dev.yaml
+prod-ha   configmap postgres-operator/pgo-profile-prod-ha`)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		profiles, err := flags.list(context.Background(), config)
+		if err != nil {
+			return err
+		}
+
+		if len(profiles) == 0 {
+			cmd.Println("No profiles found.")
+			return nil
+		}
+
+		writer := tabwriter.NewWriter(cmd.OutOrStdout(), 10, 2, 2, ' ', 0)
+		if _, err := fmt.Fprintln(writer, "NAME\tSOURCE"); err != nil {
+			return err
+		}
+		for _, profile := range profiles {
+			if _, err := fmt.Fprintf(writer, "%s\t%s\n", profile.Name, profile.Source); err != nil {
+				return err
+			}
+		}
+		return writer.Flush()
+	}
+
+	return cmd
+}
+
+// newCreateProfileShowCommand returns the show subcommand of the profile command.
+func newCreateProfileShowCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show PROFILE",
+		Short: "Show a PostgresCluster profile",
+		Long: `Show the content of a profile available to "pgo create postgrescluster --profile".
+
+### RBAC Requirements
+    Resources   Verbs
+    ---------   -----
+    configmaps  [list]
+
+### Usage`,
+	}
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	var flags profileFlags
+	flags.AddFlags(cmd)
+
+	cmd.Example = internal.FormatExample(`# Show the 'prod-ha' profile
+pgo create profile show prod-ha
+
+### Example output
+# Source: configmap postgres-operator/pgo-profile-prod-ha
+spec:
+  instances:
+  - name: pgha
+    replicas: 3`)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		profile, err := flags.find(context.Background(), config, args[0])
+		if err != nil {
+			return err
+		}
+
+		cmd.Printf("# Source: %s\n", profile.Source)
+		cmd.Print(profile.Template)
+		if !strings.HasSuffix(profile.Template, "\n") {
+			cmd.Println()
+		}
+		return nil
+	}
+
+	return cmd
+}
+
+// list returns all the profiles found in the profile directory and namespace,
+// sorted by name. Files take precedence over ConfigMaps of the same name.
+func (flags profileFlags) list(ctx context.Context, config *internal.Config) ([]clusterProfile, error) {
+	profiles, err := readProfileDirectory(flags.directory())
+	if err != nil {
+		return nil, err
+	}
+
+	client, namespace, err := flags.client(config)
+	if err != nil {
+		return nil, err
+	}
+
+	fromConfigMaps, err := listProfileConfigMaps(ctx, client, namespace, "")
+	if apierrors.IsForbidden(err) {
+		_, _ = fmt.Fprintf(config.ErrOut, "WARNING: unable to list profile ConfigMaps: %v\n", err)
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		seen[profile.Name] = true
+	}
+	for _, profile := range fromConfigMaps {
+		if !seen[profile.Name] {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles, nil
+}
+
+// find returns the profile called name. A file in the profile directory takes
+// precedence over a ConfigMap.
+func (flags profileFlags) find(ctx context.Context, config *internal.Config, name string) (*clusterProfile, error) {
+	if profile, err := readProfileFile(flags.directory(), name); err != nil || profile != nil {
+		return profile, err
+	}
+
+	client, namespace, err := flags.client(config)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := listProfileConfigMaps(ctx, client, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(profiles) {
+	case 0:
+		return nil, fmt.Errorf("profile %q not found in %q or namespace %q",
+			name, flags.directory(), namespace)
+	case 1:
+		return &profiles[0], nil
+	default:
+		return nil, fmt.Errorf("profile %q is defined by %d ConfigMaps in namespace %q",
+			name, len(profiles), namespace)
+	}
+}
+
+// client returns a ConfigMap client and the namespace in which to find profiles.
+func (flags profileFlags) client(config *internal.Config) (corev1client.ConfigMapsGetter, string, error) {
+	namespace := flags.Namespace
+	if namespace == "" {
+		var err error
+		if namespace, err = config.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	rest, err := config.ToRESTConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := corev1client.NewForConfig(rest)
+	return client, namespace, err
+}
+
+// readProfileDirectory returns the profiles in dir. A missing directory has no
+// profiles.
+func readProfileDirectory(dir string) ([]clusterProfile, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []clusterProfile
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		// #nosec G304 -- We intentionally read the directory supplied by the user.
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		profiles = append(profiles, clusterProfile{
+			Name:     strings.TrimSuffix(name, ext),
+			Source:   filepath.Join(dir, name),
+			Template: string(content),
+		})
+	}
+
+	return profiles, nil
+}
+
+// readProfileFile returns the profile called name in dir, or nil when there is
+// no such file.
+func readProfileFile(dir, name string) (*clusterProfile, error) {
+	if dir == "" || name != filepath.Base(name) {
+		return nil, nil
+	}
+
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(dir, name+ext)
+
+		// #nosec G304 -- We intentionally read the directory supplied by the user.
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &clusterProfile{Name: name, Source: path, Template: string(content)}, nil
+	}
+
+	return nil, nil
+}
+
+// listProfileConfigMaps returns the profiles stored in ConfigMaps in namespace.
+// When name is not blank, only that profile is returned.
+func listProfileConfigMaps(
+	ctx context.Context, client corev1client.ConfigMapsGetter, namespace, name string,
+) ([]clusterProfile, error) {
+	selector := util.LabelProfile
+	if name != "" {
+		selector = util.LabelProfile + "=" + name
+	}
+
+	list, err := client.ConfigMaps(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []clusterProfile
+	for _, cm := range list.Items {
+		content, ok := cm.Data[profileConfigMapKey]
+		if !ok || cm.Labels[util.LabelProfile] == "" {
+			continue
+		}
+
+		profiles = append(profiles, clusterProfile{
+			Name:     cm.Labels[util.LabelProfile],
+			Source:   "configmap " + cm.Namespace + "/" + cm.Name,
+			Template: content,
+		})
+	}
+
+	return profiles, nil
+}
+
+// render executes the profile template with values and returns the resulting
+// YAML object.
+func (profile clusterProfile) render(values profileValues) (map[string]any, error) {
+	tmpl, err := template.New(profile.Name).
+		Option("missingkey=error").
+		Parse(profile.Template)
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+
+	var object map[string]any
+	if err := yaml.Unmarshal(buf.Bytes(), &object); err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+
+	return object, nil
+}
+
+// mergeProfile merges src into dst. Objects are merged recursively and all
+// other values, including lists, replace those in dst. A null value in src
+// removes the field from dst.
+func mergeProfile(dst, src map[string]any) {
+	for key, value := range src {
+		if value == nil {
+			delete(dst, key)
+			continue
+		}
+
+		srcMap, srcOK := value.(map[string]any)
+		dstMap, dstOK := dst[key].(map[string]any)
+
+		if srcOK && dstOK {
+			mergeProfile(dstMap, srcMap)
+		} else {
+			dst[key] = value
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestClusterProfileRender(t *testing.T) {
+	values := profileValues{Name: "hippo", Namespace: "zoo", Version: 16}
+
+	t.Run("Variables", func(t *testing.T) {
+		profile := clusterProfile{Name: "dev", Template: `
+metadata:
+  labels:
+    team: '{{ .Namespace }}'
+spec:
+  image: registry.example.com/postgres:{{ .Version }}
+  users:
+  - name: '{{ .Name }}'
+`}
+
+		object, err := profile.render(values)
+		assert.NilError(t, err)
+		assert.Assert(t, cmp.MarshalMatches(object, `
+metadata:
+  labels:
+    team: zoo
+spec:
+  image: registry.example.com/postgres:16
+  users:
+  - name: hippo
+`))
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		profile := clusterProfile{Name: "dev", Template: `spec: { image: '{{ .Image }}' }`}
+
+		_, err := profile.render(values)
+		assert.ErrorContains(t, err, `profile "dev"`)
+		assert.ErrorContains(t, err, "Image")
+	})
+
+	t.Run("InvalidYAML", func(t *testing.T) {
+		profile := clusterProfile{Name: "dev", Template: `spec: [`}
+
+		_, err := profile.render(values)
+		assert.ErrorContains(t, err, `profile "dev"`)
+	})
+}
+
+func TestMergeProfile(t *testing.T) {
+	cluster, err := generateUnstructuredClusterYaml("hippo", "16")
+	assert.NilError(t, err)
+
+	profile := clusterProfile{Name: "prod-ha", Template: `
+spec:
+  instances:
+  - name: pgha
+    replicas: 3
+  backups:
+    pgbackrest:
+      global:
+        repo1-retention-full: "14"
+  monitoring: null
+  patroni:
+    dynamicConfiguration:
+      postgresql:
+        parameters:
+          max_connections: 500
+`}
+
+	partial, err := profile.render(profileValues{Name: "hippo", Version: 16})
+	assert.NilError(t, err)
+
+	mergeProfile(cluster.Object, partial)
+	assert.Assert(t, cmp.MarshalMatches(cluster, `
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PostgresCluster
+metadata:
+  name: hippo
+spec:
+  backups:
+    pgbackrest:
+      global:
+        repo1-retention-full: "14"
+      repos:
+      - name: repo1
+        volume:
+          volumeClaimSpec:
+            accessModes:
+            - ReadWriteOnce
+            resources:
+              requests:
+                storage: 1Gi
+  instances:
+  - name: pgha
+    replicas: 3
+  patroni:
+    dynamicConfiguration:
+      postgresql:
+        parameters:
+          max_connections: 500
+  postgresVersion: 16
+`))
+
+	t.Run("NullRemoves", func(t *testing.T) {
+		mergeProfile(cluster.Object, map[string]any{
+			"spec": map[string]any{"backups": nil},
+		})
+
+		_, found := cluster.Object["spec"].(map[string]any)["backups"]
+		assert.Assert(t, !found)
+	})
+}
+
+func TestReadProfileDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "dev.yaml"), []byte("spec: {}"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "prod.yml"), []byte("spec: {}"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0o600))
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "nested.yaml"), 0o700))
+
+	profiles, err := readProfileDirectory(dir)
+	assert.NilError(t, err)
+	assert.Equal(t, len(profiles), 2)
+	assert.Equal(t, profiles[0].Name, "dev")
+	assert.Equal(t, profiles[1].Name, "prod")
+	assert.Equal(t, profiles[1].Source, filepath.Join(dir, "prod.yml"))
+
+	t.Run("Missing", func(t *testing.T) {
+		profiles, err := readProfileDirectory(filepath.Join(dir, "missing"))
+		assert.NilError(t, err)
+		assert.Equal(t, len(profiles), 0)
+	})
+
+	t.Run("File", func(t *testing.T) {
+		profile, err := readProfileFile(dir, "prod")
+		assert.NilError(t, err)
+		assert.Assert(t, profile != nil)
+		assert.Equal(t, profile.Template, "spec: {}")
+
+		profile, err = readProfileFile(dir, "missing")
+		assert.NilError(t, err)
+		assert.Assert(t, profile == nil)
+
+		// Profile names cannot escape the directory.
+		profile, err = readProfileFile(filepath.Join(dir, "sub"), "../dev")
+		assert.NilError(t, err)
+		assert.Assert(t, profile == nil)
+	})
+}
+
+func TestListProfileConfigMaps(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "zoo", Name: "pgo-profile-dev",
+				Labels: map[string]string{"postgres-operator.crunchydata.com/pgo-profile": "dev"},
+			},
+			Data: map[string]string{"profile.yaml": "spec: {}"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "zoo", Name: "no-key",
+				Labels: map[string]string{"postgres-operator.crunchydata.com/pgo-profile": "other"},
+			},
+			Data: map[string]string{"other.yaml": "spec: {}"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "zoo", Name: "unlabeled"},
+			Data:       map[string]string{"profile.yaml": "spec: {}"},
+		},
+	)
+
+	profiles, err := listProfileConfigMaps(ctx, client.CoreV1(), "zoo", "")
+	assert.NilError(t, err)
+	assert.Equal(t, len(profiles), 1)
+	assert.Equal(t, profiles[0].Name, "dev")
+	assert.Equal(t, profiles[0].Source, "configmap zoo/pgo-profile-dev")
+
+	profiles, err = listProfileConfigMaps(ctx, client.CoreV1(), "zoo", "other")
+	assert.NilError(t, err)
+	assert.Equal(t, len(profiles), 0)
+}
@@ -0,0 +1,840 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// repoTypes are the kinds of pgBackRest repositories that PGO supports. Each
+// is the name of a field in "spec.backups.pgbackrest.repos[]".
+var repoTypes = []string{"s3", "gcs", "azure", "volume"}
+
+// repoNamePattern matches the repository names that PGO accepts.
+var repoNamePattern = regexp.MustCompile(`^repo[1-4]$`)
+
+// newRepoCommand returns the repo command of the PGO plugin. Subcommands of
+// repo manage the pgBackRest repositories of a PostgresCluster.
+func newRepoCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repo",
+		Short: "Manage the pgBackRest repositories of a PostgresCluster",
+		Long: `Manage the pgBackRest repositories in "spec.backups.pgbackrest.repos" of a
+PostgresCluster. Credentials of cloud repositories are stored in a Secret that
+is attached to the cluster using "spec.backups.pgbackrest.configuration".
+
+Changes are sent using server-side apply, so only repositories added by this
+command can be removed by it.`,
+	}
+
+	cmd.AddCommand(
+		newRepoAddCommand(config),
+		newRepoListCommand(config),
+		newRepoRemoveCommand(config),
+	)
+
+	return cmd
+}
+
+func newRepoAddCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add CLUSTER_NAME REPO_NAME --type TYPE",
+		Short: "Add a pgBackRest repository to a PostgresCluster",
+		Long: `Add a pgBackRest repository to a PostgresCluster. REPO_NAME is one of repo1,
+repo2, repo3, or repo4.
+
+Cloud repositories (s3, gcs, azure) need --bucket and --credentials-from-file.
+The credentials file of s3 has "key" and "key-secret" options, and that of
+azure has "account" and "key" options, one "name=value" per line. AWS names
+such as "aws_access_key_id" are accepted too. The credentials file of gcs is a
+service account key in JSON. The credentials are stored in a Secret named
+CLUSTER_NAME-pgbackrest-REPO_NAME.
+
+Use --uri-style=path and --verify-tls=false with S3-compatible storage, such
+as MinIO, that is reached by IP address or with a self-signed certificate.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+    secrets                                             [patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Add an S3 repository to the 'hippo' postgrescluster
+pgo repo add hippo repo2 --type s3 --bucket hippo-backups \
+  --endpoint s3.us-east-1.amazonaws.com --region us-east-1 \
+  --credentials-from-file ./s3.conf
+
+# Add a repository in a local MinIO
+pgo repo add hippo repo2 --type s3 --bucket hippo-backups \
+  --endpoint minio.minio.svc:9000 --region us-east-1 \
+  --uri-style path --verify-tls=false --credentials-from-file ./minio.conf
+
+# Add a repository on a 10Gi volume
+pgo repo add hippo repo3 --type volume --size 10Gi
+
+### Example output
+secrets/hippo-pgbackrest-repo2 applied
+postgresclusters/hippo repo2 added`)
+
+	repo := pgBackRestRepo{Config: config}
+
+	cmd.Flags().BoolVar(&repo.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the repository settings")
+	cmd.Flags().StringVar(&repo.Type, "type", "", "the type of repository: s3, gcs, azure, or volume (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("type"))
+
+	cmd.Flags().StringVar(&repo.Bucket, "bucket", "", "the bucket of s3 or gcs, or the container of azure")
+	cmd.Flags().StringVar(&repo.Endpoint, "endpoint", "", "the endpoint of s3")
+	cmd.Flags().StringVar(&repo.Region, "region", "", "the region of s3")
+	cmd.Flags().StringVar(&repo.URIStyle, "uri-style", "", "the URI style of s3: host or path")
+	cmd.Flags().BoolVar(&repo.VerifyTLS, "verify-tls", true, "verify the TLS certificate of cloud storage")
+	cmd.Flags().StringVar(&repo.Path, "path", "", "the path of the repository; PGO sets /pgbackrest/REPO_NAME by default")
+	cmd.Flags().StringVar(&repo.Size, "size", "", "the size of a volume repository, such as 10Gi")
+	cmd.Flags().StringVar(&repo.CredentialsFile, "credentials-from-file", "", "path to a file of cloud storage credentials")
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		repo.ClusterName, repo.RepoName = args[0], args[1]
+
+		var credentials []byte
+		if repo.CredentialsFile != "" {
+			var err error
+			if credentials, err = os.ReadFile(repo.CredentialsFile); err != nil {
+				return err
+			}
+		}
+		return repo.Add(context.Background(), credentials)
+	}
+
+	return cmd
+}
+
+func newRepoListCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list CLUSTER_NAME",
+		Short: "List the pgBackRest repositories of a PostgresCluster",
+		Long: `List the pgBackRest repositories of a PostgresCluster with their location and
+the retention settings in "spec.backups.pgbackrest.global".
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# List the repositories of the 'hippo' postgrescluster
+pgo repo list hippo
+
+### Example output
+REPO    TYPE     LOCATION                                    RETENTION FULL   RETENTION DIFF
+repo1   volume   1Gi                                         2 (count)        -
+repo2   s3       s3://hippo-backups (minio.minio.svc:9000)   14 (time)        7`)
+
+	repo := pgBackRestRepo{Config: config}
+
+	var outputEnum = util.TextOutput
+	cmd.Flags().VarP(&outputEnum, "output", "o",
+		"output format. types supported: text,json")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		repo.ClusterName = args[0]
+		return repo.List(context.Background(), outputEnum == util.JSONOutput)
+	}
+
+	return cmd
+}
+
+func newRepoRemoveCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove CLUSTER_NAME REPO_NAME",
+		Short: "Remove a pgBackRest repository from a PostgresCluster",
+		Long: `Remove a pgBackRest repository that was added by "pgo repo add" from a
+PostgresCluster, along with its Secret and options. Backups stay in cloud
+storage, but a volume repository is deleted with its backups.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+    secrets                                             [delete]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Remove repo2 from the 'hippo' postgrescluster
+pgo repo remove hippo repo2
+
+### Example output
+WARNING: Backups in repo2 can no longer be used to restore postgresclusters/hippo.
+Are you sure you want to continue? (yes/no): yes
+postgresclusters/hippo repo2 removed
+secrets/hippo-pgbackrest-repo2 deleted`)
+
+	repo := pgBackRestRepo{Config: config}
+
+	cmd.Flags().BoolVar(&repo.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the repository settings")
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		repo.ClusterName, repo.RepoName = args[0], args[1]
+		return repo.Remove(context.Background())
+	}
+
+	return cmd
+}
+
+// pgBackRestRepo adds, lists, and removes the pgBackRest repositories of a
+// PostgresCluster.
+type pgBackRestRepo struct {
+	*internal.Config
+
+	Bucket          string
+	ClusterName     string
+	CredentialsFile string
+	Endpoint        string
+	ForceConflicts  bool
+	Path            string
+	Region          string
+	RepoName        string
+	Size            string
+	Type            string
+	URIStyle        string
+	VerifyTLS       bool
+}
+
+// repoRow is one row of "pgo repo list".
+type repoRow struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Location      string `json:"location"`
+	RetentionFull string `json:"retentionFull,omitempty"`
+	RetentionType string `json:"retentionFullType,omitempty"`
+	RetentionDiff string `json:"retentionDiff,omitempty"`
+}
+
+// secretName returns the name of the Secret that holds the credentials of
+// the repository.
+func (repo pgBackRestRepo) secretName() string {
+	return repo.ClusterName + "-pgbackrest-" + repo.RepoName
+}
+
+// validate checks that the flags make sense for the type of repository.
+func (repo pgBackRestRepo) validate() error {
+	if !repoNamePattern.MatchString(repo.RepoName) {
+		return fmt.Errorf("invalid repository name %q: expected repo1, repo2, repo3, or repo4", repo.RepoName)
+	}
+	if !slices.Contains(repoTypes, repo.Type) {
+		return fmt.Errorf("invalid type %q: expected one of %s", repo.Type, strings.Join(repoTypes, ", "))
+	}
+
+	var missing []string
+	require := func(flag, value string) {
+		if value == "" {
+			missing = append(missing, "--"+flag)
+		}
+	}
+
+	cloud := repo.Type != "volume"
+	switch repo.Type {
+	case "s3":
+		require("bucket", repo.Bucket)
+		require("endpoint", repo.Endpoint)
+		require("region", repo.Region)
+	case "gcs", "azure":
+		require("bucket", repo.Bucket)
+	case "volume":
+		require("size", repo.Size)
+	}
+	if cloud {
+		require("credentials-from-file", repo.CredentialsFile)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("a repository of type %s requires %s", repo.Type, strings.Join(missing, ", "))
+	}
+
+	if repo.Type != "s3" && (repo.Endpoint != "" || repo.Region != "" || repo.URIStyle != "") {
+		return fmt.Errorf("--endpoint, --region, and --uri-style apply only to s3")
+	}
+	if repo.URIStyle != "" && repo.URIStyle != "host" && repo.URIStyle != "path" {
+		return fmt.Errorf("invalid --uri-style %q: expected host or path", repo.URIStyle)
+	}
+	if !cloud && (repo.Bucket != "" || repo.CredentialsFile != "" || !repo.VerifyTLS) {
+		return fmt.Errorf("--bucket, --credentials-from-file, and --verify-tls apply only to cloud repositories")
+	}
+	if repo.Size != "" {
+		if cloud {
+			return fmt.Errorf("--size applies only to volume repositories")
+		}
+		if _, err := resource.ParseQuantity(repo.Size); err != nil {
+			return fmt.Errorf("invalid --size %q: %w", repo.Size, err)
+		}
+	}
+	return nil
+}
+
+// credentialOptions maps the names in a credentials file to the pgBackRest
+// options of each type of repository.
+var credentialOptions = map[string]map[string]string{
+	"s3": {
+		"key": "s3-key", "aws_access_key_id": "s3-key",
+		"key-secret": "s3-key-secret", "aws_secret_access_key": "s3-key-secret",
+		"token": "s3-token", "aws_session_token": "s3-token",
+	},
+	"azure": {
+		"account": "azure-account",
+		"key":     "azure-key",
+	},
+}
+
+// requiredCredentials are the pgBackRest options that each type of repository
+// needs in its credentials file.
+var requiredCredentials = map[string][]string{
+	"s3":    {"s3-key", "s3-key-secret"},
+	"azure": {"azure-account", "azure-key"},
+}
+
+// secretData returns the files of the Secret that holds the credentials of
+// the repository. Each file is projected into the pgBackRest configuration
+// directory, /etc/pgbackrest/conf.d.
+func (repo pgBackRestRepo) secretData(credentials []byte) (map[string]string, error) {
+	if repo.Type == "gcs" {
+		if !json.Valid(credentials) {
+			return nil, fmt.Errorf("the credentials of gcs must be a service account key in JSON")
+		}
+		keyFile := repo.RepoName + "-gcs-key.json"
+		return map[string]string{
+			keyFile: string(credentials),
+			repo.RepoName + "-gcs.conf": "[global]\n" +
+				repo.RepoName + "-gcs-key=/etc/pgbackrest/conf.d/" + keyFile + "\n",
+		}, nil
+	}
+
+	names := credentialOptions[repo.Type]
+	options := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(credentials))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "[") {
+			continue
+		}
+		name, value, ok := strings.Cut(text, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		option, known := names[strings.ToLower(name)]
+		if !ok || !known || value == "" {
+			return nil, fmt.Errorf("invalid credentials on line %d: expected one of %s",
+				line, strings.Join(slices.Sorted(maps.Keys(names)), ", "))
+		}
+		options[option] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var conf strings.Builder
+	conf.WriteString("[global]\n")
+	for _, option := range slices.Sorted(maps.Keys(options)) {
+		_, _ = fmt.Fprintf(&conf, "%s-%s=%s\n", repo.RepoName, option, options[option])
+	}
+
+	for _, option := range requiredCredentials[repo.Type] {
+		if _, ok := options[option]; !ok {
+			return nil, fmt.Errorf("the credentials of %s are missing %q", repo.Type, option)
+		}
+	}
+
+	return map[string]string{repo.RepoName + "-" + repo.Type + ".conf": conf.String()}, nil
+}
+
+// repoSpec returns the fields of the repository in "spec.backups.pgbackrest.repos".
+func (repo pgBackRestRepo) repoSpec() map[string]any {
+	spec := map[string]any{"name": repo.RepoName}
+	switch repo.Type {
+	case "s3":
+		spec["s3"] = map[string]any{
+			"bucket": repo.Bucket, "endpoint": repo.Endpoint, "region": repo.Region,
+		}
+	case "gcs":
+		spec["gcs"] = map[string]any{"bucket": repo.Bucket}
+	case "azure":
+		spec["azure"] = map[string]any{"container": repo.Bucket}
+	case "volume":
+		spec["volume"] = map[string]any{
+			"volumeClaimSpec": map[string]any{
+				"accessModes": []any{"ReadWriteOnce"},
+				"resources": map[string]any{
+					"requests": map[string]any{"storage": repo.Size},
+				},
+			},
+		}
+	}
+	return spec
+}
+
+// globalOptions returns the pgBackRest options of the repository in
+// "spec.backups.pgbackrest.global".
+func (repo pgBackRestRepo) globalOptions() map[string]string {
+	options := map[string]string{}
+	if repo.Path != "" {
+		options[repo.RepoName+"-path"] = repo.Path
+	}
+	if repo.URIStyle != "" {
+		options[repo.RepoName+"-s3-uri-style"] = repo.URIStyle
+	}
+	if !repo.VerifyTLS {
+		options[repo.RepoName+"-storage-verify-tls"] = "n"
+	}
+	return options
+}
+
+// configurationIntent returns the projections of cluster with or without the
+// Secret of the repository. The list is atomic, so it must hold the
+// projections of other clients, too.
+func (repo pgBackRestRepo) configurationIntent(cluster *unstructured.Unstructured, attach bool) []any {
+	list, _, _ := unstructured.NestedSlice(cluster.Object, "spec", "backups", "pgbackrest", "configuration")
+
+	var result []any
+	for _, item := range list {
+		name, _, _ := unstructured.NestedString(asMap(item), "secret", "name")
+		if name != repo.secretName() {
+			result = append(result, item)
+		}
+	}
+	if attach {
+		result = append(result, map[string]any{
+			"secret": map[string]any{"name": repo.secretName()},
+		})
+	}
+	return result
+}
+
+func asMap(item any) map[string]any {
+	m, _ := item.(map[string]any)
+	return m
+}
+
+// addIntent adds the repository to intent, replacing any repository with the
+// same name and any options of the repository this client set before.
+func (repo pgBackRestRepo) addIntent(intent, cluster *unstructured.Unstructured) error {
+	repos := []any{}
+	for _, item := range clusterRepos(intent) {
+		if item["name"] != repo.RepoName {
+			repos = append(repos, item)
+		}
+	}
+	repos = append(repos, repo.repoSpec())
+	if err := unstructured.SetNestedSlice(intent.Object, repos,
+		"spec", "backups", "pgbackrest", "repos"); err != nil {
+		return err
+	}
+
+	global, _, _ := unstructured.NestedStringMap(intent.Object, "spec", "backups", "pgbackrest", "global")
+	for name := range global {
+		if strings.HasPrefix(name, repo.RepoName+"-") {
+			delete(global, name)
+		}
+	}
+	for name, value := range repo.globalOptions() {
+		if global == nil {
+			global = map[string]string{}
+		}
+		global[name] = value
+	}
+	if len(global) > 0 {
+		if err := unstructured.SetNestedStringMap(intent.Object, global,
+			"spec", "backups", "pgbackrest", "global"); err != nil {
+			return err
+		}
+	} else {
+		unstructured.RemoveNestedField(intent.Object, "spec", "backups", "pgbackrest", "global")
+	}
+
+	// Volume repositories have no credentials to attach.
+	if repo.Type == "volume" {
+		return nil
+	}
+	return unstructured.SetNestedSlice(intent.Object, repo.configurationIntent(cluster, true),
+		"spec", "backups", "pgbackrest", "configuration")
+}
+
+// removeIntent removes the repository and its options from intent. It returns
+// false when this client did not add the repository.
+func (repo pgBackRestRepo) removeIntent(intent, cluster *unstructured.Unstructured) (bool, error) {
+	repos := clusterRepos(intent)
+	index := slices.IndexFunc(repos, func(item map[string]any) bool {
+		return item["name"] == repo.RepoName
+	})
+	if index < 0 {
+		return false, nil
+	}
+
+	remaining := []any{}
+	for i, item := range repos {
+		if i != index {
+			remaining = append(remaining, item)
+		}
+	}
+	if len(remaining) > 0 {
+		if err := unstructured.SetNestedSlice(intent.Object, remaining,
+			"spec", "backups", "pgbackrest", "repos"); err != nil {
+			return false, err
+		}
+	} else {
+		unstructured.RemoveNestedField(intent.Object, "spec", "backups", "pgbackrest", "repos")
+	}
+
+	global, _, _ := unstructured.NestedStringMap(intent.Object, "spec", "backups", "pgbackrest", "global")
+	for name := range global {
+		if strings.HasPrefix(name, repo.RepoName+"-") {
+			delete(global, name)
+		}
+	}
+	if len(global) > 0 {
+		if err := unstructured.SetNestedStringMap(intent.Object, global,
+			"spec", "backups", "pgbackrest", "global"); err != nil {
+			return false, err
+		}
+	} else {
+		unstructured.RemoveNestedField(intent.Object, "spec", "backups", "pgbackrest", "global")
+	}
+
+	// Detach the Secret only when it is attached.
+	list, _, _ := unstructured.NestedSlice(cluster.Object, "spec", "backups", "pgbackrest", "configuration")
+	if configuration := repo.configurationIntent(cluster, false); len(configuration) < len(list) {
+		if len(configuration) > 0 {
+			if err := unstructured.SetNestedSlice(intent.Object, configuration,
+				"spec", "backups", "pgbackrest", "configuration"); err != nil {
+				return false, err
+			}
+		} else {
+			unstructured.RemoveNestedField(intent.Object, "spec", "backups", "pgbackrest", "configuration")
+		}
+	}
+
+	for _, fields := range [][]string{
+		{"spec", "backups", "pgbackrest"}, {"spec", "backups"}, {"spec"},
+	} {
+		if m, found, _ := unstructured.NestedMap(intent.Object, fields...); found && len(m) == 0 {
+			unstructured.RemoveNestedField(intent.Object, fields...)
+		}
+	}
+	return true, nil
+}
+
+// repoRows returns the repositories of cluster and their retention settings.
+func repoRows(cluster *unstructured.Unstructured) []repoRow {
+	global, _, _ := unstructured.NestedStringMap(cluster.Object, "spec", "backups", "pgbackrest", "global")
+
+	var rows []repoRow
+	for _, repo := range clusterRepos(cluster) {
+		name, _ := repo["name"].(string)
+		row := repoRow{
+			Name:          name,
+			RetentionFull: global[name+"-retention-full"],
+			RetentionType: global[name+"-retention-full-type"],
+			RetentionDiff: global[name+"-retention-diff"],
+		}
+
+		for _, t := range repoTypes {
+			if spec, ok := repo[t].(map[string]any); ok {
+				row.Type = t
+				row.Location = repoLocation(t, spec)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// repoLocation describes where a repository of type t keeps its backups.
+func repoLocation(t string, spec map[string]any) string {
+	switch t {
+	case "s3":
+		bucket, _ := spec["bucket"].(string)
+		endpoint, _ := spec["endpoint"].(string)
+		return fmt.Sprintf("s3://%s (%s)", bucket, endpoint)
+	case "gcs":
+		bucket, _ := spec["bucket"].(string)
+		return "gs://" + bucket
+	case "azure":
+		container, _ := spec["container"].(string)
+		return "azure://" + container
+	case "volume":
+		storage, _, _ := unstructured.NestedString(spec, "volumeClaimSpec", "resources", "requests", "storage")
+		return storage
+	}
+	return ""
+}
+
+func printRepoRows(w io.Writer, rows []repoRow, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if rows == nil {
+			rows = []repoRow{}
+		}
+		return encoder.Encode(rows)
+	}
+
+	orNone := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "REPO\tTYPE\tLOCATION\tRETENTION FULL\tRETENTION DIFF\n"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		full := orNone(row.RetentionFull)
+		if row.RetentionFull != "" {
+			// The default type of "retention-full" is count.
+			// - https://pgbackrest.org/configuration.html#section-repository/option-repo-retention-full-type
+			full += " (" + cmp.Or(row.RetentionType, "count") + ")"
+		}
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\t%s\n",
+			row.Name, row.Type, row.Location, full, orNone(row.RetentionDiff),
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// applySecret creates or updates the Secret of the repository. The Secret is
+// owned by cluster so it is deleted along with it.
+func (repo pgBackRestRepo) applySecret(ctx context.Context,
+	core corev1client.CoreV1Interface, cluster *unstructured.Unstructured, data map[string]string,
+) error {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      repo.secretName(),
+			Namespace: cluster.GetNamespace(),
+			Labels: map[string]string{
+				util.LabelCluster:        repo.ClusterName,
+				util.LabelPGBackRestRepo: repo.RepoName,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: cluster.GetAPIVersion(),
+				Kind:       cluster.GetKind(),
+				Name:       cluster.GetName(),
+				UID:        cluster.GetUID(),
+			}},
+		},
+		StringData: data,
+	}
+
+	patch, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
+
+	// This client owns the Secret, so take it over from anyone who changed it.
+	force := true
+	_, err = core.Secrets(cluster.GetNamespace()).Patch(ctx, secret.Name, types.ApplyPatchType, patch,
+		repo.Patch.PatchOptions(metav1.PatchOptions{Force: &force}))
+	return err
+}
+
+// Add validates the flags and credentials, stores the credentials in a Secret,
+// and adds the repository to the cluster.
+func (repo pgBackRestRepo) Add(ctx context.Context, credentials []byte) error {
+	if err := repo.validate(); err != nil {
+		return err
+	}
+
+	var data map[string]string
+	if repo.Type != "volume" {
+		var err error
+		if data, err = repo.secretData(credentials); err != nil {
+			return err
+		}
+	}
+
+	mapping, client, err := v1beta1.NewPostgresClusterClient(repo)
+	if err != nil {
+		return err
+	}
+	namespace, err := repo.Namespace()
+	if err != nil {
+		return err
+	}
+
+	// Fetch the cluster to (1) see if the repository exists and (2) extract CLI managed fields.
+	cluster, err := client.Namespace(namespace).Get(ctx, repo.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, repo.Patch.FieldManager); err != nil {
+		return err
+	}
+
+	owned := slices.ContainsFunc(clusterRepos(intent), func(item map[string]any) bool {
+		return item["name"] == repo.RepoName
+	})
+	exists := slices.ContainsFunc(clusterRepos(cluster), func(item map[string]any) bool {
+		return item["name"] == repo.RepoName
+	})
+	if exists && !owned && !repo.ForceConflicts {
+		return fmt.Errorf("repository %q already exists in %s/%s",
+			repo.RepoName, mapping.Resource.Resource, repo.ClusterName)
+	}
+
+	if data != nil {
+		rest, err := repo.ToRESTConfig()
+		if err != nil {
+			return err
+		}
+		core, err := corev1client.NewForConfig(rest)
+		if err != nil {
+			return err
+		}
+		if err := repo.applySecret(ctx, core, cluster, data); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(repo.Out, "secrets/%s applied\n", repo.secretName())
+	}
+
+	if err := repo.addIntent(intent, cluster); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, repo.Config, client.Namespace(namespace), repo.ClusterName,
+		intent, repo.ForceConflicts); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(repo.Out, "%s/%s %s added\n",
+		mapping.Resource.Resource, repo.ClusterName, repo.RepoName)
+	return nil
+}
+
+// List prints the repositories of the cluster.
+func (repo pgBackRestRepo) List(ctx context.Context, asJSON bool) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(repo)
+	if err != nil {
+		return err
+	}
+	namespace, err := repo.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, repo.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	return printRepoRows(repo.Out, repoRows(cluster), asJSON)
+}
+
+// Remove asks for confirmation and then removes the repository, its options,
+// and its Secret from the cluster.
+func (repo pgBackRestRepo) Remove(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(repo)
+	if err != nil {
+		return err
+	}
+	namespace, err := repo.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, repo.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, repo.Patch.FieldManager); err != nil {
+		return err
+	}
+
+	// Check the cluster before asking for confirmation.
+	removed, err := repo.removeIntent(intent.DeepCopy(), cluster)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fmt.Errorf("repository %q of %s/%s was not added by this command",
+			repo.RepoName, mapping.Resource.Resource, repo.ClusterName)
+	}
+
+	if !confirm(repo.In, repo.Out, fmt.Sprintf(
+		"WARNING: Backups in %s can no longer be used to restore %s/%s."+
+			"\nAre you sure you want to continue? (yes/no): ",
+		repo.RepoName, mapping.Resource.Resource, repo.ClusterName)) {
+		return nil
+	}
+
+	if _, err := repo.removeIntent(intent, cluster); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, repo.Config, client.Namespace(namespace), repo.ClusterName,
+		intent, repo.ForceConflicts); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(repo.Out, "%s/%s %s removed\n",
+		mapping.Resource.Resource, repo.ClusterName, repo.RepoName)
+
+	rest, err := repo.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	core, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+	err = core.Secrets(namespace).Delete(ctx, repo.secretName(), metav1.DeleteOptions{})
+	if err == nil {
+		_, _ = fmt.Fprintf(repo.Out, "secrets/%s deleted\n", repo.secretName())
+	}
+	if apierrors.IsNotFound(err) {
+		err = nil
+	}
+	return err
+}
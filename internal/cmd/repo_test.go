@@ -0,0 +1,221 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestPGBackRestRepoValidate(t *testing.T) {
+	// A local MinIO stand-in for S3
+	minio := pgBackRestRepo{
+		RepoName: "repo2", Type: "s3", Bucket: "backups",
+		Endpoint: "minio.minio.svc:9000", Region: "us-east-1", URIStyle: "path",
+		CredentialsFile: "minio.conf",
+	}
+	assert.NilError(t, minio.validate())
+
+	for _, tt := range []struct {
+		Repo  pgBackRestRepo
+		Error string
+	}{
+		{Repo: pgBackRestRepo{RepoName: "repo5", Type: "s3"}, Error: `invalid repository name "repo5"`},
+		{Repo: pgBackRestRepo{RepoName: "repo2", Type: "nfs"}, Error: `invalid type "nfs"`},
+		{Repo: pgBackRestRepo{RepoName: "repo2", Type: "s3", VerifyTLS: true},
+			Error: "requires --bucket, --endpoint, --region, --credentials-from-file"},
+		{Repo: pgBackRestRepo{RepoName: "repo2", Type: "gcs", Bucket: "b", CredentialsFile: "f", Region: "r"},
+			Error: "apply only to s3"},
+		{Repo: pgBackRestRepo{RepoName: "repo2", Type: "volume", VerifyTLS: true},
+			Error: "requires --size"},
+		{Repo: pgBackRestRepo{RepoName: "repo2", Type: "volume", Size: "lots", VerifyTLS: true},
+			Error: `invalid --size "lots"`},
+		{Repo: pgBackRestRepo{RepoName: "repo2", Type: "volume", Size: "1Gi", Bucket: "b", VerifyTLS: true},
+			Error: "apply only to cloud repositories"},
+	} {
+		assert.ErrorContains(t, tt.Repo.validate(), tt.Error)
+	}
+}
+
+func TestPGBackRestRepoSecretData(t *testing.T) {
+	s3 := pgBackRestRepo{RepoName: "repo2", Type: "s3"}
+
+	data, err := s3.secretData([]byte(`
+[default]
+# MinIO root credentials
+aws_access_key_id = minioadmin
+aws_secret_access_key = minio secret
+`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, data, map[string]string{
+		"repo2-s3.conf": "[global]\nrepo2-s3-key=minioadmin\nrepo2-s3-key-secret=minio secret\n",
+	})
+
+	_, err = s3.secretData([]byte("key=only"))
+	assert.ErrorContains(t, err, `missing "s3-key-secret"`)
+
+	_, err = s3.secretData([]byte("region=us-east-1"))
+	assert.ErrorContains(t, err, "invalid credentials on line 1: expected one of aws_access_key_id")
+
+	azure := pgBackRestRepo{RepoName: "repo3", Type: "azure"}
+	data, err = azure.secretData([]byte("account=hippo\nkey=c2VjcmV0\n"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, data, map[string]string{
+		"repo3-azure.conf": "[global]\nrepo3-azure-account=hippo\nrepo3-azure-key=c2VjcmV0\n",
+	})
+
+	gcs := pgBackRestRepo{RepoName: "repo4", Type: "gcs"}
+	data, err = gcs.secretData([]byte(`{"type": "service_account"}`))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, data, map[string]string{
+		"repo4-gcs-key.json": `{"type": "service_account"}`,
+		"repo4-gcs.conf":     "[global]\nrepo4-gcs-key=/etc/pgbackrest/conf.d/repo4-gcs-key.json\n",
+	})
+
+	_, err = gcs.secretData([]byte("key=value"))
+	assert.ErrorContains(t, err, "service account key in JSON")
+}
+
+func TestPGBackRestRepoIntent(t *testing.T) {
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  backups:
+    pgbackrest:
+      configuration:
+      - secret:
+          name: other
+      global:
+        repo1-retention-full: "2"
+      repos:
+      - name: repo1
+        volume: {}
+`), &cluster.Object))
+
+	repo := pgBackRestRepo{
+		ClusterName: "hippo", RepoName: "repo2", Type: "s3", Bucket: "backups",
+		Endpoint: "minio.minio.svc:9000", Region: "us-east-1", URIStyle: "path",
+	}
+
+	intent := &unstructured.Unstructured{Object: map[string]any{}}
+	assert.NilError(t, repo.addIntent(intent, cluster))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      configuration:
+      - secret:
+          name: other
+      - secret:
+          name: hippo-pgbackrest-repo2
+      global:
+        repo2-s3-uri-style: path
+        repo2-storage-verify-tls: "n"
+      repos:
+      - name: repo2
+        s3:
+          bucket: backups
+          endpoint: minio.minio.svc:9000
+          region: us-east-1
+	`))
+
+	// Adding again replaces the options of the repository.
+	repo.URIStyle, repo.VerifyTLS, repo.Path = "", true, "/hippo"
+	assert.NilError(t, repo.addIntent(intent, cluster))
+	global, _, _ := unstructured.NestedStringMap(intent.Object, "spec", "backups", "pgbackrest", "global")
+	assert.DeepEqual(t, global, map[string]string{"repo2-path": "/hippo"})
+
+	// Simulate the result of applying the intent.
+	assert.NilError(t, unstructured.SetNestedField(cluster.Object,
+		[]any{
+			map[string]any{"secret": map[string]any{"name": "other"}},
+			map[string]any{"secret": map[string]any{"name": "hippo-pgbackrest-repo2"}},
+		}, "spec", "backups", "pgbackrest", "configuration"))
+
+	removed, err := repo.removeIntent(intent, cluster)
+	assert.NilError(t, err)
+	assert.Assert(t, removed)
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      configuration:
+      - secret:
+          name: other
+	`))
+
+	removed, err = repo.removeIntent(intent, cluster)
+	assert.NilError(t, err)
+	assert.Assert(t, !removed)
+
+	volume := pgBackRestRepo{RepoName: "repo3", Type: "volume", Size: "10Gi", VerifyTLS: true}
+	intent = &unstructured.Unstructured{Object: map[string]any{}}
+	assert.NilError(t, volume.addIntent(intent, cluster))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      repos:
+      - name: repo3
+        volume:
+          volumeClaimSpec:
+            accessModes:
+            - ReadWriteOnce
+            resources:
+              requests:
+                storage: 10Gi
+	`))
+}
+
+func TestRepoRows(t *testing.T) {
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  backups:
+    pgbackrest:
+      global:
+        repo1-retention-full: "2"
+        repo2-retention-full: "14"
+        repo2-retention-full-type: time
+        repo2-retention-diff: "7"
+      repos:
+      - name: repo1
+        volume:
+          volumeClaimSpec:
+            resources:
+              requests:
+                storage: 1Gi
+      - name: repo2
+        s3:
+          bucket: hippo-backups
+          endpoint: minio.minio.svc:9000
+          region: us-east-1
+      - name: repo3
+        gcs:
+          bucket: hippo-gcs
+      - name: repo4
+        azure:
+          container: hippo-azure
+`), &cluster.Object))
+
+	var buf bytes.Buffer
+	assert.NilError(t, printRepoRows(&buf, repoRows(cluster), false))
+	assert.Equal(t, buf.String(), ``+
+		"REPO    TYPE     LOCATION                                    RETENTION FULL   RETENTION DIFF\n"+
+		"repo1   volume   1Gi                                         2 (count)        -\n"+
+		"repo2   s3       s3://hippo-backups (minio.minio.svc:9000)   14 (time)        7\n"+
+		"repo3   gcs      gs://hippo-gcs                              -                -\n"+
+		"repo4   azure    azure://hippo-azure                         -                -\n")
+
+	buf.Reset()
+	assert.NilError(t, printRepoRows(&buf, nil, true))
+	assert.Equal(t, buf.String(), "[]\n")
+}
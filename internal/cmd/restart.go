@@ -0,0 +1,368 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newRestartCommand returns the restart command of the PGO plugin. It restarts
+// the Pods of a PostgresCluster or, when possible, Postgres in place.
+func newRestartCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart CLUSTER_NAME",
+		Short: "Restart the Pods or Postgres of a PostgresCluster",
+		Long: `Restart a PostgresCluster after changing settings that require a restart.
+
+When Patroni reports Postgres parameters that are pending a restart, and no
+other flags are passed, Patroni restarts Postgres on those instances without
+restarting their Pods. Otherwise, the restart annotation is set on the Pods of
+the PostgresCluster and PGO replaces them one at a time, replicas first.
+
+Use --instance-set, --pgbouncer, or --repo-host to restart only those Pods.
+Use --wait to report each Pod as it becomes ready after its restart.
+Overwriting an annotation set by another client may require the
+--force-conflicts flag.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Restart the 'hippo' postgrescluster
+pgo restart hippo
+
+# Restart the Pods of instance set 'instance1' and wait for them to be ready
+pgo restart hippo --instance-set instance1 --wait
+
+# Restart the PgBouncer Pods of the 'hippo' postgrescluster
+pgo restart hippo --pgbouncer
+
+### Example output
+postgresclusters/hippo restart initiated
+pod/hippo-instance1-8gjt-0 restarted
+pod/hippo-instance1-x7zq-0 restarted
+postgresclusters/hippo restart complete`)
+
+	restart := pgRestart{Config: config}
+
+	cmd.Flags().StringVar(&restart.InstanceSet, "instance-set", "", "restart the Pods of this instance set")
+	cmd.Flags().BoolVar(&restart.PGBouncer, "pgbouncer", false, "restart the PgBouncer Pods")
+	cmd.Flags().BoolVar(&restart.RepoHost, "repo-host", false, "restart the pgBackRest repo host Pod")
+	cmd.Flags().BoolVar(&restart.Rolling, "rolling", false,
+		"restart Pods even when Patroni can restart Postgres in place")
+	cmd.Flags().BoolVar(&restart.Wait, "wait", false, "wait for the restarted Pods to be ready")
+	cmd.Flags().DurationVar(&restart.Timeout, "timeout", 10*time.Minute, "how long to wait for the restarted Pods")
+	cmd.Flags().BoolVar(&restart.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the restart annotation")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		restart.ClusterName = args[0]
+		return restart.Run(context.Background())
+	}
+
+	return cmd
+}
+
+type pgRestart struct {
+	*internal.Config
+
+	ClusterName    string
+	ForceConflicts bool
+	InstanceSet    string
+	PGBouncer      bool
+	RepoHost       bool
+	Rolling        bool
+	Timeout        time.Duration
+	Wait           bool
+
+	pollInterval time.Duration
+}
+
+// targeted returns whether or not only some of the Pods should be restarted.
+func (restart pgRestart) targeted() bool {
+	return restart.InstanceSet != "" || restart.PGBouncer || restart.RepoHost
+}
+
+// selectors returns label selectors for the Pods to be restarted.
+func (restart pgRestart) selectors() []string {
+	cluster := util.LabelCluster + "=" + restart.ClusterName
+	if !restart.targeted() {
+		return []string{cluster}
+	}
+
+	var selectors []string
+	if restart.InstanceSet != "" {
+		selectors = append(selectors, cluster+","+util.LabelInstanceSet+"="+restart.InstanceSet)
+	}
+	if restart.PGBouncer {
+		selectors = append(selectors, util.PGBouncerLabels(restart.ClusterName))
+	}
+	if restart.RepoHost {
+		selectors = append(selectors, util.RepoHostInstanceLabels(restart.ClusterName))
+	}
+	return selectors
+}
+
+// modifyIntent sets the restart annotation to value in the Pod metadata of
+// intent. It returns an error when cluster does not have the Pods to restart.
+func (restart pgRestart) modifyIntent(
+	intent, cluster *unstructured.Unstructured, value string,
+) error {
+	annotate := func(object map[string]any, fields ...string) error {
+		return unstructured.SetNestedField(object, value,
+			append(fields, "metadata", "annotations", util.RestartAnnotation())...)
+	}
+
+	if !restart.targeted() {
+		return annotate(intent.Object, "spec")
+	}
+
+	if name := restart.InstanceSet; name != "" {
+		sets, _, _ := unstructured.NestedSlice(cluster.Object, "spec", "instances")
+		if !slices.ContainsFunc(sets, func(set any) bool {
+			return set.(map[string]any)["name"] == name
+		}) {
+			return fmt.Errorf("instance set %q not found in postgrescluster %q", name, restart.ClusterName)
+		}
+
+		// Instance sets are a list keyed by name. Add the set to the intent
+		// when another client manages it.
+		sets, _, _ = unstructured.NestedSlice(intent.Object, "spec", "instances")
+		index := slices.IndexFunc(sets, func(set any) bool {
+			return set.(map[string]any)["name"] == name
+		})
+		if index < 0 {
+			sets = append(sets, map[string]any{"name": name})
+			index = len(sets) - 1
+		}
+		if err := annotate(sets[index].(map[string]any)); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedSlice(intent.Object, sets, "spec", "instances"); err != nil {
+			return err
+		}
+	}
+
+	if restart.PGBouncer {
+		if _, found, _ := unstructured.NestedMap(cluster.Object, "spec", "proxy", "pgBouncer"); !found {
+			return fmt.Errorf("postgrescluster %q does not have PgBouncer", restart.ClusterName)
+		}
+		if err := annotate(intent.Object, "spec", "proxy", "pgBouncer"); err != nil {
+			return err
+		}
+	}
+
+	if restart.RepoHost {
+		if err := annotate(intent.Object, "spec", "backups", "pgbackrest"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pendingRestart returns the members of Patroni "list" JSON output that have
+// parameters pending a restart.
+func pendingRestart(list string) ([]string, error) {
+	var members []map[string]any
+	if err := json.Unmarshal([]byte(list), &members); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, member := range members {
+		if value, _ := member["Pending restart"].(string); value != "" {
+			name, _ := member["Member"].(string)
+			pending = append(pending, name)
+		}
+	}
+	return pending, nil
+}
+
+// restartProgress returns the names of pods that have been restarted with
+// the restart annotation value and are ready. It returns true when every Pod
+// of a StatefulSet or ReplicaSet in pods is one of them.
+func restartProgress(pods []corev1.Pod, value string) ([]string, bool) {
+	var restarted []string
+	var waiting int
+
+	for _, pod := range pods {
+		// Ignore the Pods of Jobs, which do not restart, and Pods that are
+		// going away.
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || (owner.Kind != "StatefulSet" && owner.Kind != "ReplicaSet") ||
+			pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		ready := slices.ContainsFunc(pod.Status.Conditions, func(c corev1.PodCondition) bool {
+			return c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue
+		})
+		if ready && pod.Annotations[util.RestartAnnotation()] == value {
+			restarted = append(restarted, pod.Name)
+		} else {
+			waiting++
+		}
+	}
+
+	return restarted, waiting == 0 && len(restarted) > 0
+}
+
+func (restart pgRestart) Run(ctx context.Context) error {
+	mapping, clusterClient, err := v1beta1.NewPostgresClusterClient(restart)
+	if err != nil {
+		return err
+	}
+	namespace, err := restart.Namespace()
+	if err != nil {
+		return err
+	}
+	rest, err := restart.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	core, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+
+	// Fetch the cluster to (1) see if it exists and (2) extract CLI managed fields.
+	cluster, err := clusterClient.Namespace(namespace).Get(ctx, restart.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	// Let Patroni restart Postgres when only parameters are pending a restart.
+	if !restart.targeted() && !restart.Rolling {
+		restarted, err := restart.restartPending()
+		if err != nil || restarted {
+			return err
+		}
+	}
+
+	value := time.Now().UTC().Format(time.RFC3339)
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, restart.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := restart.modifyIntent(intent, cluster, value); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, restart.Config, clusterClient.Namespace(namespace), restart.ClusterName,
+		intent, restart.ForceConflicts); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(restart.Out, "%s/%s restart initiated\n",
+		mapping.Resource.Resource, restart.ClusterName)
+
+	if !restart.Wait {
+		return nil
+	}
+	if err := restart.wait(ctx, core.Pods(namespace), value); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(restart.Out, "%s/%s restart complete\n",
+		mapping.Resource.Resource, restart.ClusterName)
+	return nil
+}
+
+// restartPending uses Patroni to restart the instances that have parameters
+// pending a restart. It returns false when there are none.
+func (restart pgRestart) restartPending() (bool, error) {
+	exec, err := getPrimaryExec(restart.Config, []string{restart.ClusterName})
+	if err != nil {
+		return false, err
+	}
+
+	stdout, stderr, err := Executor(exec).patronictl("list", "json")
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+
+	pending, err := pendingRestart(stdout)
+	if err != nil || len(pending) == 0 {
+		return false, err
+	}
+
+	_, _ = fmt.Fprintf(restart.Out, "Restarting Postgres with pending changes on %s\n",
+		strings.Join(pending, ", "))
+
+	// The Patroni scope of a PostgresCluster is its name with "-ha" at the end.
+	stdout, stderr, err = Executor(exec).patronictl(
+		"restart "+restart.ClusterName+"-ha --pending --force", "")
+	_, _ = fmt.Fprint(restart.Out, stdout)
+	if err != nil {
+		return true, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	return true, nil
+}
+
+// wait polls the Pods to be restarted until they are all ready with the
+// restart annotation value or the timeout expires.
+func (restart pgRestart) wait(ctx context.Context, pods corev1client.PodInterface, value string) error {
+	interval := restart.pollInterval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+
+	reported := map[string]bool{}
+
+	ctx, cancel := context.WithTimeout(ctx, restart.Timeout)
+	defer cancel()
+
+	err := wait.PollUntilWithContext(ctx, interval, func(ctx context.Context) (bool, error) {
+		var items []corev1.Pod
+		for _, selector := range restart.selectors() {
+			list, err := pods.List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				return false, err
+			}
+			items = append(items, list.Items...)
+		}
+
+		restarted, done := restartProgress(items, value)
+		for _, name := range restarted {
+			if !reported[name] {
+				reported[name] = true
+				_, _ = fmt.Fprintf(restart.Out, "pod/%s restarted\n", name)
+			}
+		}
+		return done, nil
+	})
+
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return fmt.Errorf("timed out after %v waiting for Pods of postgrescluster %q to restart",
+			restart.Timeout, restart.ClusterName)
+	}
+	return err
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestPGRestartModifyIntent(t *testing.T) {
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  instances:
+  - name: instance1
+    replicas: 2
+  - name: instance2
+`), &cluster.Object))
+
+	t.Run("Cluster", func(t *testing.T) {
+		intent := &unstructured.Unstructured{Object: map[string]any{}}
+		assert.NilError(t, pgRestart{}.modifyIntent(intent, cluster, "now"))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  metadata:
+    annotations:
+      postgres-operator.crunchydata.com/restart: now
+		`))
+	})
+
+	t.Run("InstanceSet", func(t *testing.T) {
+		intent := &unstructured.Unstructured{}
+		intent.Object = map[string]any{"spec": map[string]any{
+			"instances": []any{map[string]any{"name": "instance1", "replicas": int64(2)}},
+		}}
+
+		restart := pgRestart{InstanceSet: "instance2"}
+		assert.NilError(t, restart.modifyIntent(intent, cluster, "now"))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  instances:
+  - name: instance1
+    replicas: 2
+  - metadata:
+      annotations:
+        postgres-operator.crunchydata.com/restart: now
+    name: instance2
+		`))
+
+		restart.InstanceSet = "missing"
+		assert.ErrorContains(t, restart.modifyIntent(intent, cluster, "now"), `"missing" not found`)
+	})
+
+	t.Run("PgBouncer", func(t *testing.T) {
+		restart := pgRestart{ClusterName: "hippo", PGBouncer: true, RepoHost: true}
+		err := restart.modifyIntent(&unstructured.Unstructured{Object: map[string]any{}}, cluster, "now")
+		assert.ErrorContains(t, err, `"hippo" does not have PgBouncer`)
+
+		assert.NilError(t, unstructured.SetNestedMap(cluster.Object, map[string]any{}, "spec", "proxy", "pgBouncer"))
+
+		intent := &unstructured.Unstructured{Object: map[string]any{}}
+		assert.NilError(t, restart.modifyIntent(intent, cluster, "now"))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      metadata:
+        annotations:
+          postgres-operator.crunchydata.com/restart: now
+  proxy:
+    pgBouncer:
+      metadata:
+        annotations:
+          postgres-operator.crunchydata.com/restart: now
+		`))
+
+		assert.DeepEqual(t, restart.selectors(), []string{
+			"postgres-operator.crunchydata.com/cluster=hippo,postgres-operator.crunchydata.com/role=pgbouncer",
+			"postgres-operator.crunchydata.com/cluster=hippo,postgres-operator.crunchydata.com/pgbackrest-dedicated=",
+		})
+	})
+}
+
+func TestPendingRestart(t *testing.T) {
+	pending, err := pendingRestart(`[
+  {"Cluster": "hippo-ha", "Member": "hippo-instance1-8gjt-0", "Role": "Leader", "State": "running", "Pending restart": "*"},
+  {"Cluster": "hippo-ha", "Member": "hippo-instance1-x7zq-0", "Role": "Replica", "State": "streaming"}
+]`)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pending, []string{"hippo-instance1-8gjt-0"})
+
+	pending, err = pendingRestart(`[]`)
+	assert.NilError(t, err)
+	assert.Equal(t, len(pending), 0)
+
+	_, err = pendingRestart(`+ Cluster: hippo-ha`)
+	assert.ErrorContains(t, err, "invalid")
+}
+
+func TestRestartProgress(t *testing.T) {
+	pod := func(name, kind, value string, ready bool) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{"postgres-operator.crunchydata.com/restart": value},
+		}}
+		if kind != "" {
+			controller := true
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Controller: &controller}}
+		}
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+		return pod
+	}
+
+	pods := []corev1.Pod{
+		pod("hippo-instance1-abcd-0", "StatefulSet", "now", true),
+		pod("hippo-instance1-efgh-0", "StatefulSet", "before", true),
+		pod("hippo-pgbouncer-1234", "ReplicaSet", "now", false),
+		pod("hippo-backup-xyz", "Job", "", false),
+		pod("standalone", "", "", false),
+	}
+
+	restarted, done := restartProgress(pods, "now")
+	assert.DeepEqual(t, restarted, []string{"hippo-instance1-abcd-0"})
+	assert.Assert(t, !done)
+
+	pods[1] = pod("hippo-instance1-efgh-0", "StatefulSet", "now", true)
+	pods[2] = pod("hippo-pgbouncer-1234", "ReplicaSet", "now", true)
+
+	restarted, done = restartProgress(pods, "now")
+	assert.Equal(t, len(restarted), 3)
+	assert.Assert(t, done)
+
+	_, done = restartProgress(nil, "now")
+	assert.Assert(t, !done, "expected no Pods to not be done")
+}
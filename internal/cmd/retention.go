@@ -0,0 +1,479 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// maxRetention is the largest value of the pgBackRest retention options.
+// - https://pgbackrest.org/configuration.html#section-repository/option-repo-retention-full
+const maxRetention = 9999999
+
+// newBackupRetentionCommand returns the retention subcommand of the backup
+// command. Subcommands of retention manage how long backups are kept.
+func newBackupRetentionCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Manage the backup retention of a PostgresCluster",
+		Long: `Manage the pgBackRest retention options in "spec.backups.pgbackrest.global" of a
+PostgresCluster. pgBackRest expires backup sets and WAL that are outside the
+retention after each backup.
+
+Changes are sent using server-side apply. Overwriting options set by another
+client may require the --force-conflicts flag.`,
+	}
+
+	cmd.AddCommand(
+		newBackupRetentionSetCommand(config),
+		newBackupRetentionShowCommand(config),
+	)
+
+	return cmd
+}
+
+func newBackupRetentionSetCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set CLUSTER_NAME --repo REPO_NAME",
+		Short: "Set the backup retention of a repository",
+		Long: `Set the backup retention of a repository of a PostgresCluster. Only the options
+of flags passed on the command line are changed.
+
+With --type count, --full is the number of full backups to keep. With
+--type time, --full is the number of days to keep full backups. --diff is the
+number of differential backups to keep.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Keep full backups of repo1 for 14 days
+pgo backup retention set hippo --repo 1 --full 14 --type time
+
+### Example output
+postgresclusters/hippo repo1 retention updated`)
+
+	retention := pgBackRestRetention{Config: config}
+
+	cmd.Flags().StringVar(&retention.RepoName, "repo", "", "the repository, such as 1 or repo1 (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("repo"))
+
+	cmd.Flags().BoolVar(&retention.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the retention options")
+	cmd.Flags().Int("full", 0, "the number of full backups, or days with --type time, to keep")
+	cmd.Flags().String("type", "", "how to count --full: count or time")
+	cmd.Flags().Int("diff", 0, "the number of differential backups to keep")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		retention.ClusterName = args[0]
+		retention.Options = map[string]string{}
+
+		for _, flag := range []string{"full", "type", "diff"} {
+			if cmd.Flags().Changed(flag) {
+				retention.Options[flag] = cmd.Flags().Lookup(flag).Value.String()
+			}
+		}
+		if len(retention.Options) == 0 {
+			return fmt.Errorf("at least one of --full, --type, or --diff is required")
+		}
+
+		return retention.Set(context.Background())
+	}
+
+	return cmd
+}
+
+func newBackupRetentionShowCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show CLUSTER_NAME",
+		Short: "Show what the backup retention keeps and expires",
+		Long: `Show the backup retention of each repository of a PostgresCluster and which
+backup sets and WAL it keeps or expires the next time pgBackRest expires backups.
+
+pgBackRest expires backups after each backup, so the backup that triggers it
+also counts toward the retention. This command evaluates the retention against
+the backup sets that exist now.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Show the backup retention of the 'hippo' postgrescluster
+pgo backup retention show hippo
+
+### Example output
+repo1: full=1 (count) diff=unset
+ACTION   LABEL                               TYPE   STOP
+expire   20250223-010002F                    full   2025-02-23T01:04:02Z
+expire   20250223-010002F_20250224-010002I   incr   2025-02-24T01:00:38Z
+keep     20250302-010002F                    full   2025-03-02T01:04:10Z
+repo1: keep WAL from 000000010000000000000010 to 000000010000000000000031; expire WAL before 000000010000000000000010`)
+
+	retention := pgBackRestRetention{Config: config}
+
+	cmd.Flags().StringVar(&retention.RepoName, "repo", "", "only show this repository, such as 1 or repo1")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		retention.ClusterName = args[0]
+		return retention.Show(context.Background(), time.Now())
+	}
+
+	return cmd
+}
+
+type pgBackRestRetention struct {
+	*internal.Config
+
+	ClusterName    string
+	ForceConflicts bool
+	RepoName       string
+
+	// Options are the values of the "full", "type", and "diff" flags that
+	// were passed on the command line.
+	Options map[string]string
+}
+
+// retentionPolicy is the retention of one repository. Zero values are unset.
+type retentionPolicy struct {
+	Full     int
+	FullType string
+	Diff     int
+}
+
+// retentionOptions maps flags to the pgBackRest options they set, without
+// the "repoN-" prefix.
+var retentionOptions = map[string]string{
+	"full": "retention-full",
+	"type": "retention-full-type",
+	"diff": "retention-diff",
+}
+
+// repoName returns the name of the repository, such as "repo1", whether it
+// was passed as a name or a number.
+func (retention pgBackRestRetention) repoName() string {
+	return "repo" + strings.TrimPrefix(retention.RepoName, "repo")
+}
+
+// validate checks the values of Options.
+func (retention pgBackRestRetention) validate() error {
+	if !repoNamePattern.MatchString(retention.repoName()) {
+		return fmt.Errorf("invalid repository %q: expected 1, 2, 3, or 4", retention.RepoName)
+	}
+	for _, flag := range []string{"full", "diff"} {
+		if value, ok := retention.Options[flag]; ok {
+			if n, err := strconv.Atoi(value); err != nil || n < 1 || n > maxRetention {
+				return fmt.Errorf("invalid --%s %q: expected a number between 1 and %d", flag, value, maxRetention)
+			}
+		}
+	}
+	if value, ok := retention.Options["type"]; ok && value != "count" && value != "time" {
+		return fmt.Errorf("invalid --type %q: expected count or time", value)
+	}
+	return nil
+}
+
+// setIntent sets the retention options of the repository in intent.
+func (retention pgBackRestRetention) setIntent(intent *unstructured.Unstructured) error {
+	path := []string{"spec", "backups", "pgbackrest", "global"}
+	global, _, _ := unstructured.NestedStringMap(intent.Object, path...)
+	if global == nil {
+		global = map[string]string{}
+	}
+	for flag, value := range retention.Options {
+		global[retention.repoName()+"-"+retentionOptions[flag]] = value
+	}
+	return unstructured.SetNestedStringMap(intent.Object, global, path...)
+}
+
+// clusterRetention returns the retention of repo in the spec of cluster.
+func clusterRetention(cluster *unstructured.Unstructured, repo string) (retentionPolicy, error) {
+	global, _, _ := unstructured.NestedStringMap(cluster.Object, "spec", "backups", "pgbackrest", "global")
+
+	policy := retentionPolicy{FullType: global[repo+"-retention-full-type"]}
+	for option, value := range map[string]*int{
+		"retention-full": &policy.Full,
+		"retention-diff": &policy.Diff,
+	} {
+		if text, ok := global[repo+"-"+option]; ok {
+			n, err := strconv.Atoi(text)
+			if err != nil || n < 1 {
+				return policy, fmt.Errorf("invalid %s-%s %q", repo, option, text)
+			}
+			*value = n
+		}
+	}
+	if policy.FullType == "" {
+		policy.FullType = "count"
+	}
+	return policy, nil
+}
+
+func (policy retentionPolicy) String() string {
+	full, diff := "unset", "unset"
+	if policy.Full > 0 {
+		full = fmt.Sprintf("%d (%s)", policy.Full, policy.FullType)
+	}
+	if policy.Diff > 0 {
+		diff = strconv.Itoa(policy.Diff)
+	}
+	return "full=" + full + " diff=" + diff
+}
+
+// retentionPlan is what a retention policy keeps and expires in a repository.
+type retentionPlan struct {
+	Keep, Expire []backupSet
+
+	// WALStart is the oldest WAL that is kept, and WALStop is the newest.
+	WALStart, WALStop string
+}
+
+// evaluate returns what policy keeps and expires of the backup sets in
+// repository repoKey at now. Backup sets are ordered oldest first.
+func (policy retentionPolicy) evaluate(backups []backupSet, archives []archiveRange, repoKey int, now time.Time) retentionPlan {
+	var fulls, diffs []backupSet
+	for _, backup := range backups {
+		if backup.Database.RepoKey != repoKey {
+			continue
+		}
+		switch backup.Type {
+		case "full":
+			fulls = append(fulls, backup)
+		case "diff":
+			diffs = append(diffs, backup)
+		}
+	}
+
+	expired := map[string]bool{}
+	expire := func(backup backupSet) {
+		expired[backup.Label] = true
+		for _, dependent := range dependentBackups(backups, repoKey, backup.Label) {
+			expired[dependent.Label] = true
+		}
+	}
+
+	// Full backups beyond the retention expire along with their dependents.
+	keepFrom := 0
+	switch {
+	case policy.Full == 0:
+	case policy.FullType == "time":
+		// Keep the newest full backup older than the retention period so that
+		// recovery to the start of the period is possible.
+		cutoff := now.AddDate(0, 0, -policy.Full).Unix()
+		for i, full := range fulls {
+			if full.Timestamp.Stop <= cutoff {
+				keepFrom = i
+			}
+		}
+	default:
+		keepFrom = max(0, len(fulls)-policy.Full)
+	}
+	for _, full := range fulls[:keepFrom] {
+		expire(full)
+	}
+
+	// Differential backups beyond the retention expire along with their
+	// dependents.
+	if policy.Diff > 0 {
+		var kept []backupSet
+		for _, diff := range diffs {
+			if !expired[diff.Label] {
+				kept = append(kept, diff)
+			}
+		}
+		for _, diff := range kept[:max(0, len(kept)-policy.Diff)] {
+			expire(diff)
+		}
+	}
+
+	var plan retentionPlan
+	for _, backup := range backups {
+		if backup.Database.RepoKey != repoKey {
+			continue
+		}
+		if expired[backup.Label] {
+			plan.Expire = append(plan.Expire, backup)
+			continue
+		}
+		plan.Keep = append(plan.Keep, backup)
+		if plan.WALStart == "" || walSegment(backup.Archive.Start) < walSegment(plan.WALStart) {
+			plan.WALStart = backup.Archive.Start
+		}
+	}
+	for _, archive := range archives {
+		if archive.Database.RepoKey == repoKey && walSegment(archive.Max) > walSegment(plan.WALStop) {
+			plan.WALStop = archive.Max
+		}
+	}
+	return plan
+}
+
+func printRetentionPlan(w io.Writer, plan retentionPlan) error {
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "ACTION\tLABEL\tTYPE\tSTOP\n"); err != nil {
+		return err
+	}
+
+	var rows []backupSet
+	rows = append(rows, plan.Expire...)
+	rows = append(rows, plan.Keep...)
+	slices.SortStableFunc(rows, func(a, b backupSet) int {
+		return cmp.Compare(a.Timestamp.Stop, b.Timestamp.Stop)
+	})
+
+	for _, backup := range rows {
+		action := "keep"
+		if slices.ContainsFunc(plan.Expire, func(e backupSet) bool { return e.Label == backup.Label }) {
+			action = "expire"
+		}
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\n",
+			action, backup.Label, backup.Type, formatUnix(backup.Timestamp.Stop),
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// walMessage describes the WAL that plan keeps and expires in repo.
+func (plan retentionPlan) walMessage(repo string) string {
+	if plan.WALStart == "" || plan.WALStop == "" {
+		return repo + ": no WAL is archived for the backup sets that are kept"
+	}
+	return fmt.Sprintf("%s: keep WAL from %s to %s; expire WAL before %s",
+		repo, plan.WALStart, plan.WALStop, plan.WALStart)
+}
+
+// Set validates Options and sets them on the repository.
+func (retention pgBackRestRetention) Set(ctx context.Context) error {
+	if err := retention.validate(); err != nil {
+		return err
+	}
+
+	mapping, client, err := v1beta1.NewPostgresClusterClient(retention)
+	if err != nil {
+		return err
+	}
+	namespace, err := retention.Namespace()
+	if err != nil {
+		return err
+	}
+
+	// Fetch the cluster to (1) see if the repository exists and (2) extract CLI managed fields.
+	cluster, err := client.Namespace(namespace).Get(ctx, retention.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(clusterRepos(cluster), func(repo map[string]any) bool {
+		return repo["name"] == retention.repoName()
+	}) {
+		return fmt.Errorf("repository %q not found in %s/%s",
+			retention.repoName(), mapping.Resource.Resource, retention.ClusterName)
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, retention.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := retention.setIntent(intent); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, retention.Config, client.Namespace(namespace), retention.ClusterName,
+		intent, retention.ForceConflicts); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(retention.Out, "%s/%s %s retention updated\n",
+		mapping.Resource.Resource, retention.ClusterName, retention.repoName())
+	return nil
+}
+
+// Show prints the retention of each repository and what it keeps and expires.
+func (retention pgBackRestRetention) Show(ctx context.Context, now time.Time) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(retention)
+	if err != nil {
+		return err
+	}
+	namespace, err := retention.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, retention.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	backups, archives, err := pgBackRestBackups{
+		Config: retention.Config, ClusterName: retention.ClusterName,
+	}.info()
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range clusterRepos(cluster) {
+		name, _ := repo["name"].(string)
+		if retention.RepoName != "" && name != retention.repoName() {
+			continue
+		}
+		key, err := strconv.Atoi(strings.TrimPrefix(name, "repo"))
+		if err != nil {
+			continue
+		}
+
+		policy, err := clusterRetention(cluster, name)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(retention.Out, "%s: %s\n", name, policy)
+		if policy.Full == 0 {
+			_, _ = fmt.Fprintf(retention.Out, "%s: %s-retention-full is not set, so no backup sets expire\n", name, name)
+			continue
+		}
+
+		plan := policy.evaluate(backups, archives, key, now)
+		if err := printRetentionPlan(retention.Out, plan); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(retention.Out, plan.walMessage(name))
+	}
+	return nil
+}
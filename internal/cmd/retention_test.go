@@ -0,0 +1,167 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestPGBackRestRetentionIntent(t *testing.T) {
+	retention := pgBackRestRetention{RepoName: "1", Options: map[string]string{
+		"full": "14", "type": "time",
+	}}
+	assert.NilError(t, retention.validate())
+
+	intent := &unstructured.Unstructured{Object: map[string]any{}}
+	assert.NilError(t, retention.setIntent(intent))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      global:
+        repo1-retention-full: "14"
+        repo1-retention-full-type: time
+	`))
+
+	retention = pgBackRestRetention{RepoName: "repo1", Options: map[string]string{"diff": "3"}}
+	assert.NilError(t, retention.setIntent(intent))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      global:
+        repo1-retention-diff: "3"
+        repo1-retention-full: "14"
+        repo1-retention-full-type: time
+	`))
+
+	for _, tt := range []struct {
+		Retention pgBackRestRetention
+		Error     string
+	}{
+		{Retention: pgBackRestRetention{RepoName: "5"}, Error: `invalid repository "5"`},
+		{Retention: pgBackRestRetention{RepoName: "1", Options: map[string]string{"full": "0"}},
+			Error: `invalid --full "0": expected a number between 1 and 9999999`},
+		{Retention: pgBackRestRetention{RepoName: "1", Options: map[string]string{"diff": "10000000"}},
+			Error: `invalid --diff "10000000"`},
+		{Retention: pgBackRestRetention{RepoName: "1", Options: map[string]string{"type": "days"}},
+			Error: `invalid --type "days": expected count or time`},
+	} {
+		assert.ErrorContains(t, tt.Retention.validate(), tt.Error)
+	}
+}
+
+func TestClusterRetention(t *testing.T) {
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  backups:
+    pgbackrest:
+      global:
+        repo1-retention-full: "2"
+        repo2-retention-full: "14"
+        repo2-retention-full-type: time
+        repo2-retention-diff: "1"
+        repo3-retention-full: "many"
+`), &cluster.Object))
+
+	policy, err := clusterRetention(cluster, "repo1")
+	assert.NilError(t, err)
+	assert.Equal(t, policy, retentionPolicy{Full: 2, FullType: "count"})
+	assert.Equal(t, policy.String(), "full=2 (count) diff=unset")
+
+	policy, err = clusterRetention(cluster, "repo2")
+	assert.NilError(t, err)
+	assert.Equal(t, policy.String(), "full=14 (time) diff=1")
+
+	_, err = clusterRetention(cluster, "repo3")
+	assert.ErrorContains(t, err, `invalid repo3-retention-full "many"`)
+
+	policy, err = clusterRetention(cluster, "repo4")
+	assert.NilError(t, err)
+	assert.Equal(t, policy.String(), "full=unset diff=unset")
+}
+
+func TestRetentionPlan(t *testing.T) {
+	backups, archives, err := parseBackupInfo(backupInfoJSON)
+	assert.NilError(t, err)
+
+	now := time.Date(2025, 3, 9, 14, 30, 0, 0, time.UTC)
+	labels := func(backups []backupSet) []string {
+		var result []string
+		for _, backup := range backups {
+			result = append(result, backup.Label)
+		}
+		return result
+	}
+
+	t.Run("Count", func(t *testing.T) {
+		plan := retentionPolicy{Full: 1, FullType: "count"}.evaluate(backups, archives, 1, now)
+		assert.DeepEqual(t, labels(plan.Expire), []string{
+			"20250223-010002F",
+			"20250223-010002F_20250224-010002I",
+		})
+		assert.Equal(t, len(plan.Keep), 3)
+		assert.Equal(t, plan.walMessage("repo1"), "repo1: keep WAL from 000000010000000000000010"+
+			" to 000000010000000000000031; expire WAL before 000000010000000000000010")
+
+		var buf bytes.Buffer
+		assert.NilError(t, printRetentionPlan(&buf, plan))
+		assert.Equal(t, buf.String(), ``+
+			"ACTION   LABEL                               TYPE   STOP\n"+
+			"expire   20250223-010002F                    full   2025-02-23T01:04:02Z\n"+
+			"expire   20250223-010002F_20250224-010002I   incr   2025-02-24T01:00:38Z\n"+
+			"keep     20250302-010002F                    full   2025-03-02T01:04:10Z\n"+
+			"keep     20250302-010002F_20250305-010002D   diff   2025-03-05T01:00:40Z\n"+
+			"keep     20250302-010002F_20250309-010003I   incr   2025-03-09T01:00:41Z\n")
+
+		plan = retentionPolicy{Full: 2, FullType: "count"}.evaluate(backups, archives, 1, now)
+		assert.Assert(t, plan.Expire == nil)
+		assert.Equal(t, plan.WALStart, "000000010000000000000002")
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		// The newest full backup older than 3 days is kept.
+		plan := retentionPolicy{Full: 3, FullType: "time"}.evaluate(backups, archives, 1, now)
+		assert.DeepEqual(t, labels(plan.Expire), []string{
+			"20250223-010002F",
+			"20250223-010002F_20250224-010002I",
+		})
+
+		plan = retentionPolicy{Full: 14, FullType: "time"}.evaluate(backups, archives, 1, now)
+		assert.Assert(t, plan.Expire == nil)
+
+		plan = retentionPolicy{Full: 30, FullType: "time"}.evaluate(backups, archives, 1, now)
+		assert.Assert(t, plan.Expire == nil)
+	})
+
+	t.Run("Differential", func(t *testing.T) {
+		// Pretend there are two differential backups.
+		backups := append([]backupSet(nil), backups...)
+		backups[1].Type = "diff"
+
+		plan := retentionPolicy{Full: 2, FullType: "count", Diff: 1}.evaluate(backups, archives, 1, now)
+		assert.DeepEqual(t, labels(plan.Expire), []string{
+			"20250223-010002F_20250224-010002I",
+		})
+	})
+
+	t.Run("Repository", func(t *testing.T) {
+		plan := retentionPolicy{Full: 1, FullType: "count"}.evaluate(backups, archives, 2, now)
+		assert.Assert(t, plan.Expire == nil)
+		assert.DeepEqual(t, labels(plan.Keep), []string{"20250302-020002F"})
+
+		plan = retentionPolicy{Full: 1, FullType: "count"}.evaluate(backups, archives, 3, now)
+		assert.Equal(t, plan.walMessage("repo3"), "repo3: no WAL is archived for the backup sets that are kept")
+	})
+}
@@ -0,0 +1,578 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// backupTypes are the kinds of scheduled pgBackRest backups. Field is the
+// name in "spec.backups.pgbackrest.repos[].schedules" and Label is the value
+// of the CronJob label.
+var backupTypes = []struct {
+	Field, Label, Flag string
+}{
+	{Field: "full", Label: "full", Flag: "full"},
+	{Field: "differential", Label: "diff", Flag: "diff"},
+	{Field: "incremental", Label: "incr", Flag: "incr"},
+}
+
+// newBackupScheduleCommand returns the schedule subcommand of the backup
+// command. Subcommands of schedule manage the backup schedules of repositories.
+func newBackupScheduleCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Manage the backup schedules of a PostgresCluster",
+		Long: `Manage the pgBackRest backup schedules in "spec.backups.pgbackrest.repos[].schedules"
+of a PostgresCluster. PGO runs each schedule with a CronJob.
+
+Schedules are cron expressions with five fields: minute, hour, day of month,
+month, and day of week. Changes are sent using server-side apply, so only the
+schedules set by this command can be cleared by it. Overwriting schedules set
+by another client may require the --force-conflicts flag.`,
+	}
+
+	cmd.AddCommand(
+		newBackupScheduleListCommand(config),
+		newBackupScheduleSetCommand(config),
+		newBackupScheduleClearCommand(config),
+	)
+
+	return cmd
+}
+
+func newBackupScheduleListCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list CLUSTER_NAME",
+		Short: "List the backup schedules of a PostgresCluster",
+		Long: `List the backup schedules of a PostgresCluster with their next run time and
+the CronJob that runs them. Times are in UTC.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    cronjobs.batch                                      [list]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# List the backup schedules of the 'hippo' postgrescluster
+pgo backup schedule list hippo
+
+### Example output
+REPO    TYPE          SCHEDULE      NEXT RUN               CRONJOB            LAST SCHEDULE          STATUS
+repo1   full          0 1 * * 0     2025-03-16T01:00:00Z   hippo-repo1-full   2025-03-09T01:00:00Z   Succeeded
+repo1   incremental   0 1 * * 1-6   2025-03-10T01:00:00Z   hippo-repo1-incr   2025-03-08T01:00:00Z   Succeeded`)
+
+	schedule := pgBackRestSchedule{Config: config}
+
+	cmd.Flags().StringVar(&schedule.RepoName, "repo", "", "only list the schedules of this repository, such as repo1")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		schedule.ClusterName = args[0]
+		return schedule.List(context.Background())
+	}
+
+	return cmd
+}
+
+func newBackupScheduleSetCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set CLUSTER_NAME --repo REPO_NAME",
+		Short: "Set the backup schedules of a repository",
+		Long: `Set the backup schedules of a repository of a PostgresCluster. Only the
+schedules of flags passed on the command line are changed.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Take a full backup every Sunday and an incremental backup every other day
+pgo backup schedule set hippo --repo repo1 --full "0 1 * * 0" --incr "0 1 * * 1-6"
+
+### Example output
+postgresclusters/hippo repo1 schedules updated
+repo1 full backup next runs at 2025-03-16T01:00:00Z
+repo1 incremental backup next runs at 2025-03-10T01:00:00Z`)
+
+	schedule := pgBackRestSchedule{Config: config}
+
+	cmd.Flags().StringVar(&schedule.RepoName, "repo", "", "the repository to back up, such as repo1 (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("repo"))
+
+	cmd.Flags().BoolVar(&schedule.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the backup schedules")
+	cmd.Flags().String("full", "", "cron schedule of full backups")
+	cmd.Flags().String("diff", "", "cron schedule of differential backups")
+	cmd.Flags().String("incr", "", "cron schedule of incremental backups")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		schedule.ClusterName = args[0]
+		schedule.Schedules = map[string]string{}
+
+		for _, t := range backupTypes {
+			if cmd.Flags().Changed(t.Flag) {
+				value, err := cmd.Flags().GetString(t.Flag)
+				if err != nil {
+					return err
+				}
+				schedule.Schedules[t.Field] = value
+			}
+		}
+		if len(schedule.Schedules) == 0 {
+			return fmt.Errorf("at least one of --full, --diff, or --incr is required")
+		}
+
+		return schedule.Set(context.Background(), time.Now())
+	}
+
+	return cmd
+}
+
+func newBackupScheduleClearCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear CLUSTER_NAME --repo REPO_NAME",
+		Short: "Clear the backup schedules of a repository",
+		Long: `Clear the backup schedules of a repository of a PostgresCluster. Without
+--full, --diff, or --incr, every schedule set by this command is cleared.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Stop taking incremental backups to repo1
+pgo backup schedule clear hippo --repo repo1 --incr
+
+### Example output
+postgresclusters/hippo repo1 schedules cleared`)
+
+	schedule := pgBackRestSchedule{Config: config}
+
+	cmd.Flags().StringVar(&schedule.RepoName, "repo", "", "the repository of the schedules, such as repo1 (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("repo"))
+
+	cmd.Flags().BoolVar(&schedule.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the backup schedules")
+	cmd.Flags().Bool("full", false, "clear the schedule of full backups")
+	cmd.Flags().Bool("diff", false, "clear the schedule of differential backups")
+	cmd.Flags().Bool("incr", false, "clear the schedule of incremental backups")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		schedule.ClusterName = args[0]
+
+		var fields []string
+		for _, t := range backupTypes {
+			if selected, _ := cmd.Flags().GetBool(t.Flag); selected {
+				fields = append(fields, t.Field)
+			}
+		}
+		if len(fields) == 0 {
+			for _, t := range backupTypes {
+				fields = append(fields, t.Field)
+			}
+		}
+
+		return schedule.Clear(context.Background(), fields)
+	}
+
+	return cmd
+}
+
+type pgBackRestSchedule struct {
+	*internal.Config
+
+	ClusterName    string
+	ForceConflicts bool
+	RepoName       string
+
+	// Schedules are the cron expressions to set by their field name.
+	Schedules map[string]string
+}
+
+// scheduleRow is a backup schedule as printed by "pgo backup schedule list".
+type scheduleRow struct {
+	Repo, Type, Schedule, Next string
+	CronJob, LastSchedule      string
+	Status                     string
+}
+
+// clusterRepos returns the pgBackRest repositories in the spec of object.
+func clusterRepos(object *unstructured.Unstructured) []map[string]any {
+	list, _, _ := unstructured.NestedSlice(object.Object, "spec", "backups", "pgbackrest", "repos")
+
+	repos := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if repo, ok := item.(map[string]any); ok {
+			repos = append(repos, repo)
+		}
+	}
+	return repos
+}
+
+// cronJobStatus summarizes the most recent run of cronjob.
+func cronJobStatus(cronjob *batchv1.CronJob) string {
+	switch {
+	case cronjob.Spec.Suspend != nil && *cronjob.Spec.Suspend:
+		return "Suspended"
+	case len(cronjob.Status.Active) > 0:
+		return "Active"
+	case cronjob.Status.LastScheduleTime == nil:
+		return "Waiting"
+	case cronjob.Status.LastSuccessfulTime != nil &&
+		!cronjob.Status.LastSuccessfulTime.Before(cronjob.Status.LastScheduleTime):
+		return "Succeeded"
+	}
+	return "Failed"
+}
+
+// nextRun returns when expression next runs after now or why it does not.
+func nextRun(expression string, now time.Time) string {
+	schedule, err := util.ParseCronSchedule(expression)
+	if err != nil {
+		return "invalid: " + err.Error()
+	}
+	next, err := schedule.Next(now.UTC())
+	if err != nil {
+		return err.Error()
+	}
+	return next.Format(time.RFC3339)
+}
+
+// scheduleRows returns the schedules of repositories named repoName in cluster
+// along with their CronJobs. CronJobs without a schedule are included last.
+func scheduleRows(cluster *unstructured.Unstructured,
+	cronjobs []batchv1.CronJob, repoName string, now time.Time,
+) []scheduleRow {
+	var rows []scheduleRow
+	used := make([]bool, len(cronjobs))
+
+	row := func(repo, field, label, schedule string) scheduleRow {
+		r := scheduleRow{
+			Repo: repo, Type: field, Schedule: schedule,
+			Next: nextRun(schedule, now), CronJob: "<none>", LastSchedule: "<none>",
+			Status: "Pending",
+		}
+		for i := range cronjobs {
+			labels := cronjobs[i].Labels
+			if !used[i] && labels[util.LabelPGBackRestRepo] == repo &&
+				labels[util.LabelPGBackRestCronJob] == label {
+				used[i] = true
+				r.CronJob = cronjobs[i].Name
+				r.Status = cronJobStatus(&cronjobs[i])
+				if t := cronjobs[i].Status.LastScheduleTime; t != nil {
+					r.LastSchedule = t.UTC().Format(time.RFC3339)
+				}
+				break
+			}
+		}
+		return r
+	}
+
+	for _, repo := range clusterRepos(cluster) {
+		name, _ := repo["name"].(string)
+		if repoName != "" && name != repoName {
+			continue
+		}
+		for _, t := range backupTypes {
+			if schedule, _, _ := unstructured.NestedString(repo, "schedules", t.Field); schedule != "" {
+				rows = append(rows, row(name, t.Field, t.Label, schedule))
+			}
+		}
+	}
+
+	// PGO deletes the CronJob of a schedule that is no longer in the spec.
+	for i := range cronjobs {
+		labels := cronjobs[i].Labels
+		if used[i] || (repoName != "" && labels[util.LabelPGBackRestRepo] != repoName) {
+			continue
+		}
+		for _, t := range backupTypes {
+			if labels[util.LabelPGBackRestCronJob] == t.Label {
+				r := row(labels[util.LabelPGBackRestRepo], t.Field, t.Label, cronjobs[i].Spec.Schedule)
+				r.Next = "-"
+				rows = append(rows, r)
+			}
+		}
+	}
+
+	return rows
+}
+
+// printScheduleRows writes rows to w as a table.
+func printScheduleRows(w io.Writer, rows []scheduleRow) error {
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "REPO\tTYPE\tSCHEDULE\tNEXT RUN\tCRONJOB\tLAST SCHEDULE\tSTATUS\n"); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Repo, r.Type, r.Schedule, r.Next, r.CronJob, r.LastSchedule, r.Status,
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// repoIntent returns the repository named name in the intent repos and its
+// index, adding it when it is not there.
+func repoIntent(repos []any, name string) ([]any, int) {
+	index := slices.IndexFunc(repos, func(repo any) bool {
+		r, _ := repo.(map[string]any)
+		return r["name"] == name
+	})
+	if index < 0 {
+		repos = append(repos, map[string]any{"name": name})
+		index = len(repos) - 1
+	}
+	return repos, index
+}
+
+// setIntent sets the schedules of the repository in intent.
+func (schedule pgBackRestSchedule) setIntent(intent *unstructured.Unstructured) error {
+	path := []string{"spec", "backups", "pgbackrest", "repos"}
+	repos, _, _ := unstructured.NestedSlice(intent.Object, path...)
+	repos, index := repoIntent(repos, schedule.RepoName)
+
+	for field, value := range schedule.Schedules {
+		if err := unstructured.SetNestedField(repos[index].(map[string]any),
+			value, "schedules", field); err != nil {
+			return err
+		}
+	}
+	return unstructured.SetNestedSlice(intent.Object, repos, path...)
+}
+
+// clearIntent removes the schedules in fields from the repository in intent.
+// It returns false when intent has none of them.
+func (schedule pgBackRestSchedule) clearIntent(intent *unstructured.Unstructured, fields []string) bool {
+	path := []string{"spec", "backups", "pgbackrest", "repos"}
+	repos, _, _ := unstructured.NestedSlice(intent.Object, path...)
+	index := slices.IndexFunc(repos, func(repo any) bool {
+		r, _ := repo.(map[string]any)
+		return r["name"] == schedule.RepoName
+	})
+	if index < 0 {
+		return false
+	}
+
+	repo := repos[index].(map[string]any)
+	var removed bool
+	for _, field := range fields {
+		if _, found, _ := unstructured.NestedFieldNoCopy(repo, "schedules", field); found {
+			unstructured.RemoveNestedField(repo, "schedules", field)
+			removed = true
+		}
+	}
+	if schedules, _, _ := unstructured.NestedMap(repo, "schedules"); len(schedules) == 0 {
+		delete(repo, "schedules")
+	}
+
+	// A repository with only its name in the intent is no longer managed by
+	// the CLI.
+	if len(repo) == 1 {
+		repos = slices.Delete(repos, index, index+1)
+	}
+	if len(repos) == 0 {
+		unstructured.RemoveNestedField(intent.Object, path...)
+	} else {
+		_ = unstructured.SetNestedSlice(intent.Object, repos, path...)
+	}
+	internal.RemoveEmptySections(intent, "spec", "backups", "pgbackrest")
+
+	return removed
+}
+
+// clusterHasRepo returns whether or not the repository exists in cluster.
+func (schedule pgBackRestSchedule) clusterHasRepo(cluster *unstructured.Unstructured) bool {
+	return slices.ContainsFunc(clusterRepos(cluster), func(repo map[string]any) bool {
+		return repo["name"] == schedule.RepoName
+	})
+}
+
+// List prints the backup schedules of the cluster and their CronJobs.
+func (schedule pgBackRestSchedule) List(ctx context.Context) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(schedule)
+	if err != nil {
+		return err
+	}
+	namespace, err := schedule.Namespace()
+	if err != nil {
+		return err
+	}
+	rest, err := schedule.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	batch, err := batchv1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, schedule.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if schedule.RepoName != "" && !schedule.clusterHasRepo(cluster) {
+		return fmt.Errorf("repository %q not found in postgrescluster %q", schedule.RepoName, schedule.ClusterName)
+	}
+
+	cronjobs, err := batch.CronJobs(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: util.LabelCluster + "=" + schedule.ClusterName +
+			"," + util.LabelPGBackRestCronJob,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printScheduleRows(schedule.Out,
+		scheduleRows(cluster, cronjobs.Items, schedule.RepoName, time.Now()))
+}
+
+// Set validates the schedules and applies them to the repository.
+func (schedule pgBackRestSchedule) Set(ctx context.Context, now time.Time) error {
+	next := map[string]time.Time{}
+	for field, expression := range schedule.Schedules {
+		parsed, err := util.ParseCronSchedule(expression)
+		if err == nil {
+			next[field], err = parsed.Next(now.UTC())
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s schedule %q: %w", field, expression, err)
+		}
+	}
+
+	mapping, client, err := v1beta1.NewPostgresClusterClient(schedule)
+	if err != nil {
+		return err
+	}
+	namespace, err := schedule.Namespace()
+	if err != nil {
+		return err
+	}
+
+	// Fetch the cluster to (1) see if the repository exists and (2) extract CLI managed fields.
+	cluster, err := client.Namespace(namespace).Get(ctx, schedule.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !schedule.clusterHasRepo(cluster) {
+		return fmt.Errorf("repository %q not found in %s/%s",
+			schedule.RepoName, mapping.Resource.Resource, schedule.ClusterName)
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, schedule.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := schedule.setIntent(intent); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, schedule.Config, client.Namespace(namespace), schedule.ClusterName,
+		intent, schedule.ForceConflicts); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(schedule.Out, "%s/%s %s schedules updated\n",
+		mapping.Resource.Resource, schedule.ClusterName, schedule.RepoName)
+	for _, t := range backupTypes {
+		if when, ok := next[t.Field]; ok {
+			_, _ = fmt.Fprintf(schedule.Out, "%s %s backup next runs at %s\n",
+				schedule.RepoName, t.Field, when.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// Clear removes the schedules in fields from the repository.
+func (schedule pgBackRestSchedule) Clear(ctx context.Context, fields []string) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(schedule)
+	if err != nil {
+		return err
+	}
+	namespace, err := schedule.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, schedule.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !schedule.clusterHasRepo(cluster) {
+		return fmt.Errorf("repository %q not found in %s/%s",
+			schedule.RepoName, mapping.Resource.Resource, schedule.ClusterName)
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, schedule.Patch.FieldManager); err != nil {
+		return err
+	}
+	if !schedule.clearIntent(intent, fields) {
+		return fmt.Errorf("schedules of %s in %s/%s were not set by pgo; clear them with the client that set them",
+			schedule.RepoName, mapping.Resource.Resource, schedule.ClusterName)
+	}
+
+	cluster, err = applyCluster(ctx, schedule.Config, client.Namespace(namespace), schedule.ClusterName,
+		intent, schedule.ForceConflicts)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(schedule.Out, "%s/%s %s schedules cleared\n",
+		mapping.Resource.Resource, schedule.ClusterName, schedule.RepoName)
+
+	// Another client may also have set these schedules.
+	for _, repo := range clusterRepos(cluster) {
+		if repo["name"] != schedule.RepoName {
+			continue
+		}
+		for _, field := range fields {
+			if value, _, _ := unstructured.NestedString(repo, "schedules", field); value != "" {
+				_, _ = fmt.Fprintf(schedule.Out, "WARNING: %s %s schedule is still set by another client of %s/%s\n",
+					schedule.RepoName, field, mapping.Resource.Resource, schedule.ClusterName)
+			}
+		}
+	}
+	return nil
+}
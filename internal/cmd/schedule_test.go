@@ -0,0 +1,152 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestCronJobStatus(t *testing.T) {
+	at := func(hour int) *metav1.Time {
+		t := metav1.NewTime(time.Date(2025, 3, 9, hour, 0, 0, 0, time.UTC))
+		return &t
+	}
+	suspend := true
+
+	for _, tt := range []struct {
+		CronJob  batchv1.CronJob
+		Expected string
+	}{
+		{CronJob: batchv1.CronJob{Spec: batchv1.CronJobSpec{Suspend: &suspend}}, Expected: "Suspended"},
+		{CronJob: batchv1.CronJob{Status: batchv1.CronJobStatus{
+			Active: []corev1.ObjectReference{{Name: "job"}}, LastScheduleTime: at(1),
+		}}, Expected: "Active"},
+		{CronJob: batchv1.CronJob{}, Expected: "Waiting"},
+		{CronJob: batchv1.CronJob{Status: batchv1.CronJobStatus{
+			LastScheduleTime: at(1), LastSuccessfulTime: at(2),
+		}}, Expected: "Succeeded"},
+		{CronJob: batchv1.CronJob{Status: batchv1.CronJobStatus{
+			LastScheduleTime: at(3), LastSuccessfulTime: at(2),
+		}}, Expected: "Failed"},
+	} {
+		assert.Equal(t, cronJobStatus(&tt.CronJob), tt.Expected)
+	}
+}
+
+func TestScheduleRows(t *testing.T) {
+	// Sunday, March 9, 2025 at 14:30 UTC
+	now := time.Date(2025, 3, 9, 14, 30, 0, 0, time.UTC)
+
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  backups:
+    pgbackrest:
+      repos:
+      - name: repo1
+        schedules:
+          full: "0 1 * * 0"
+          incremental: "0 1 * * 1-6"
+      - name: repo2
+        schedules:
+          differential: "not a schedule"
+      - name: repo3
+`), &cluster.Object))
+
+	lastSchedule := metav1.NewTime(time.Date(2025, 3, 9, 1, 0, 0, 0, time.UTC))
+	cronjob := func(name, repo, label, schedule string) batchv1.CronJob {
+		return batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+				"postgres-operator.crunchydata.com/pgbackrest-repo":    repo,
+				"postgres-operator.crunchydata.com/pgbackrest-cronjob": label,
+			}},
+			Spec:   batchv1.CronJobSpec{Schedule: schedule},
+			Status: batchv1.CronJobStatus{LastScheduleTime: &lastSchedule, LastSuccessfulTime: &lastSchedule},
+		}
+	}
+	cronjobs := []batchv1.CronJob{
+		cronjob("hippo-repo1-full", "repo1", "full", "0 1 * * 0"),
+		cronjob("hippo-repo3-diff", "repo3", "diff", "0 2 * * *"),
+	}
+
+	var buf bytes.Buffer
+	assert.NilError(t, printScheduleRows(&buf, scheduleRows(cluster, cronjobs, "", now)))
+	assert.Equal(t, buf.String(), ``+
+		"REPO    TYPE           SCHEDULE         NEXT RUN                              CRONJOB            LAST SCHEDULE          STATUS\n"+
+		"repo1   full           0 1 * * 0        2025-03-16T01:00:00Z                  hippo-repo1-full   2025-03-09T01:00:00Z   Succeeded\n"+
+		"repo1   incremental    0 1 * * 1-6      2025-03-10T01:00:00Z                  <none>             <none>                 Pending\n"+
+		"repo2   differential   not a schedule   invalid: expected 5 fields, found 3   <none>             <none>                 Pending\n"+
+		"repo3   differential   0 2 * * *        -                                     hippo-repo3-diff   2025-03-09T01:00:00Z   Succeeded\n")
+
+	rows := scheduleRows(cluster, cronjobs, "repo1", now)
+	assert.Equal(t, len(rows), 2)
+	assert.Equal(t, rows[0].CronJob, "hippo-repo1-full")
+}
+
+func TestPGBackRestScheduleIntent(t *testing.T) {
+	intent := &unstructured.Unstructured{Object: map[string]any{}}
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  backups:
+    pgbackrest:
+      repos:
+      - name: repo2
+        schedules:
+          full: "0 3 * * *"
+`), &intent.Object))
+
+	schedule := pgBackRestSchedule{RepoName: "repo1", Schedules: map[string]string{
+		"full": "0 1 * * 0", "incremental": "0 1 * * 1-6",
+	}}
+	assert.NilError(t, schedule.setIntent(intent))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      repos:
+      - name: repo2
+        schedules:
+          full: 0 3 * * *
+      - name: repo1
+        schedules:
+          full: 0 1 * * 0
+          incremental: 0 1 * * 1-6
+	`))
+
+	assert.Assert(t, schedule.clearIntent(intent, []string{"incremental"}))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `
+spec:
+  backups:
+    pgbackrest:
+      repos:
+      - name: repo2
+        schedules:
+          full: 0 3 * * *
+      - name: repo1
+        schedules:
+          full: 0 1 * * 0
+	`))
+
+	assert.Assert(t, !schedule.clearIntent(intent, []string{"differential"}))
+
+	assert.Assert(t, schedule.clearIntent(intent, []string{"full", "differential", "incremental"}))
+	schedule.RepoName = "repo2"
+	assert.Assert(t, schedule.clearIntent(intent, []string{"full", "differential", "incremental"}))
+	assert.Assert(t, cmp.MarshalMatches(intent.Object, `{}`))
+
+	schedule.RepoName = "repo3"
+	assert.Assert(t, !schedule.clearIntent(intent, []string{"full"}))
+}
@@ -7,8 +7,13 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/url"
 	"os"
 	"strings"
 	"text/tabwriter"
@@ -17,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/crunchydata/postgres-operator-client/internal"
 	"github.com/crunchydata/postgres-operator-client/internal/util"
@@ -72,6 +78,7 @@ HA
 		newShowBackupCommand(config),
 		newShowHACommand(config),
 		newShowUserCommand(config),
+		newShowPgadminCommand(config),
 	)
 
 	// Limit the number of args, that is, only one cluster name
@@ -101,6 +108,14 @@ HA
 				cmd.Printf("\nError returned: %s\n", stderr)
 			}
 		}
+
+		// Point out that automatic failover is off while Patroni is paused.
+		// The paused state is extra, so failing to read it is not an error.
+		if paused, err := getPaused(config, args); err != nil {
+			cmd.PrintErrf("\nWARNING: unable to read whether Patroni is paused: %v\n", err)
+		} else if paused {
+			cmd.Printf("\n"+pausedMessage+"\n", args[0])
+		}
 		return nil
 	}
 
@@ -253,12 +268,26 @@ pgo show ha hippo --output json
 			}
 		}
 
+		// Point out that automatic failover is off while Patroni is paused.
+		// Only the pretty output is meant to be read by people.
+		// The paused state is extra, so failing to read it is not an error.
+		if err == nil && outputEnum == util.PrettyPatroni {
+			if paused, pausedErr := getPaused(config, args); pausedErr != nil {
+				cmd.PrintErrf("\nWARNING: unable to read whether Patroni is paused: %v\n", pausedErr)
+			} else if paused {
+				cmd.Printf("\n"+pausedMessage+"\n", args[0])
+			}
+		}
+
 		return err
 	}
 
 	return cmdShowHA
 }
 
+// pausedMessage is printed with the HA status of a paused cluster.
+const pausedMessage = `Patroni is paused: automatic failover is disabled. Run "pgo resume %s" to resume.`
+
 // getHA execs into the primary Pod, runs the 'patronictl list' command and
 // returns the command output and/or error
 func getHA(
@@ -285,6 +314,18 @@ or for users defined on the PostgresCluster spec.
 Use the "--show-connection-info" flag to get the
 connection info, including password.
 
+Use the "--format" flag to print the connection info
+of one user in a format other programs can read:
+    env          shell commands that export libpq environment variables
+    dotenv       a .env file of libpq variables and DATABASE_URL
+    pgpass       a line of a ~/.pgpass file
+    jdbc         a Java properties file of url, user, and password
+    json         an object of every field in the user Secret
+    secret-yaml  a Secret manifest to apply in another namespace
+
+Use the "--output-file" flag to write that output to a
+file that only you can read.
+
 #### RBAC Requirements
     Resources  Verbs
     ---------  -----
@@ -302,6 +343,12 @@ pgo show user rhino --cluster hippo
 # including sensitive password info
 pgo show user rhino --cluster hippo --show-connection-info
 
+# Write environment variables for user "rhino" to a .env file
+pgo show user rhino --cluster hippo --format dotenv --output-file .env
+
+# Copy the Secret of user "rhino" to the "app" namespace
+pgo show user rhino --cluster hippo --format secret-yaml | kubectl apply -n app -f -
+
 ### Example output
 # Showing all the users of the "hippo" cluster
 CLUSTER  USERNAME
@@ -325,12 +372,27 @@ Connection URL:
 	cmdShowUser.Flags().StringVarP(&cluster, "cluster", "c", "", "Set the Postgres cluster name (required)")
 	cobra.CheckErr(cmdShowUser.MarkFlagRequired("cluster"))
 
+	var formatEnum = util.NoUserFormat
+	cmdShowUser.Flags().Var(&formatEnum, "format",
+		"connection info format. types supported: env,pgpass,jdbc,dotenv,json,secret-yaml")
+
+	var outputFile string
+	cmdShowUser.Flags().StringVar(&outputFile, "output-file", "",
+		"write the --format output to this file with 0600 permissions")
+
 	// Limit the number of args to at most one pguser name
 	cmdShowUser.Args = cobra.MaximumNArgs(1)
 
 	// Define the 'show backup' command
 	cmdShowUser.RunE = func(cmd *cobra.Command, args []string) error {
 
+		if formatEnum == util.NoUserFormat && outputFile != "" {
+			return errors.New("--output-file requires --format")
+		}
+		if formatEnum != util.NoUserFormat && len(args) == 0 {
+			return errors.New("--format requires a USER_NAME")
+		}
+
 		// configure client
 		rest, err := config.ToRESTConfig()
 		if err != nil {
@@ -356,6 +418,10 @@ Connection URL:
 			return nil
 		}
 
+		if formatEnum != util.NoUserFormat {
+			return writeUserFormat(config, secretList.Items[0], formatEnum.String(), outputFile)
+		}
+
 		// If user info found, print
 		return printUsers(cmd, secretList, fields, cluster)
 	}
@@ -381,8 +447,7 @@ func getUsers(client *v1.CoreV1Client,
 	// Set up the labels for listing the secrets; add the user label is present in args
 	labelSelector := util.PostgresUserSecretLabels(cluster)
 	if len(args) > 0 {
-		labelSelector = labelSelector +
-			",postgres-operator.crunchydata.com/pguser=" + args[0]
+		labelSelector = labelSelector + "," + util.LabelPostgresUser + "=" + args[0]
 	}
 
 	return client.Secrets(configNamespace).List(ctx, metav1.ListOptions{
@@ -470,12 +535,172 @@ func printUserConnectionStrings(cmd *cobra.Command,
 	return nil
 }
 
+// writeUserFormat writes the connection information in secret to the output
+// stream or to outputFile after the user confirms.
+func writeUserFormat(config *internal.Config,
+	secret corev1.Secret,
+	format string,
+	outputFile string,
+) error {
+	// Write the prompt to stderr so it does not mix with the formatted output.
+	if !confirm(config.In, config.ErrOut,
+		"WARNING: This command will show sensitive password information."+
+			"\nAre you sure you want to continue? (yes/no): ") {
+		return nil
+	}
+
+	data, err := formatUserSecret(secret, format)
+	if err != nil {
+		return err
+	}
+
+	if outputFile == "" {
+		_, err = config.Out.Write(data)
+		return err
+	}
+
+	// Create the file readable only by its owner, and tighten the permissions
+	// of a file that already exists before writing to it.
+	file, err := os.OpenFile(outputFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err == nil {
+		err = file.Chmod(0o600)
+		if err == nil {
+			_, err = file.Write(data)
+		}
+		err = errors.Join(err, file.Close())
+	}
+	if err == nil {
+		_, _ = fmt.Fprintf(config.Out, "user %s connection info written to %s\n",
+			string(secret.Data["user"]), outputFile)
+	}
+	return err
+}
+
+// formatUserSecret returns the connection information in secret in format.
+func formatUserSecret(secret corev1.Secret, format string) ([]byte, error) {
+	field := func(key string) string { return string(secret.Data[key]) }
+
+	dbname := field("user")
+	if _, ok := secret.Data["dbname"]; ok {
+		dbname = field("dbname")
+	}
+	uri := field("uri")
+	if uri == "" {
+		uri = (&url.URL{
+			Scheme: "postgresql",
+			User:   url.UserPassword(field("user"), field("password")),
+			Host:   net.JoinHostPort(field("host"), field("port")),
+			Path:   "/" + dbname,
+		}).String()
+	}
+
+	libpq := [][2]string{
+		{"PGHOST", field("host")},
+		{"PGPORT", field("port")},
+		{"PGDATABASE", dbname},
+		{"PGUSER", field("user")},
+		{"PGPASSWORD", field("password")},
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "env":
+		// Single quotes prevent every shell expansion; a single quote itself
+		// ends the string, is escaped, and starts another string.
+		for _, kv := range libpq {
+			_, _ = fmt.Fprintf(&buf, "export %s='%s'\n", kv[0],
+				strings.ReplaceAll(kv[1], "'", `'\''`))
+		}
+
+	case "dotenv":
+		quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+		for _, kv := range append(libpq, [2]string{"DATABASE_URL", uri}) {
+			_, _ = fmt.Fprintf(&buf, "%s=\"%s\"\n", kv[0], quote.Replace(kv[1]))
+		}
+
+	case "pgpass":
+		// - https://www.postgresql.org/docs/current/libpq-pgpass.html
+		quote := strings.NewReplacer(`\`, `\\`, `:`, `\:`)
+		_, _ = fmt.Fprintf(&buf, "%s:%s:%s:%s:%s\n",
+			quote.Replace(field("host")), quote.Replace(field("port")),
+			quote.Replace(dbname), quote.Replace(field("user")),
+			quote.Replace(field("password")))
+
+		if _, ok := secret.Data["pgbouncer-host"]; ok {
+			_, _ = fmt.Fprintf(&buf, "%s:%s:%s:%s:%s\n",
+				quote.Replace(field("pgbouncer-host")), quote.Replace(field("pgbouncer-port")),
+				quote.Replace(dbname), quote.Replace(field("user")),
+				quote.Replace(field("password")))
+		}
+
+	case "jdbc":
+		// - https://docs.oracle.com/javase/8/docs/api/java/util/Properties.html#load-java.io.Reader-
+		quote := strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`)
+		jdbcURL := "jdbc:postgresql://" + net.JoinHostPort(field("host"), field("port")) +
+			"/" + url.PathEscape(dbname)
+		_, _ = fmt.Fprintf(&buf, "url=%s\nuser=%s\npassword=%s\n",
+			quote.Replace(jdbcURL), quote.Replace(field("user")),
+			quote.Replace(field("password")))
+
+	case "json":
+		fields := make(map[string]string, len(secret.Data))
+		for key := range secret.Data {
+			fields[key] = field(key)
+		}
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(fields); err != nil {
+			return nil, err
+		}
+
+	case "secret-yaml":
+		// Leave out the namespace, labels, and owner of the original so the
+		// manifest can be applied anywhere.
+		data := make(map[string]any, len(secret.Data))
+		for key, value := range secret.Data {
+			data[key] = base64.StdEncoding.EncodeToString(value)
+		}
+		b, err := yaml.Marshal(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]any{"name": secret.Name},
+			"type":       string(corev1.SecretTypeOpaque),
+			"data":       data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	return buf.Bytes(), nil
+}
+
 // getPrimaryExec returns a executor function for the primary Pod to allow for
 // commands to be run against it.
 func getPrimaryExec(config *internal.Config, args []string) (
 	func(stdin io.Reader, stdout io.Writer, stderr io.Writer, command ...string) error,
 	error,
 ) {
+	// Get the namespace. This will either be from the Kubernetes configuration
+	// or from the --namespace (-n) flag.
+	configNamespace, err := config.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	return getPrimaryExecInNamespace(config, configNamespace, args[0])
+}
+
+// getPrimaryExecInNamespace returns a executor function for the primary Pod of
+// the cluster named clusterName in configNamespace.
+func getPrimaryExecInNamespace(config *internal.Config, configNamespace, clusterName string) (
+	func(stdin io.Reader, stdout io.Writer, stderr io.Writer, command ...string) error,
+	error,
+) {
 
 	// configure client
 	ctx := context.Background()
@@ -488,20 +713,13 @@ func getPrimaryExec(config *internal.Config, args []string) (
 		return nil, err
 	}
 
-	// Get the namespace. This will either be from the Kubernetes configuration
-	// or from the --namespace (-n) flag.
-	configNamespace, err := config.Namespace()
-	if err != nil {
-		return nil, err
-	}
-
 	// Get the primary instance Pod by its labels. For a Postgres cluster
 	// named 'hippo', we'll use the following:
 	//    postgres-operator.crunchydata.com/cluster=hippo
 	//    postgres-operator.crunchydata.com/data=postgres
 	//    postgres-operator.crunchydata.com/role=master
 	pods, err := client.Pods(configNamespace).List(ctx, metav1.ListOptions{
-		LabelSelector: util.PrimaryInstanceLabels(args[0]),
+		LabelSelector: util.PrimaryInstanceLabels(clusterName),
 	})
 	if err != nil {
 		return nil, err
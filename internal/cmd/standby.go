@@ -0,0 +1,679 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+// newStandbyCommand returns the standby command of the PGO plugin.
+// Subcommands of standby manage "spec.standby" for disaster recovery.
+// - https://access.crunchydata.com/documentation/postgres-operator/latest/tutorials/backups-disaster-recovery/disaster-recovery
+func newStandbyCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "standby",
+		Short: "Manage standby PostgresClusters for disaster recovery",
+		Long: `Manage standby PostgresClusters for disaster recovery. A standby follows a
+primary cluster by reading WAL from a shared pgBackRest repository, by
+streaming from the primary's host, or both. Promoting a standby makes it
+writable; demoting a cluster makes it a standby of another.
+
+Changes are sent using server-side apply. Overwriting "spec.standby" set by
+another client may require the --force-conflicts flag.`,
+	}
+
+	cmd.AddCommand(
+		newStandbyCreateCommand(config),
+		newStandbyDemoteCommand(config),
+		newStandbyPromoteCommand(config),
+		newStandbyStatusCommand(config),
+	)
+
+	return cmd
+}
+
+func newStandbyCreateCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create STANDBY_NAME --from PRIMARY_NAME",
+		Short: "Create a standby of a PostgresCluster",
+		Long: `Create a standby PostgresCluster that follows PRIMARY_NAME. It has the Postgres
+version, images, and volume size of PRIMARY_NAME.
+
+With --repo, the standby reads WAL from that repository of PRIMARY_NAME. The
+repository must be in cloud storage, and the Secrets in its configuration must
+exist in the namespace of the standby. With --host, the standby streams WAL
+from that host. Streaming needs both clusters to use certificates from the same
+CA in "spec.customTLSSecret" and "spec.customReplicationTLSSecret". Without
+either flag, the standby reads from the first repository of PRIMARY_NAME.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get create]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Create a standby of 'hippo' that reads from its S3 repository
+pgo standby create hippo-dr --from hippo --repo repo2
+
+# Create a standby of 'hippo' in another namespace that streams from its primary
+pgo standby create hippo-dr --namespace dr --from hippo --from-namespace prod \
+  --host hippo-primary.prod.svc --port 5432
+
+### Example output
+postgresclusters/hippo-dr created as a standby of postgresclusters/hippo`)
+
+	standby := pgStandby{Config: config}
+
+	cmd.Flags().StringVar(&standby.From, "from", "", "the primary PostgresCluster to follow (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("from"))
+	cmd.Flags().StringVar(&standby.FromNamespace, "from-namespace", "", "the namespace of the primary; default is the namespace of the standby")
+	standby.addSourceFlags(cmd)
+
+	// Only one positional argument: the name of the standby.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		standby.ClusterName = args[0]
+		return standby.Create(context.Background())
+	}
+
+	return cmd
+}
+
+func newStandbyPromoteCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote STANDBY_NAME",
+		Short: "Promote a standby PostgresCluster to a primary",
+		Long: `Promote a standby PostgresCluster by setting "spec.standby.enabled" to false.
+The standby stops following its primary and starts accepting writes.
+
+Fence the old primary before promoting: shut it down with "pgo stop" or demote
+it with "pgo standby demote". Two clusters that accept writes diverge, and the
+writes to one of them are lost.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Promote the 'hippo-dr' standby
+pgo standby promote hippo-dr
+
+### Example output
+WARNING: Fence the primary of postgresclusters/hippo-dr before promoting it. If the
+primary is still accepting writes, both clusters diverge and writes are lost.
+Are you sure you want to continue? (yes/no): yes
+postgresclusters/hippo-dr promoted`)
+
+	standby := pgStandby{Config: config}
+
+	cmd.Flags().BoolVar(&standby.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the standby settings")
+
+	// Only one positional argument: the name of the standby.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		standby.ClusterName = args[0]
+		return standby.Promote(context.Background())
+	}
+
+	return cmd
+}
+
+func newStandbyDemoteCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "demote CLUSTER_NAME",
+		Short: "Demote a PostgresCluster to a standby",
+		Long: `Demote a PostgresCluster to a standby that follows a repository or host,
+usually of the cluster that was promoted in its place. The cluster stops
+accepting writes.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Make 'hippo' a standby that reads WAL from repo2
+pgo standby demote hippo --repo repo2
+
+### Example output
+WARNING: postgresclusters/hippo will stop accepting writes.
+Are you sure you want to continue? (yes/no): yes
+postgresclusters/hippo demoted to a standby`)
+
+	standby := pgStandby{Config: config}
+
+	cmd.Flags().BoolVar(&standby.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the standby settings")
+	standby.addSourceFlags(cmd)
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		standby.ClusterName = args[0]
+		if standby.RepoName == "" && standby.Host == "" {
+			return fmt.Errorf("at least one of --repo or --host is required")
+		}
+		return standby.Demote(context.Background())
+	}
+
+	return cmd
+}
+
+func newStandbyStatusCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status STANDBY_NAME",
+		Short: "Show how far a standby PostgresCluster is behind its primary",
+		Long: `Show the Patroni members and WAL positions of a standby PostgresCluster and,
+with --primary, of its primary. The replay lag is the difference between the
+current WAL position of the primary and the replayed position of the standby.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Compare the 'hippo-dr' standby with 'hippo'
+pgo standby status hippo-dr --primary hippo
+
+### Example output
+postgresclusters/hippo-dr: standby enabled, repoName repo2
+CLUSTER    MEMBER                      ROLE             STATE       TL    LAG IN MB
+hippo      hippo-instance1-8kds-0      Leader           running     3
+hippo      hippo-instance1-z4rt-0      Replica          streaming   3     0
+hippo-dr   hippo-dr-instance1-mq2v-0   Standby Leader   streaming   3
+CLUSTER    RECOVERY   CURRENT LSN   RECEIVE LSN   REPLAY LSN   LAST REPLAY
+hippo      false      0/5000060     -             -            -
+hippo-dr   true       -             0/5000000     0/5000000    2025-03-09T14:29:58Z
+hippo-dr replays 96 bytes behind hippo`)
+
+	standby := pgStandby{Config: config}
+
+	cmd.Flags().StringVar(&standby.From, "primary", "", "the primary PostgresCluster to compare with")
+	cmd.Flags().StringVar(&standby.FromNamespace, "primary-namespace", "", "the namespace of the primary; default is the namespace of the standby")
+
+	// Only one positional argument: the name of the standby.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		standby.ClusterName = args[0]
+		return standby.Status(context.Background())
+	}
+
+	return cmd
+}
+
+type pgStandby struct {
+	*internal.Config
+
+	ClusterName    string
+	ForceConflicts bool
+	Host           string
+	Port           int
+	RepoName       string
+
+	// From is the primary PostgresCluster, in FromNamespace.
+	From          string
+	FromNamespace string
+}
+
+// addSourceFlags adds the flags of where a standby gets WAL.
+func (standby *pgStandby) addSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&standby.RepoName, "repo", "", "the repository to read WAL from, such as repo2")
+	cmd.Flags().StringVar(&standby.Host, "host", "", "the host to stream WAL from")
+	cmd.Flags().IntVar(&standby.Port, "port", 0, "the port of --host; default is 5432")
+}
+
+// standbySpec returns the value of "spec.standby" that follows the repository
+// or host of standby.
+func (standby pgStandby) standbySpec() map[string]any {
+	spec := map[string]any{"enabled": true}
+	if standby.RepoName != "" {
+		spec["repoName"] = "repo" + strings.TrimPrefix(standby.RepoName, "repo")
+	}
+	if standby.Host != "" {
+		spec["host"] = standby.Host
+	}
+	if standby.Port != 0 {
+		spec["port"] = int64(standby.Port)
+	}
+	return spec
+}
+
+// standbyCluster returns a PostgresCluster named name that is a standby of
+// source. It also returns warnings about what the standby needs to work.
+func (standby pgStandby) standbyCluster(source *unstructured.Unstructured, name string) (
+	*unstructured.Unstructured, []string, error,
+) {
+	cluster, err := generateClusterLike(source, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	spec := standby.standbySpec()
+
+	if repoName, ok := spec["repoName"].(string); ok {
+		// A standby reads the same repository as its primary, so it needs the
+		// same repository definition, options, and credentials.
+		var repo map[string]any
+		for _, r := range clusterRepos(source) {
+			if r["name"] == repoName {
+				repo = r
+			}
+		}
+		if repo == nil {
+			return nil, nil, fmt.Errorf("repository %q not found in %s", repoName, source.GetName())
+		}
+		if _, ok := repo["volume"]; ok {
+			return nil, nil, fmt.Errorf("repository %q is a volume that only %s can read; use --host to stream instead",
+				repoName, source.GetName())
+		}
+		if err := unstructured.SetNestedSlice(cluster.Object, []any{repo},
+			"spec", "backups", "pgbackrest", "repos"); err != nil {
+			return nil, nil, err
+		}
+
+		if configuration, found, _ := unstructured.NestedSlice(source.Object,
+			"spec", "backups", "pgbackrest", "configuration"); found {
+			if err := unstructured.SetNestedSlice(cluster.Object, configuration,
+				"spec", "backups", "pgbackrest", "configuration"); err != nil {
+				return nil, nil, err
+			}
+			warnings = append(warnings, "the Secrets in spec.backups.pgbackrest.configuration"+
+				" must exist in the namespace of the standby")
+		}
+
+		global, _, _ := unstructured.NestedStringMap(source.Object, "spec", "backups", "pgbackrest", "global")
+		options := map[string]string{}
+		for option, value := range global {
+			if strings.HasPrefix(option, repoName+"-") {
+				options[option] = value
+			}
+		}
+		if len(options) > 0 {
+			if err := unstructured.SetNestedStringMap(cluster.Object, options,
+				"spec", "backups", "pgbackrest", "global"); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if _, ok := spec["host"]; ok {
+		var found bool
+		for _, field := range []string{"customTLSSecret", "customReplicationTLSSecret"} {
+			if value, ok, _ := unstructured.NestedMap(source.Object, "spec", field); ok {
+				found = true
+				if err := unstructured.SetNestedMap(cluster.Object, value, "spec", field); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		if !found {
+			warnings = append(warnings, "streaming needs spec.customTLSSecret and"+
+				" spec.customReplicationTLSSecret from the same CA in both clusters")
+		}
+	}
+
+	err = unstructured.SetNestedMap(cluster.Object, spec, "spec", "standby")
+	return cluster, warnings, err
+}
+
+// setStandby applies spec as "spec.standby" of the cluster.
+func (standby pgStandby) setStandby(ctx context.Context,
+	client dynamic.NamespaceableResourceInterface, namespace string, spec map[string]any,
+) error {
+	cluster, err := client.Namespace(namespace).Get(ctx, standby.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, standby.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedMap(intent.Object, spec, "spec", "standby"); err != nil {
+		return err
+	}
+	_, err = applyCluster(ctx, standby.Config, client.Namespace(namespace), standby.ClusterName,
+		intent, standby.ForceConflicts)
+	return err
+}
+
+// Create creates a standby of From.
+func (standby pgStandby) Create(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(standby)
+	if err != nil {
+		return err
+	}
+	namespace, err := standby.Namespace()
+	if err != nil {
+		return err
+	}
+	sourceNamespace := standby.FromNamespace
+	if sourceNamespace == "" {
+		sourceNamespace = namespace
+	}
+
+	source, err := client.Namespace(sourceNamespace).Get(ctx, standby.From, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	// Follow the first repository when there is nothing else to follow.
+	if standby.RepoName == "" && standby.Host == "" {
+		if repos := clusterRepos(source); len(repos) > 0 {
+			standby.RepoName, _ = repos[0]["name"].(string)
+		}
+	}
+
+	cluster, warnings, err := standby.standbyCluster(source, standby.ClusterName)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		_, _ = fmt.Fprintf(standby.Out, "WARNING: %s\n", warning)
+	}
+
+	if _, err := createPostgresCluster(ctx, standby.Config, namespace, cluster, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(standby.Out, "%s/%s created as a standby of %s/%s\n",
+		mapping.Resource.Resource, standby.ClusterName, mapping.Resource.Resource, standby.From)
+	return nil
+}
+
+// Promote asks for confirmation and then disables the standby.
+func (standby pgStandby) Promote(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(standby)
+	if err != nil {
+		return err
+	}
+	namespace, err := standby.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, standby.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if enabled, _, _ := unstructured.NestedBool(cluster.Object, "spec", "standby", "enabled"); !enabled {
+		return fmt.Errorf("%s/%s is not a standby", mapping.Resource.Resource, standby.ClusterName)
+	}
+
+	if !confirm(standby.In, standby.Out, fmt.Sprintf(
+		"WARNING: Fence the primary of %s/%s before promoting it. If the\n"+
+			"primary is still accepting writes, both clusters diverge and writes are lost."+
+			"\nAre you sure you want to continue? (yes/no): ",
+		mapping.Resource.Resource, standby.ClusterName)) {
+		return nil
+	}
+
+	if err := standby.setStandby(ctx, client, namespace, map[string]any{"enabled": false}); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(standby.Out, "%s/%s promoted\n", mapping.Resource.Resource, standby.ClusterName)
+	return nil
+}
+
+// Demote asks for confirmation and then makes the cluster a standby.
+func (standby pgStandby) Demote(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(standby)
+	if err != nil {
+		return err
+	}
+	namespace, err := standby.Namespace()
+	if err != nil {
+		return err
+	}
+
+	if !confirm(standby.In, standby.Out, fmt.Sprintf(
+		"WARNING: %s/%s will stop accepting writes."+
+			"\nAre you sure you want to continue? (yes/no): ",
+		mapping.Resource.Resource, standby.ClusterName)) {
+		return nil
+	}
+
+	if err := standby.setStandby(ctx, client, namespace, standby.standbySpec()); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(standby.Out, "%s/%s demoted to a standby\n", mapping.Resource.Resource, standby.ClusterName)
+	return nil
+}
+
+// patroniMember is one member in the output of "patronictl list --format json".
+type patroniMember struct {
+	Member string `json:"Member"`
+	Role   string `json:"Role"`
+	State  string `json:"State"`
+	TL     any    `json:"TL"`
+	Lag    any    `json:"Lag in MB"`
+}
+
+// walPosition is the output of Executor.walPositions.
+type walPosition struct {
+	InRecovery      bool    `json:"in_recovery"`
+	CurrentLSN      *string `json:"current_lsn"`
+	ReceiveLSN      *string `json:"receive_lsn"`
+	ReplayLSN       *string `json:"replay_lsn"`
+	ReplayTimestamp *string `json:"replay_timestamp"`
+}
+
+// standbySide is what status knows about one of the clusters.
+type standbySide struct {
+	Cluster  string
+	Members  []patroniMember
+	Position walPosition
+}
+
+// parseLSN returns the byte position of a WAL location such as "16/B374D848".
+func parseLSN(lsn string) (uint64, error) {
+	high, low, ok := strings.Cut(lsn, "/")
+	if ok {
+		h, err1 := strconv.ParseUint(high, 16, 32)
+		l, err2 := strconv.ParseUint(low, 16, 32)
+		if err1 == nil && err2 == nil {
+			return h<<32 | l, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid WAL location %q", lsn)
+}
+
+// getStandbySide execs into the primary Pod of clusterName in namespace and
+// returns its Patroni members and WAL positions.
+func (standby pgStandby) getStandbySide(namespace, clusterName string) (standbySide, error) {
+	side := standbySide{Cluster: clusterName}
+
+	exec, err := getPrimaryExecInNamespace(standby.Config, namespace, clusterName)
+	if err != nil {
+		return side, err
+	}
+
+	stdout, stderr, err := Executor(exec).patronictl("list", "json")
+	if err != nil {
+		return side, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	if err := json.Unmarshal([]byte(stdout), &side.Members); err != nil {
+		return side, fmt.Errorf("unexpected Patroni output: %w", err)
+	}
+
+	stdout, stderr, err = Executor(exec).walPositions()
+	if err != nil {
+		return side, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr))
+	}
+	if err := json.Unmarshal([]byte(stdout), &side.Position); err != nil {
+		return side, fmt.Errorf("unexpected WAL positions: %w", err)
+	}
+	return side, nil
+}
+
+func printStandbySides(w io.Writer, sides []standbySide) error {
+	orNone := func(s *string) string {
+		if s == nil || *s == "" {
+			return "-"
+		}
+		return *s
+	}
+	orBlank := func(v any) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "CLUSTER\tMEMBER\tROLE\tSTATE\tTL\tLAG IN MB\n"); err != nil {
+		return err
+	}
+	for _, side := range sides {
+		for _, m := range side.Members {
+			if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				side.Cluster, m.Member, m.Role, m.State, orBlank(m.TL), orBlank(m.Lag),
+			); err != nil {
+				return err
+			}
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	p = printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "CLUSTER\tRECOVERY\tCURRENT LSN\tRECEIVE LSN\tREPLAY LSN\tLAST REPLAY\n"); err != nil {
+		return err
+	}
+	for _, side := range sides {
+		replayed := orNone(side.Position.ReplayTimestamp)
+		if t, err := time.Parse(time.RFC3339Nano, replayed); err == nil {
+			replayed = t.UTC().Format(time.RFC3339)
+		}
+		if _, err := fmt.Fprintf(p, "%s\t%t\t%s\t%s\t%s\t%s\n",
+			side.Cluster, side.Position.InRecovery, orNone(side.Position.CurrentLSN),
+			orNone(side.Position.ReceiveLSN), orNone(side.Position.ReplayLSN), replayed,
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// replayLag describes how far the standby replays behind the primary.
+func replayLag(primary, standby standbySide) string {
+	if primary.Position.CurrentLSN == nil {
+		return fmt.Sprintf("%s is in recovery, so it is not a primary", primary.Cluster)
+	}
+	if standby.Position.ReplayLSN == nil {
+		return fmt.Sprintf("%s has not replayed any WAL", standby.Cluster)
+	}
+
+	current, err := parseLSN(*primary.Position.CurrentLSN)
+	if err != nil {
+		return err.Error()
+	}
+	replayed, err := parseLSN(*standby.Position.ReplayLSN)
+	if err != nil {
+		return err.Error()
+	}
+	if replayed >= current {
+		return fmt.Sprintf("%s has replayed all WAL of %s", standby.Cluster, primary.Cluster)
+	}
+	return fmt.Sprintf("%s replays %d bytes behind %s", standby.Cluster, current-replayed, primary.Cluster)
+}
+
+// Status prints the standby settings, Patroni members, and WAL positions of
+// the standby and, when there is one, its primary.
+func (standby pgStandby) Status(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(standby)
+	if err != nil {
+		return err
+	}
+	namespace, err := standby.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, standby.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	spec, _, _ := unstructured.NestedMap(cluster.Object, "spec", "standby")
+	summary := "standby disabled"
+	if enabled, _ := spec["enabled"].(bool); enabled {
+		summary = "standby enabled"
+		for _, field := range []string{"repoName", "host", "port"} {
+			if value, ok := spec[field]; ok {
+				summary += fmt.Sprintf(", %s %v", field, value)
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(standby.Out, "%s/%s: %s\n", mapping.Resource.Resource, standby.ClusterName, summary)
+
+	var sides []standbySide
+	if standby.From != "" {
+		primaryNamespace := standby.FromNamespace
+		if primaryNamespace == "" {
+			primaryNamespace = namespace
+		}
+		side, err := standby.getStandbySide(primaryNamespace, standby.From)
+		if err != nil {
+			return err
+		}
+		sides = append(sides, side)
+	}
+
+	side, err := standby.getStandbySide(namespace, standby.ClusterName)
+	if err != nil {
+		return err
+	}
+	sides = append(sides, side)
+
+	if err := printStandbySides(standby.Out, sides); err != nil {
+		return err
+	}
+	if len(sides) == 2 {
+		_, _ = fmt.Fprintln(standby.Out, replayLag(sides[0], sides[1]))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestStandbySpec(t *testing.T) {
+	assert.DeepEqual(t, pgStandby{RepoName: "2"}.standbySpec(),
+		map[string]any{"enabled": true, "repoName": "repo2"})
+	assert.DeepEqual(t, pgStandby{Host: "hippo-primary.prod.svc", Port: 5432}.standbySpec(),
+		map[string]any{"enabled": true, "host": "hippo-primary.prod.svc", "port": int64(5432)})
+}
+
+func TestStandbyCluster(t *testing.T) {
+	source := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PostgresCluster
+metadata:
+  name: hippo
+  namespace: prod
+spec:
+  postgresVersion: 16
+  instances:
+  - dataVolumeClaimSpec:
+      accessModes: [ReadWriteOnce]
+      resources:
+        requests:
+          storage: 50Gi
+  customTLSSecret:
+    name: hippo-tls
+  backups:
+    pgbackrest:
+      configuration:
+      - secret:
+          name: hippo-pgbackrest-repo2
+      global:
+        repo1-retention-full: "2"
+        repo2-path: /pgbackrest/hippo
+        repo2-s3-uri-style: path
+      repos:
+      - name: repo1
+        volume:
+          volumeClaimSpec: {}
+      - name: repo2
+        s3:
+          bucket: backups
+          endpoint: s3.example.com
+          region: us-east-1
+`), &source.Object))
+
+	t.Run("Repo", func(t *testing.T) {
+		cluster, warnings, err := pgStandby{RepoName: "repo2"}.standbyCluster(source, "hippo-dr")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, warnings, []string{
+			"the Secrets in spec.backups.pgbackrest.configuration must exist in the namespace of the standby",
+		})
+		assert.Assert(t, cmp.MarshalMatches(cluster.Object, `
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PostgresCluster
+metadata:
+  name: hippo-dr
+spec:
+  backups:
+    pgbackrest:
+      configuration:
+      - secret:
+          name: hippo-pgbackrest-repo2
+      global:
+        repo2-path: /pgbackrest/hippo
+        repo2-s3-uri-style: path
+      repos:
+      - name: repo2
+        s3:
+          bucket: backups
+          endpoint: s3.example.com
+          region: us-east-1
+  instances:
+  - dataVolumeClaimSpec:
+      accessModes:
+      - ReadWriteOnce
+      resources:
+        requests:
+          storage: 50Gi
+  postgresVersion: 16
+  standby:
+    enabled: true
+    repoName: repo2
+		`))
+
+		_, _, err = pgStandby{RepoName: "repo1"}.standbyCluster(source, "hippo-dr")
+		assert.ErrorContains(t, err, "use --host")
+
+		_, _, err = pgStandby{RepoName: "repo4"}.standbyCluster(source, "hippo-dr")
+		assert.ErrorContains(t, err, `repository "repo4" not found`)
+	})
+
+	t.Run("Host", func(t *testing.T) {
+		cluster, warnings, err := pgStandby{Host: "hippo-primary.prod.svc"}.standbyCluster(source, "hippo-dr")
+		assert.NilError(t, err)
+		assert.Assert(t, len(warnings) == 0)
+
+		tls, _, _ := unstructured.NestedMap(cluster.Object, "spec", "customTLSSecret")
+		assert.DeepEqual(t, tls, map[string]any{"name": "hippo-tls"})
+		standby, _, _ := unstructured.NestedMap(cluster.Object, "spec", "standby")
+		assert.DeepEqual(t, standby, map[string]any{"enabled": true, "host": "hippo-primary.prod.svc"})
+
+		// Without custom certificates, streaming needs them set up.
+		unstructured.RemoveNestedField(source.Object, "spec", "customTLSSecret")
+		_, warnings, err = pgStandby{Host: "hippo-primary.prod.svc"}.standbyCluster(source, "hippo-dr")
+		assert.NilError(t, err)
+		assert.Equal(t, len(warnings), 1)
+		assert.Assert(t, strings.Contains(warnings[0], "same CA"))
+	})
+}
+
+func TestParseLSN(t *testing.T) {
+	position, err := parseLSN("0/5000060")
+	assert.NilError(t, err)
+	assert.Equal(t, position, uint64(0x5000060))
+
+	position, err = parseLSN("16/B374D848")
+	assert.NilError(t, err)
+	assert.Equal(t, position, uint64(0x16)<<32|0xB374D848)
+
+	for _, lsn := range []string{"", "5000060", "0/xyz", "1/2/3"} {
+		_, err = parseLSN(lsn)
+		assert.ErrorContains(t, err, "invalid WAL location", "%q", lsn)
+	}
+}
+
+func TestPrintStandbySides(t *testing.T) {
+	side := func(cluster, members, position string) standbySide {
+		s := standbySide{Cluster: cluster}
+		assert.NilError(t, json.Unmarshal([]byte(members), &s.Members))
+		assert.NilError(t, json.Unmarshal([]byte(position), &s.Position))
+		return s
+	}
+
+	primary := side("hippo", `[
+  {"Cluster": "hippo-ha", "Member": "hippo-instance1-8kds-0", "Host": "10.0.0.1", "Role": "Leader", "State": "running", "TL": 3},
+  {"Cluster": "hippo-ha", "Member": "hippo-instance1-z4rt-0", "Host": "10.0.0.2", "Role": "Replica", "State": "streaming", "TL": 3, "Lag in MB": 0}
+]`, `{"in_recovery": false, "current_lsn": "0/5000060", "receive_lsn": null, "replay_lsn": null, "replay_timestamp": null}`)
+
+	standby := side("hippo-dr", `[
+  {"Cluster": "hippo-dr-ha", "Member": "hippo-dr-instance1-mq2v-0", "Host": "10.1.0.1", "Role": "Standby Leader", "State": "streaming", "TL": 3}
+]`, `{"in_recovery": true, "current_lsn": null, "receive_lsn": "0/5000000", "replay_lsn": "0/5000000", "replay_timestamp": "2025-03-09T14:29:58.123456+00:00"}`)
+
+	var buf bytes.Buffer
+	assert.NilError(t, printStandbySides(&buf, []standbySide{primary, standby}))
+	assert.Equal(t, buf.String(), ``+
+		"CLUSTER    MEMBER                      ROLE             STATE       TL    LAG IN MB\n"+
+		"hippo      hippo-instance1-8kds-0      Leader           running     3     \n"+
+		"hippo      hippo-instance1-z4rt-0      Replica          streaming   3     0\n"+
+		"hippo-dr   hippo-dr-instance1-mq2v-0   Standby Leader   streaming   3     \n"+
+		"CLUSTER    RECOVERY   CURRENT LSN   RECEIVE LSN   REPLAY LSN   LAST REPLAY\n"+
+		"hippo      false      0/5000060     -             -            -\n"+
+		"hippo-dr   true       -             0/5000000     0/5000000    2025-03-09T14:29:58Z\n")
+
+	assert.Equal(t, replayLag(primary, standby), "hippo-dr replays 96 bytes behind hippo")
+	assert.Equal(t, replayLag(standby, primary), "hippo-dr is in recovery, so it is not a primary")
+	assert.Equal(t, replayLag(primary, primary), "hippo has not replayed any WAL")
+
+	caughtUp := "0/5000060"
+	standby.Position.ReplayLSN = &caughtUp
+	assert.Equal(t, replayLag(primary, standby), "hippo-dr has replayed all WAL of hippo")
+}
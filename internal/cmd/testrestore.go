@@ -0,0 +1,354 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+func newBackupTestRestoreCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test-restore CLUSTER_NAME",
+		Short: "Restore a backup into a scratch PostgresCluster to prove it works",
+		Long: `Test-restore creates a temporary PostgresCluster that is restored from a backup
+of CLUSTER_NAME using "spec.dataSource", waits for it to be ready, runs your
+validation SQL in it, and then deletes it. It reports how long the restore took.
+
+The scratch cluster is created in --scratch-namespace, which must exist, or in
+the namespace of CLUSTER_NAME. It uses the Postgres version, image, and volume
+size of CLUSTER_NAME.
+
+Validation SQL runs on the primary of the scratch cluster and stops at the
+first error. Queries such as row counts and checksums are printed with their
+results. To fail the test when a result is wrong, raise an error; for example:
+    DO $$ BEGIN ASSERT (SELECT count(*) FROM orders) > 0; END $$;
+
+Test-restore exits with status 0 when the restore and validation succeed and 1
+otherwise.
+
+The scratch cluster is deleted when test-restore is interrupted, too. Scratch
+clusters are labeled with the name of the cluster they restore, so any that
+are left behind can be found and deleted:
+    kubectl get postgresclusters -A -l postgres-operator.crunchydata.com/pgo-test-restore
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get create delete]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Restore the latest backup of 'hippo' in the 'scratch' namespace
+pgo backup test-restore hippo --scratch-namespace scratch \
+  --database app --sql "SELECT count(*) FROM orders"
+
+# Restore 'hippo' from repo2 as it was at 2 PM on March 9th
+pgo backup test-restore hippo --repo 2 --target-time "2025-03-09 14:00:00+00" \
+  --database app --sql-file ./validate.sql
+
+### Example output
+postgresclusters/hippo-restore-x7k2p created in namespace scratch
+postgresclusters/hippo-restore-x7k2p restored from repo1 in 4m12s
+SELECT count(*) FROM orders
+ count
+-------
+ 10214
+(1 row)
+
+postgresclusters/hippo-restore-x7k2p deleted
+Test restore of postgresclusters/hippo passed in 4m15s`)
+
+	restore := pgTestRestore{Config: config}
+
+	cmd.Flags().StringVar(&restore.RepoName, "repo", "", "the repository to restore from, such as 1 or repo1; default is the first repository")
+	cmd.Flags().StringVar(&restore.Set, "set", "", "the label of the backup set to restore; default is the latest")
+	cmd.Flags().StringVar(&restore.TargetTime, "target-time", "",
+		`recover to this time, such as "2025-03-09 14:00:00+00"`)
+	cmd.Flags().StringVar(&restore.ScratchNamespace, "scratch-namespace", "", "the namespace of the scratch cluster")
+	cmd.Flags().StringVar(&restore.Database, "database", "postgres", "the database to run the validation SQL in")
+	cmd.Flags().StringArrayVar(&restore.SQL, "sql", nil, "validation SQL; can be used multiple times")
+	cmd.Flags().StringArrayVar(&restore.SQLFiles, "sql-file", nil, "path to a file of validation SQL; can be used multiple times")
+	cmd.Flags().DurationVar(&restore.Timeout, "timeout", 30*time.Minute, "how long to wait for the scratch cluster to be ready")
+	cmd.Flags().BoolVar(&restore.Keep, "keep", false, "keep the scratch cluster to investigate it")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		restore.ClusterName = args[0]
+
+		// Stop waiting on Ctrl-C or SIGTERM so the scratch cluster is deleted.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return restore.Run(ctx)
+	}
+
+	return cmd
+}
+
+type pgTestRestore struct {
+	*internal.Config
+
+	ClusterName      string
+	Database         string
+	Keep             bool
+	RepoName         string
+	ScratchNamespace string
+	Set              string
+	SQL              []string
+	SQLFiles         []string
+	TargetTime       string
+	Timeout          time.Duration
+
+	// pollInterval is how often to check the scratch cluster; zero is 5s.
+	pollInterval time.Duration
+}
+
+// restoreOptions returns the pgBackRest restore options of the data source.
+func (restore pgTestRestore) restoreOptions() ([]string, error) {
+	var options []string
+	if restore.Set != "" {
+		options = append(options, "--set="+restore.Set)
+	}
+	if restore.TargetTime != "" {
+		target, err := parseRecoveryTarget(restore.TargetTime)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, "--type=time",
+			`--target="`+target.Format("2006-01-02 15:04:05-07")+`"`)
+	}
+	return options, nil
+}
+
+// validationScript returns the SQL of the flags followed by the SQL of files.
+func (restore pgTestRestore) validationScript() (string, error) {
+	var script strings.Builder
+	for _, sql := range restore.SQL {
+		script.WriteString(strings.TrimSpace(sql))
+		if !strings.HasSuffix(script.String(), ";") {
+			script.WriteString(";")
+		}
+		script.WriteString("\n")
+	}
+	for _, path := range restore.SQLFiles {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		script.Write(content)
+		script.WriteString("\n")
+	}
+	return script.String(), nil
+}
+
+// scratchCluster returns a PostgresCluster named name that restores from
+// repo of source.
+func scratchCluster(source *unstructured.Unstructured, name, namespace, repo string, options []string) (
+	*unstructured.Unstructured, error,
+) {
+	cluster, err := generateClusterLike(source, name)
+	if err != nil {
+		return nil, err
+	}
+	cluster.SetLabels(map[string]string{util.LabelTestRestore: source.GetName()})
+
+	dataSource := map[string]any{
+		"clusterName": source.GetName(),
+		"repoName":    repo,
+	}
+	if namespace != source.GetNamespace() {
+		dataSource["clusterNamespace"] = source.GetNamespace()
+	}
+	if len(options) > 0 {
+		// Unstructured objects hold []any rather than []string.
+		values := make([]any, len(options))
+		for i := range options {
+			values[i] = options[i]
+		}
+		dataSource["options"] = values
+	}
+	err = unstructured.SetNestedField(cluster.Object, map[string]any{
+		"postgresCluster": dataSource,
+	}, "spec", "dataSource")
+	return cluster, err
+}
+
+// scratchStatus returns whether or not every instance of cluster is ready. It
+// returns an error when the restore has failed.
+func scratchStatus(cluster *unstructured.Unstructured) (bool, error) {
+	finished, _, _ := unstructured.NestedBool(cluster.Object, "status", "pgbackrest", "restore", "finished")
+	succeeded, _, _ := unstructured.NestedInt64(cluster.Object, "status", "pgbackrest", "restore", "succeeded")
+	if finished && succeeded == 0 {
+		return false, errors.New("the restore Job failed")
+	}
+
+	instances, _, _ := unstructured.NestedSlice(cluster.Object, "status", "instances")
+	if len(instances) == 0 {
+		return false, nil
+	}
+	for _, instance := range instances {
+		replicas, _, _ := unstructured.NestedInt64(asMap(instance), "replicas")
+		ready, _, _ := unstructured.NestedInt64(asMap(instance), "readyReplicas")
+		if replicas == 0 || ready < replicas {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// wait returns when the scratch cluster is ready, has failed, Timeout passes,
+// or parent is done.
+func (restore pgTestRestore) wait(parent context.Context, client dynamic.ResourceInterface, name string) error {
+	interval := restore.pollInterval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(parent, restore.Timeout)
+	defer cancel()
+
+	err := wait.PollUntilWithContext(ctx, interval, func(ctx context.Context) (bool, error) {
+		cluster, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return scratchStatus(cluster)
+	})
+
+	if parent.Err() != nil {
+		return fmt.Errorf("interrupted while waiting for postgrescluster %q: %w", name, parent.Err())
+	}
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return fmt.Errorf("timed out after %v waiting for postgrescluster %q to be ready", restore.Timeout, name)
+	}
+	return err
+}
+
+// deleteScratchCluster deletes the scratch cluster with a context of its own,
+// because the context of Run may be done when it is interrupted.
+func deleteScratchCluster(config *internal.Config, namespace, name string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	return deletePostgresCluster(ctx, config, namespace, name)
+}
+
+// Run creates the scratch cluster, waits for it, validates it, and deletes it.
+func (restore pgTestRestore) Run(ctx context.Context) (err error) {
+	started := time.Now()
+
+	options, err := restore.restoreOptions()
+	if err != nil {
+		return err
+	}
+	script, err := restore.validationScript()
+	if err != nil {
+		return err
+	}
+
+	mapping, client, err := v1beta1.NewPostgresClusterClient(restore)
+	if err != nil {
+		return err
+	}
+	namespace, err := restore.Namespace()
+	if err != nil {
+		return err
+	}
+	scratchNamespace := restore.ScratchNamespace
+	if scratchNamespace == "" {
+		scratchNamespace = namespace
+	}
+
+	source, err := client.Namespace(namespace).Get(ctx, restore.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	var repo string
+	for _, r := range clusterRepos(source) {
+		name, _ := r["name"].(string)
+		if repo == "" && (restore.RepoName == "" || name == "repo"+strings.TrimPrefix(restore.RepoName, "repo")) {
+			repo = name
+		}
+	}
+	if repo == "" {
+		return fmt.Errorf("repository %q not found in %s/%s",
+			restore.RepoName, mapping.Resource.Resource, restore.ClusterName)
+	}
+
+	suffix, err := generatePassword(5)
+	if err != nil {
+		return err
+	}
+	name := restore.ClusterName + "-restore-" + strings.ToLower(suffix)
+
+	scratch, err := scratchCluster(source, name, scratchNamespace, repo, options)
+	if err != nil {
+		return err
+	}
+	if _, err := createPostgresCluster(ctx, restore.Config, scratchNamespace, scratch, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(restore.Out, "%s/%s created in namespace %s\n", mapping.Resource.Resource, name, scratchNamespace)
+
+	defer func() {
+		if restore.Keep {
+			_, _ = fmt.Fprintf(restore.Out, "%s/%s kept in namespace %s\n", mapping.Resource.Resource, name, scratchNamespace)
+		} else if _, deleteErr := deleteScratchCluster(restore.Config, scratchNamespace, name); deleteErr != nil {
+			err = errors.Join(err, deleteErr)
+		} else {
+			_, _ = fmt.Fprintf(restore.Out, "%s/%s deleted\n", mapping.Resource.Resource, name)
+		}
+
+		if err == nil {
+			_, _ = fmt.Fprintf(restore.Out, "Test restore of %s/%s passed in %v\n",
+				mapping.Resource.Resource, restore.ClusterName, time.Since(started).Round(time.Second))
+		}
+	}()
+
+	created := time.Now()
+	if err := restore.wait(ctx, client.Namespace(scratchNamespace), name); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(restore.Out, "%s/%s restored from %s in %v\n",
+		mapping.Resource.Resource, name, repo, time.Since(created).Round(time.Second))
+
+	if strings.TrimSpace(script) == "" {
+		return nil
+	}
+
+	exec, err := getPrimaryExecInNamespace(restore.Config, scratchNamespace, name)
+	if err != nil {
+		return err
+	}
+	stdout, stderr, err := Executor(exec).psqlScript(restore.Database, script)
+	_, _ = fmt.Fprint(restore.Out, stdout)
+	if err != nil {
+		return fmt.Errorf("validation failed: %w: %s", err, strings.TrimSpace(stderr))
+	}
+	return nil
+}
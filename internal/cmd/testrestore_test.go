@@ -0,0 +1,173 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestTestRestoreOptions(t *testing.T) {
+	options, err := pgTestRestore{}.restoreOptions()
+	assert.NilError(t, err)
+	assert.Assert(t, options == nil)
+
+	options, err = pgTestRestore{
+		Set: "20250302-010002F", TargetTime: "2025-03-09T10:00:00-04:00",
+	}.restoreOptions()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, options, []string{
+		"--set=20250302-010002F", "--type=time", `--target="2025-03-09 14:00:00+00"`,
+	})
+
+	_, err = pgTestRestore{TargetTime: "noon"}.restoreOptions()
+	assert.ErrorContains(t, err, `invalid time "noon"`)
+}
+
+func TestTestRestoreValidationScript(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "validate.sql")
+	assert.NilError(t, os.WriteFile(file, []byte("SELECT md5(string_agg(id::text, ',')) FROM orders;"), 0o600))
+
+	script, err := pgTestRestore{
+		SQL:      []string{" SELECT count(*) FROM orders ", "SELECT 1;"},
+		SQLFiles: []string{file},
+	}.validationScript()
+	assert.NilError(t, err)
+	assert.Equal(t, script, ``+
+		"SELECT count(*) FROM orders;\n"+
+		"SELECT 1;\n"+
+		"SELECT md5(string_agg(id::text, ',')) FROM orders;\n")
+
+	_, err = pgTestRestore{SQLFiles: []string{filepath.Join(t.TempDir(), "missing.sql")}}.validationScript()
+	assert.ErrorContains(t, err, "missing.sql")
+}
+
+func TestScratchCluster(t *testing.T) {
+	source := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PostgresCluster
+metadata:
+  name: hippo
+  namespace: prod
+spec:
+  postgresVersion: 16
+  image: registry.example.com/postgres:16
+  instances:
+  - name: big
+    replicas: 3
+    dataVolumeClaimSpec:
+      accessModes: [ReadWriteOnce]
+      resources:
+        requests:
+          storage: 50Gi
+  backups:
+    pgbackrest:
+      image: registry.example.com/pgbackrest:2.54
+      repos:
+      - name: repo1
+`), &source.Object))
+
+	cluster, err := scratchCluster(source, "hippo-restore-abcde", "scratch", "repo1",
+		[]string{"--set=20250302-010002F"})
+	assert.NilError(t, err)
+	assert.Assert(t, cmp.MarshalMatches(cluster.Object, `
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PostgresCluster
+metadata:
+  labels:
+    postgres-operator.crunchydata.com/pgo-test-restore: hippo
+  name: hippo-restore-abcde
+spec:
+  backups:
+    pgbackrest:
+      image: registry.example.com/pgbackrest:2.54
+      repos:
+      - name: repo1
+        volume:
+          volumeClaimSpec:
+            accessModes:
+            - ReadWriteOnce
+            resources:
+              requests:
+                storage: 1Gi
+  dataSource:
+    postgresCluster:
+      clusterName: hippo
+      clusterNamespace: prod
+      options:
+      - --set=20250302-010002F
+      repoName: repo1
+  image: registry.example.com/postgres:16
+  instances:
+  - dataVolumeClaimSpec:
+      accessModes:
+      - ReadWriteOnce
+      resources:
+        requests:
+          storage: 50Gi
+  postgresVersion: 16
+	`))
+
+	// The same namespace needs no clusterNamespace, and objects can be copied.
+	cluster, err = scratchCluster(source, "hippo-restore-abcde", "prod", "repo1", nil)
+	assert.NilError(t, err)
+	assert.Assert(t, cluster.DeepCopy() != nil)
+	dataSource, _, _ := unstructured.NestedMap(cluster.Object, "spec", "dataSource", "postgresCluster")
+	assert.DeepEqual(t, dataSource, map[string]any{"clusterName": "hippo", "repoName": "repo1"})
+
+	unstructured.RemoveNestedField(source.Object, "spec", "postgresVersion")
+	_, err = scratchCluster(source, "hippo-restore-abcde", "prod", "repo1", nil)
+	assert.ErrorContains(t, err, "Postgres version of hippo")
+}
+
+func TestScratchStatus(t *testing.T) {
+	status := func(text string) *unstructured.Unstructured {
+		cluster := new(unstructured.Unstructured)
+		assert.NilError(t, kyaml.Unmarshal([]byte(text), &cluster.Object))
+		return cluster
+	}
+
+	ready, err := scratchStatus(status(`{}`))
+	assert.NilError(t, err)
+	assert.Assert(t, !ready)
+
+	ready, err = scratchStatus(status(`
+status:
+  instances:
+  - name: "00"
+    replicas: 1
+    readyReplicas: 0
+`))
+	assert.NilError(t, err)
+	assert.Assert(t, !ready)
+
+	ready, err = scratchStatus(status(`
+status:
+  instances:
+  - name: "00"
+    replicas: 1
+    readyReplicas: 1
+`))
+	assert.NilError(t, err)
+	assert.Assert(t, ready)
+
+	_, err = scratchStatus(status(`
+status:
+  pgbackrest:
+    restore:
+      finished: true
+      failed: 6
+`))
+	assert.ErrorContains(t, err, "restore Job failed")
+}
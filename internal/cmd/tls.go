@@ -0,0 +1,527 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newTLSCommand returns the tls command of the PGO plugin. Subcommands of tls
+// show and change the certificates of a PostgresCluster.
+// - https://access.crunchydata.com/documentation/postgres-operator/latest/tutorials/day-two/customize-cluster#customize-tls
+func newTLSCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tls",
+		Short: "Show or change the TLS certificates of a PostgresCluster",
+		Long: `Show or change the TLS certificates of a PostgresCluster. PGO generates the
+certificates of a cluster unless "spec.customTLSSecret" and
+"spec.customReplicationTLSSecret" name Secrets of your own.`,
+	}
+
+	cmd.AddCommand(
+		newTLSSetCommand(config),
+		newTLSShowCommand(config),
+	)
+
+	return cmd
+}
+
+func newTLSShowCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show CLUSTER_NAME",
+		Short: "Show the TLS certificates of a PostgresCluster",
+		Long: `Show the subject, SANs, issuer, and expiry of the cluster and replication
+certificates of a PostgresCluster and of their CAs. Certificates are read from
+the custom Secrets in the spec or from the Secrets that PGO generates.
+
+A warning is printed for each certificate that expires within --warn-days.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+    secrets                                             [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Show the certificates of the 'hippo' postgrescluster
+pgo tls show hippo
+
+### Example output
+CERTIFICATE      SECRET                   SUBJECT                                                 ISSUER                    SANS                                                                       EXPIRES
+cluster          hippo-cluster-cert       CN=hippo-primary.postgres-operator.svc.cluster.local.   CN=postgres-operator-ca   hippo-primary.postgres-operator.svc,hippo-replicas.postgres-operator.svc   2026-11-02 (15 days)
+cluster CA       hippo-cluster-cert       CN=postgres-operator-ca                                 CN=postgres-operator-ca                                                                              2035-03-01 (3056 days)
+replication      hippo-replication-cert   CN=_crunchyrepl                                         CN=postgres-operator-ca                                                                              2026-11-02 (15 days)
+replication CA   hippo-replication-cert   CN=postgres-operator-ca                                 CN=postgres-operator-ca                                                                              2035-03-01 (3056 days)
+WARNING: The cluster certificate in secrets/hippo-cluster-cert expires in 15 days.
+WARNING: The replication certificate in secrets/hippo-replication-cert expires in 15 days.`)
+
+	certificates := pgTLS{Config: config}
+
+	cmd.Flags().IntVar(&certificates.WarnDays, "warn-days", 30, "warn about certificates that expire within this many days")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		certificates.ClusterName = args[0]
+		return certificates.Show(context.Background(), time.Now())
+	}
+
+	return cmd
+}
+
+func newTLSSetCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set CLUSTER_NAME --cert FILE --key FILE --ca FILE",
+		Short: "Use your own TLS certificate for a PostgresCluster",
+		Long: `Use your own TLS certificate for a PostgresCluster. The certificate, key, and CA
+are checked, stored in a Secret owned by the PostgresCluster, and then named in
+"spec.customTLSSecret" or, with --replication, "spec.customReplicationTLSSecret".
+
+The certificate must match the key and be signed by the CA. The cluster
+certificate must cover the primary and replica Service names of the cluster.
+The replication certificate must have the common name "_crunchyrepl". PGO uses
+custom certificates only when both are set, and they must share the same CA.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+    secrets                                             [patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Use your own certificates for the 'hippo' postgrescluster
+pgo tls set hippo --cert hippo.crt --key hippo.key --ca ca.crt
+pgo tls set hippo --replication --cert repl.crt --key repl.key --ca ca.crt
+
+### Example output
+secrets/hippo-custom-tls applied
+postgresclusters/hippo updated`)
+
+	certificates := pgTLS{Config: config}
+
+	cmd.Flags().StringVar(&certificates.CertFile, "cert", "", "path to the PEM certificate and any intermediates")
+	cmd.Flags().StringVar(&certificates.KeyFile, "key", "", "path to the PEM private key")
+	cmd.Flags().StringVar(&certificates.CAFile, "ca", "", "path to the PEM certificate of the CA")
+	cobra.CheckErr(cmd.MarkFlagRequired("cert"))
+	cobra.CheckErr(cmd.MarkFlagRequired("key"))
+	cobra.CheckErr(cmd.MarkFlagRequired("ca"))
+	cmd.Flags().BoolVar(&certificates.Replication, "replication", false, "set the replication certificate")
+	cmd.Flags().BoolVar(&certificates.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the TLS settings")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		certificates.ClusterName = args[0]
+
+		var files [3][]byte
+		for i, path := range []string{certificates.CertFile, certificates.KeyFile, certificates.CAFile} {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files[i] = content
+		}
+		return certificates.Set(context.Background(), files[0], files[1], files[2], time.Now())
+	}
+
+	return cmd
+}
+
+type pgTLS struct {
+	*internal.Config
+
+	CAFile         string
+	CertFile       string
+	ClusterName    string
+	ForceConflicts bool
+	KeyFile        string
+	Replication    bool
+	WarnDays       int
+}
+
+// tlsCertificate is a certificate that PGO generates or takes from a Secret.
+type tlsCertificate struct {
+	Name string
+
+	// Field is the spec field of the custom Secret.
+	Field string
+
+	// Suffix is the suffix of the name of the generated Secret.
+	Suffix string
+}
+
+var tlsCertificates = []tlsCertificate{
+	{Name: "cluster", Field: "customTLSSecret", Suffix: "-cluster-cert"},
+	{Name: "replication", Field: "customReplicationTLSSecret", Suffix: "-replication-cert"},
+}
+
+// replicationCommonName is the user that Postgres replication authenticates
+// as with the replication certificate.
+const replicationCommonName = "_crunchyrepl"
+
+// secretKeys returns the name of the Secret of certificate and the Secret keys
+// of its "tls.crt" and "ca.crt". Custom Secrets can map these to other keys.
+func (certificate tlsCertificate) secretKeys(cluster *unstructured.Unstructured) (string, map[string]string) {
+	keys := map[string]string{"tls.crt": "tls.crt", "ca.crt": "ca.crt"}
+
+	name, found, _ := unstructured.NestedString(cluster.Object, "spec", certificate.Field, "name")
+	if !found {
+		return cluster.GetName() + certificate.Suffix, keys
+	}
+
+	items, _, _ := unstructured.NestedSlice(cluster.Object, "spec", certificate.Field, "items")
+	for _, item := range items {
+		key, _ := asMap(item)["key"].(string)
+		path, _ := asMap(item)["path"].(string)
+		if _, ok := keys[path]; ok && key != "" {
+			keys[path] = key
+		}
+	}
+	return name, keys
+}
+
+// certificateRow describes one certificate in a Secret.
+type certificateRow struct {
+	Certificate string
+	Secret      string
+	Subject     string
+	Issuer      string
+	SANs        []string
+	NotAfter    time.Time
+}
+
+// parseCertificates returns the certificates in the PEM data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificates found")
+	}
+	return certificates, nil
+}
+
+// certificateRows returns rows for the first certificate in "tls.crt" and
+// "ca.crt" of data.
+func certificateRows(name, secret string, data map[string][]byte, keys map[string]string) ([]certificateRow, error) {
+	var rows []certificateRow
+	for _, file := range []struct{ key, label string }{
+		{"tls.crt", name}, {"ca.crt", name + " CA"},
+	} {
+		certificates, err := parseCertificates(data[keys[file.key]])
+		if err != nil {
+			return nil, fmt.Errorf("secrets/%s %q: %w", secret, keys[file.key], err)
+		}
+
+		c := certificates[0]
+		row := certificateRow{
+			Certificate: file.label,
+			Secret:      secret,
+			Subject:     c.Subject.String(),
+			Issuer:      c.Issuer.String(),
+			SANs:        c.DNSNames,
+			NotAfter:    c.NotAfter,
+		}
+		for _, ip := range c.IPAddresses {
+			row.SANs = append(row.SANs, ip.String())
+		}
+		for _, uri := range c.URIs {
+			row.SANs = append(row.SANs, uri.String())
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// daysUntil returns the number of whole days from now until then.
+func daysUntil(now, then time.Time) int {
+	return int(then.Sub(now).Hours() / 24)
+}
+
+func printCertificateRows(w io.Writer, rows []certificateRow, now time.Time, warnDays int) error {
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "CERTIFICATE\tSECRET\tSUBJECT\tISSUER\tSANS\tEXPIRES\n"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		expires := "expired"
+		if row.NotAfter.After(now) {
+			expires = fmt.Sprintf("%s (%d days)", row.NotAfter.UTC().Format(time.DateOnly), daysUntil(now, row.NotAfter))
+		}
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.Certificate, row.Secret, row.Subject, row.Issuer, strings.Join(row.SANs, ","), expires,
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		switch {
+		case !row.NotAfter.After(now):
+			_, _ = fmt.Fprintf(&buf, "WARNING: The %s certificate in secrets/%s has expired.\n",
+				row.Certificate, row.Secret)
+		case row.NotAfter.Before(now.AddDate(0, 0, warnDays)):
+			_, _ = fmt.Fprintf(&buf, "WARNING: The %s certificate in secrets/%s expires in %d days.\n",
+				row.Certificate, row.Secret, daysUntil(now, row.NotAfter))
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// checkCertificate returns an error when the certificate does not match the
+// key, is not signed by the CA, or does not fit how PGO uses it.
+func (certificates pgTLS) checkCertificate(certPEM, keyPEM, caPEM []byte, namespace string, now time.Time) error {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("the certificate and key do not match: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no certificates found in the CA")
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range pair.Certificate[1:] {
+		if c, err := x509.ParseCertificate(der); err == nil {
+			intermediates.AddCert(c)
+		}
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		CurrentTime:   now,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		Roots:         roots,
+	}); err != nil {
+		return fmt.Errorf("the certificate is not valid with the CA: %w", err)
+	}
+
+	if certificates.Replication {
+		if leaf.Subject.CommonName != replicationCommonName {
+			return fmt.Errorf("the replication certificate must have the common name %q, not %q",
+				replicationCommonName, leaf.Subject.CommonName)
+		}
+		return nil
+	}
+
+	var missing []string
+	for _, service := range []string{"primary", "replicas"} {
+		name := fmt.Sprintf("%s-%s.%s.svc", certificates.ClusterName, service, namespace)
+		if leaf.VerifyHostname(name) != nil {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the certificate does not cover the Service names %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// target returns the certificate that Set changes.
+func (certificates pgTLS) target() tlsCertificate {
+	if certificates.Replication {
+		return tlsCertificates[1]
+	}
+	return tlsCertificates[0]
+}
+
+// secretName returns the name of the Secret that Set creates.
+func (certificates pgTLS) secretName() string {
+	if certificates.Replication {
+		return certificates.ClusterName + "-custom-replication-tls"
+	}
+	return certificates.ClusterName + "-custom-tls"
+}
+
+// applySecret creates or updates the Secret of the certificate. The Secret is
+// owned by cluster so it is deleted along with it.
+func (certificates pgTLS) applySecret(ctx context.Context,
+	core corev1client.CoreV1Interface, cluster *unstructured.Unstructured, data map[string][]byte,
+) error {
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      certificates.secretName(),
+			Namespace: cluster.GetNamespace(),
+			Labels: map[string]string{
+				util.LabelCluster: certificates.ClusterName,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: cluster.GetAPIVersion(),
+				Kind:       cluster.GetKind(),
+				Name:       cluster.GetName(),
+				UID:        cluster.GetUID(),
+			}},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+
+	patch, err := json.Marshal(secret)
+	if err != nil {
+		return err
+	}
+
+	// This client owns the Secret, so take it over from anyone who changed it.
+	force := true
+	_, err = core.Secrets(cluster.GetNamespace()).Patch(ctx, secret.Name, types.ApplyPatchType, patch,
+		certificates.Patch.PatchOptions(metav1.PatchOptions{Force: &force}))
+	return err
+}
+
+// Show prints the certificates of the cluster.
+func (certificates pgTLS) Show(ctx context.Context, now time.Time) error {
+	_, client, err := v1beta1.NewPostgresClusterClient(certificates)
+	if err != nil {
+		return err
+	}
+	namespace, err := certificates.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, certificates.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	rest, err := certificates.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	core, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+
+	var rows []certificateRow
+	for _, certificate := range tlsCertificates {
+		name, keys := certificate.secretKeys(cluster)
+		secret, err := core.Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		more, err := certificateRows(certificate.Name, name, secret.Data, keys)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, more...)
+	}
+
+	return printCertificateRows(certificates.Out, rows, now, certificates.WarnDays)
+}
+
+// Set checks the certificate, stores it in a Secret, and names that Secret in
+// the spec of the cluster.
+func (certificates pgTLS) Set(ctx context.Context, certPEM, keyPEM, caPEM []byte, now time.Time) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(certificates)
+	if err != nil {
+		return err
+	}
+	namespace, err := certificates.Namespace()
+	if err != nil {
+		return err
+	}
+
+	if err := certificates.checkCertificate(certPEM, keyPEM, caPEM, namespace, now); err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, certificates.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, certificates.Patch.FieldManager); err != nil {
+		return err
+	}
+
+	rest, err := certificates.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	core, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+	if err := certificates.applySecret(ctx, core, cluster, map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+		"ca.crt":                caPEM,
+	}); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(certificates.Out, "secrets/%s applied\n", certificates.secretName())
+
+	target := certificates.target()
+	if err := unstructured.SetNestedMap(intent.Object, map[string]any{
+		"name": certificates.secretName(),
+	}, "spec", target.Field); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, certificates.Config, client.Namespace(namespace), certificates.ClusterName,
+		intent, certificates.ForceConflicts); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(certificates.Out, "%s/%s updated\n", mapping.Resource.Resource, certificates.ClusterName)
+
+	// PGO uses custom certificates only when both fields are set.
+	for _, other := range tlsCertificates {
+		if other.Field == target.Field {
+			continue
+		}
+		if _, found, _ := unstructured.NestedMap(cluster.Object, "spec", other.Field); !found {
+			_, _ = fmt.Fprintf(certificates.Out,
+				"WARNING: PGO uses custom certificates only when %q is also set.\n", "spec."+other.Field)
+		}
+	}
+	return nil
+}
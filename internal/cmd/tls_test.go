@@ -0,0 +1,180 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// testCertificate returns a PEM certificate and key signed by parent, or
+// self-signed when parent is nil.
+func testCertificate(t *testing.T, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (
+	*x509.Certificate, *ecdsa.PrivateKey, []byte, []byte,
+) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NilError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+
+	return certificate, key,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestTLSCheckCertificate(t *testing.T) {
+	now := time.Date(2025, time.March, 9, 12, 0, 0, 0, time.UTC)
+
+	ca, caKey, caPEM, _ := testCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "example-ca"},
+		NotBefore:             now.AddDate(-1, 0, 0),
+		NotAfter:              now.AddDate(10, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	_, _, otherCAPEM, _ := testCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "other-ca"},
+		NotBefore:             now.AddDate(-1, 0, 0),
+		NotAfter:              now.AddDate(10, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+
+	leaf := func(commonName string, names ...string) ([]byte, []byte) {
+		_, _, certPEM, keyPEM := testCertificate(t, &x509.Certificate{
+			SerialNumber: big.NewInt(3),
+			Subject:      pkix.Name{CommonName: commonName},
+			DNSNames:     names,
+			NotBefore:    now.AddDate(0, -1, 0),
+			NotAfter:     now.AddDate(1, 0, 0),
+		}, ca, caKey)
+		return certPEM, keyPEM
+	}
+
+	cluster := pgTLS{ClusterName: "hippo"}
+	certPEM, keyPEM := leaf("hippo-primary.prod.svc", "hippo-primary.prod.svc", "*.prod.svc")
+	assert.NilError(t, cluster.checkCertificate(certPEM, keyPEM, caPEM, "prod", now))
+
+	_, otherKeyPEM := leaf("other")
+	assert.ErrorContains(t, cluster.checkCertificate(certPEM, otherKeyPEM, caPEM, "prod", now),
+		"the certificate and key do not match")
+	assert.ErrorContains(t, cluster.checkCertificate(certPEM, keyPEM, otherCAPEM, "prod", now),
+		"the certificate is not valid with the CA")
+	assert.ErrorContains(t, cluster.checkCertificate(certPEM, keyPEM, []byte("nope"), "prod", now),
+		"no certificates found in the CA")
+	assert.ErrorContains(t, cluster.checkCertificate(certPEM, keyPEM, caPEM, "prod", now.AddDate(2, 0, 0)),
+		"expired")
+
+	certPEM, keyPEM = leaf("hippo-primary.prod.svc", "hippo-primary.prod.svc")
+	assert.ErrorContains(t, cluster.checkCertificate(certPEM, keyPEM, caPEM, "prod", now),
+		"the certificate does not cover the Service names hippo-replicas.prod.svc")
+	assert.ErrorContains(t, cluster.checkCertificate(certPEM, keyPEM, caPEM, "dev", now),
+		"hippo-primary.dev.svc, hippo-replicas.dev.svc")
+
+	replication := pgTLS{ClusterName: "hippo", Replication: true}
+	certPEM, keyPEM = leaf("_crunchyrepl")
+	assert.NilError(t, replication.checkCertificate(certPEM, keyPEM, caPEM, "prod", now))
+
+	certPEM, keyPEM = leaf("replicator")
+	assert.ErrorContains(t, replication.checkCertificate(certPEM, keyPEM, caPEM, "prod", now),
+		`must have the common name "_crunchyrepl", not "replicator"`)
+}
+
+func TestTLSSecretKeys(t *testing.T) {
+	cluster := new(unstructured.Unstructured)
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+metadata:
+  name: hippo
+spec:
+  customTLSSecret:
+    name: hippo-tls
+    items:
+    - {key: server.crt, path: tls.crt}
+    - {key: server.key, path: tls.key}
+`), &cluster.Object))
+
+	name, keys := tlsCertificates[0].secretKeys(cluster)
+	assert.Equal(t, name, "hippo-tls")
+	assert.DeepEqual(t, keys, map[string]string{"tls.crt": "server.crt", "ca.crt": "ca.crt"})
+
+	name, keys = tlsCertificates[1].secretKeys(cluster)
+	assert.Equal(t, name, "hippo-replication-cert")
+	assert.DeepEqual(t, keys, map[string]string{"tls.crt": "tls.crt", "ca.crt": "ca.crt"})
+}
+
+func TestPrintCertificateRows(t *testing.T) {
+	now := time.Date(2025, time.March, 9, 12, 0, 0, 0, time.UTC)
+
+	ca, caKey, caPEM, _ := testCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "example-ca"},
+		NotBefore:             now.AddDate(-1, 0, 0),
+		NotAfter:              now.AddDate(10, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	_, _, certPEM, _ := testCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "hippo-primary"},
+		DNSNames:     []string{"hippo-primary", "hippo-primary.prod.svc"},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:    now.AddDate(0, -1, 0),
+		NotAfter:     now.AddDate(0, 0, 12),
+	}, ca, caKey)
+
+	_, err := certificateRows("cluster", "hippo-tls", map[string][]byte{"tls.crt": certPEM},
+		map[string]string{"tls.crt": "tls.crt", "ca.crt": "ca.crt"})
+	assert.ErrorContains(t, err, `secrets/hippo-tls "ca.crt": no certificates found`)
+
+	rows, err := certificateRows("cluster", "hippo-tls", map[string][]byte{
+		"server.crt": append(certPEM, caPEM...), "ca.crt": caPEM,
+	}, map[string]string{"tls.crt": "server.crt", "ca.crt": "ca.crt"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(rows), 2)
+
+	rows = append(rows, certificateRow{
+		Certificate: "replication", Secret: "hippo-replication-cert",
+		Subject: "CN=_crunchyrepl", Issuer: "CN=example-ca", NotAfter: now.AddDate(0, 0, -1),
+	})
+
+	var buf bytes.Buffer
+	assert.NilError(t, printCertificateRows(&buf, rows, now, 30))
+	assert.Equal(t, buf.String(), ``+
+		"CERTIFICATE   SECRET                   SUBJECT            ISSUER          SANS                                            EXPIRES\n"+
+		"cluster       hippo-tls                CN=hippo-primary   CN=example-ca   hippo-primary,hippo-primary.prod.svc,10.0.0.1   2025-03-21 (12 days)\n"+
+		"cluster CA    hippo-tls                CN=example-ca      CN=example-ca                                                   2035-03-09 (3652 days)\n"+
+		"replication   hippo-replication-cert   CN=_crunchyrepl    CN=example-ca                                                   expired\n"+
+		"WARNING: The cluster certificate in secrets/hippo-tls expires in 12 days.\n"+
+		"WARNING: The replication certificate in secrets/hippo-replication-cert has expired.\n")
+}
@@ -0,0 +1,498 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newUpgradeCommand returns the upgrade command of the PGO plugin. It drives a
+// PGUpgrade major version upgrade of a PostgresCluster from start to finish.
+func newUpgradeCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade CLUSTER_NAME",
+		Short: "Upgrade the Postgres major version of a cluster",
+		Long: `Upgrade performs a major version upgrade of a PostgresCluster using PGUpgrade.
+
+The upgrade happens in steps:
+    1. Create a PGUpgrade for the PostgresCluster.
+    2. Annotate the PostgresCluster to allow the upgrade.
+    3. Shut down the PostgresCluster, when --shutdown is set.
+    4. Wait for the PGUpgrade and its Job to succeed.
+    5. Set the new Postgres version and image, remove the annotation, and
+       start the PostgresCluster.
+
+Each step checks that the previous one succeeded. When the command is
+interrupted, running it again with the same arguments resumes the upgrade.
+Overwriting fields owned by another client may require the --force-conflicts flag.
+When another client sets the image of the PostgresCluster, --image is required
+so the new Postgres version does not start with an image of the old one.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    jobs.batch                                          [list]
+    pgupgrades.postgres-operator.crunchydata.com        [create get]
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Upgrade the 'hippo' postgrescluster to Postgres 17, shutting it down first
+pgo upgrade hippo --to-version 17 --shutdown
+
+# Upgrade the 'hippo' postgrescluster to a specific Postgres 17 image
+pgo upgrade hippo --to-version 17 --image registry.example.com/crunchy-postgres:ubi9-17.4-2520 --shutdown
+
+### Example output
+WARNING: Upgrading a postgrescluster takes it offline until the upgrade is complete.
+Are you sure you want to upgrade 'hippo' from Postgres 16 to 17? (yes/no): yes
+pgupgrades/hippo-upgrade created
+postgresclusters/hippo annotated
+postgresclusters/hippo stop initiated
+Progressing=True (PGUpgradeProgressing): Upgrading from Postgres 16 to 17
+jobs/hippo-pgupgrade running
+jobs/hippo-pgupgrade succeeded
+Succeeded=True (PGUpgradeSucceeded): Upgraded from Postgres 16 to 17
+postgresclusters/hippo upgraded to Postgres 17 and start initiated`)
+
+	upgrade := pgUpgrade{Config: config}
+
+	cmd.Flags().IntVar(&upgrade.ToVersion, "to-version", 0, "Postgres major version to upgrade to")
+	cobra.CheckErr(cmd.MarkFlagRequired("to-version"))
+
+	cmd.Flags().StringVar(&upgrade.Image, "image", "",
+		"Postgres image of the new version; defaults to the image PGO has for that version. "+
+			"Required when another client sets the image of the cluster")
+	cmd.Flags().StringVar(&upgrade.UpgradeImage, "upgrade-image", "",
+		"Image of the upgrade Job; defaults to the image PGO has for PGUpgrade")
+	cmd.Flags().StringVar(&upgrade.Name, "name", "",
+		"Name of the PGUpgrade; defaults to CLUSTER_NAME-upgrade")
+	cmd.Flags().BoolVar(&upgrade.Shutdown, "shutdown", false,
+		"Shut down the cluster so the upgrade can begin")
+	cmd.Flags().BoolVar(&upgrade.ForceConflicts, "force-conflicts", false,
+		"take ownership and overwrite the version, image, and shutdown settings")
+	cmd.Flags().DurationVar(&upgrade.Timeout, "timeout", 30*time.Minute,
+		"How long to wait for the upgrade to complete")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		upgrade.ClusterName = args[0]
+		if upgrade.Name == "" {
+			upgrade.Name = upgrade.ClusterName + "-upgrade"
+		}
+
+		return upgrade.Run(context.Background())
+	}
+
+	return cmd
+}
+
+type pgUpgrade struct {
+	*internal.Config
+
+	ClusterName    string
+	ForceConflicts bool
+	Image          string
+	Name           string
+	Shutdown       bool
+	Timeout        time.Duration
+	ToVersion      int
+	UpgradeImage   string
+
+	// pollInterval is how often to check the progress of the upgrade.
+	pollInterval time.Duration
+}
+
+// generatePGUpgrade returns a PGUpgrade in the unstructured format.
+func (upgrade pgUpgrade) generatePGUpgrade(fromVersion int64) *unstructured.Unstructured {
+	u := new(unstructured.Unstructured)
+	u.SetGroupVersionKind(v1beta1.GroupVersion.WithKind("PGUpgrade"))
+	u.SetName(upgrade.Name)
+
+	spec := map[string]any{
+		"postgresClusterName": upgrade.ClusterName,
+		"fromPostgresVersion": fromVersion,
+		"toPostgresVersion":   int64(upgrade.ToVersion),
+	}
+	if upgrade.Image != "" {
+		spec["toPostgresImage"] = upgrade.Image
+	}
+	if upgrade.UpgradeImage != "" {
+		spec["image"] = upgrade.UpgradeImage
+	}
+	u.Object["spec"] = spec
+
+	return u
+}
+
+// checkCluster returns an error when cluster cannot be upgraded. It returns the
+// current Postgres version of cluster otherwise.
+func (upgrade pgUpgrade) checkCluster(cluster *unstructured.Unstructured) (int64, error) {
+	current, found, err := unstructured.NestedInt64(cluster.Object, "spec", "postgresVersion")
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("postgrescluster %q has no postgresVersion", upgrade.ClusterName)
+	}
+	if current >= int64(upgrade.ToVersion) {
+		return current, fmt.Errorf("postgrescluster %q is already at Postgres %d; cannot upgrade to %d",
+			upgrade.ClusterName, current, upgrade.ToVersion)
+	}
+
+	if value, ok := cluster.GetAnnotations()[util.AllowUpgradeAnnotation()]; ok && value != upgrade.Name {
+		return current, fmt.Errorf("postgrescluster %q is already annotated for PGUpgrade %q",
+			upgrade.ClusterName, value)
+	}
+
+	shutdown, _, _ := unstructured.NestedBool(cluster.Object, "spec", "shutdown")
+	if !shutdown && !upgrade.Shutdown {
+		return current, fmt.Errorf("postgrescluster %q is running; pass --shutdown or run 'pgo stop %s' first",
+			upgrade.ClusterName, upgrade.ClusterName)
+	}
+
+	return current, upgrade.checkImage(cluster)
+}
+
+// checkImage returns an error when the upgrade would keep an image of the old
+// Postgres version. Without --image the image is removed from the fields of
+// this client, which leaves in place an image set by any other client.
+func (upgrade pgUpgrade) checkImage(cluster *unstructured.Unstructured) error {
+	image, _, _ := unstructured.NestedString(cluster.Object, "spec", "image")
+	if image == "" || upgrade.Image != "" {
+		return nil
+	}
+
+	managers, err := internal.FieldManagers(cluster, "spec", "image")
+	if err != nil {
+		return err
+	}
+	if len(managers) == 1 && managers[0] == upgrade.Patch.FieldManager {
+		return nil
+	}
+	return fmt.Errorf("postgrescluster %q has image %q set by another client; "+
+		"pass --image with a Postgres %d image", upgrade.ClusterName, image, upgrade.ToVersion)
+}
+
+// checkExisting returns an error when an existing PGUpgrade does not match the
+// requested upgrade.
+func (upgrade pgUpgrade) checkExisting(existing *unstructured.Unstructured, fromVersion int64) error {
+	cluster, _, _ := unstructured.NestedString(existing.Object, "spec", "postgresClusterName")
+	from, _, _ := unstructured.NestedInt64(existing.Object, "spec", "fromPostgresVersion")
+	to, _, _ := unstructured.NestedInt64(existing.Object, "spec", "toPostgresVersion")
+
+	if cluster != upgrade.ClusterName || from != fromVersion || to != int64(upgrade.ToVersion) {
+		return fmt.Errorf("pgupgrade %q already exists for postgrescluster %q from %d to %d; "+
+			"delete it or choose another --name", upgrade.Name, cluster, from, to)
+	}
+	return nil
+}
+
+// annotateIntent sets the annotation that allows PGUpgrade to upgrade the cluster.
+func (upgrade pgUpgrade) annotateIntent(intent *unstructured.Unstructured) {
+	intent.SetAnnotations(internal.MergeStringMaps(
+		intent.GetAnnotations(), map[string]string{
+			util.AllowUpgradeAnnotation(): upgrade.Name,
+		}))
+}
+
+// finishIntent sets the new Postgres version and image, removes the upgrade
+// annotation, and starts the cluster.
+func (upgrade pgUpgrade) finishIntent(intent *unstructured.Unstructured) error {
+	annotations := intent.GetAnnotations()
+	delete(annotations, util.AllowUpgradeAnnotation())
+	intent.SetAnnotations(annotations)
+	internal.RemoveEmptySections(intent, "metadata", "annotations")
+
+	if err := unstructured.SetNestedField(intent.Object,
+		int64(upgrade.ToVersion), "spec", "postgresVersion"); err != nil {
+		return err
+	}
+
+	if value, path := upgrade.Image, []string{"spec", "image"}; value == "" {
+		unstructured.RemoveNestedField(intent.Object, path...)
+	} else if err := unstructured.SetNestedField(intent.Object, value, path...); err != nil {
+		return err
+	}
+
+	return unstructured.SetNestedField(intent.Object, false, "spec", "shutdown")
+}
+
+// upgradeProgress interprets the conditions of a PGUpgrade. It returns true
+// when the upgrade has succeeded and an error when it has failed.
+func upgradeProgress(pgupgrade *unstructured.Unstructured) (bool, error) {
+	conditions, _, _ := unstructured.NestedSlice(pgupgrade.Object, "status", "conditions")
+	for i := range conditions {
+		condition, _ := conditions[i].(map[string]any)
+		kind, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+
+		if kind != "Succeeded" {
+			continue
+		}
+
+		switch metav1.ConditionStatus(status) {
+		case metav1.ConditionTrue:
+			return true, nil
+		case metav1.ConditionFalse:
+			reason, _, _ := unstructured.NestedString(condition, "reason")
+			message, _, _ := unstructured.NestedString(condition, "message")
+			return false, fmt.Errorf("pgupgrade %q failed: %s: %s", pgupgrade.GetName(), reason, message)
+		default:
+		}
+	}
+	return false, nil
+}
+
+// describeConditions returns one line for each condition of a PGUpgrade.
+func describeConditions(pgupgrade *unstructured.Unstructured) []string {
+	var lines []string
+	conditions, _, _ := unstructured.NestedSlice(pgupgrade.Object, "status", "conditions")
+	for i := range conditions {
+		condition, _ := conditions[i].(map[string]any)
+		kind, _, _ := unstructured.NestedString(condition, "type")
+		status, _, _ := unstructured.NestedString(condition, "status")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		message, _, _ := unstructured.NestedString(condition, "message")
+
+		lines = append(lines, fmt.Sprintf("%s=%s (%s): %s", kind, status, reason, message))
+	}
+	return lines
+}
+
+// describeJob returns a short description of the state of an upgrade Job and
+// whether or not it has failed.
+func describeJob(job batchv1.Job) (string, bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return "succeeded", false
+		case batchv1.JobFailed:
+			return "failed: " + condition.Message, true
+		default:
+		}
+	}
+	if job.Status.Active > 0 {
+		return "running", false
+	}
+	return "pending", false
+}
+
+func (upgrade pgUpgrade) Run(ctx context.Context) error {
+	mapping, clusterClient, err := v1beta1.NewPostgresClusterClient(upgrade)
+	if err != nil {
+		return err
+	}
+	upgradeMapping, upgradeClient, err := v1beta1.NewPGUpgradeClient(upgrade)
+	if err != nil {
+		return err
+	}
+	rest, err := upgrade.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	batch, err := batchv1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+	namespace, err := upgrade.Namespace()
+	if err != nil {
+		return err
+	}
+
+	clusters := clusterClient.Namespace(namespace)
+	pgupgrades := upgradeClient.Namespace(namespace)
+
+	// Step 0: Check that the cluster can be upgraded.
+	cluster, err := clusters.Get(ctx, upgrade.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	fromVersion, err := upgrade.checkCluster(cluster)
+	if err != nil {
+		return err
+	}
+
+	if !confirm(upgrade.In, upgrade.Out, fmt.Sprintf(
+		"WARNING: Upgrading a postgrescluster takes it offline until the upgrade is complete.\n"+
+			"Are you sure you want to upgrade '%s' from Postgres %d to %d? (yes/no): ",
+		upgrade.ClusterName, fromVersion, upgrade.ToVersion)) {
+		return nil
+	}
+
+	// Step 1: Create the PGUpgrade or resume with an existing one.
+	existing, err := pgupgrades.Get(ctx, upgrade.Name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if err := upgrade.checkExisting(existing, fromVersion); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(upgrade.Out, "%s/%s exists\n", upgradeMapping.Resource.Resource, upgrade.Name)
+
+	case apierrors.IsNotFound(err):
+		if _, err := pgupgrades.Create(ctx, upgrade.generatePGUpgrade(fromVersion),
+			upgrade.Patch.CreateOptions(metav1.CreateOptions{})); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(upgrade.Out, "%s/%s created\n", upgradeMapping.Resource.Resource, upgrade.Name)
+
+	default:
+		return err
+	}
+
+	// Step 2: Annotate the cluster to allow the upgrade.
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, upgrade.Patch.FieldManager); err != nil {
+		return err
+	}
+	upgrade.annotateIntent(intent)
+	if _, err := applyCluster(ctx, upgrade.Config, clusters, upgrade.ClusterName,
+		intent, upgrade.ForceConflicts); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(upgrade.Out, "%s/%s annotated\n", mapping.Resource.Resource, upgrade.ClusterName)
+
+	// Step 3: Shut down the cluster. Fetch it again so the fields extracted
+	// from it include the annotation.
+	if upgrade.Shutdown {
+		if cluster, err = clusters.Get(ctx, upgrade.ClusterName, metav1.GetOptions{}); err != nil {
+			return err
+		}
+
+		msg, err := patchClusterShutdown(cluster, clusterClient, ShutdownRequestArgs{
+			ClusterName:      upgrade.ClusterName,
+			Config:           upgrade.Config,
+			ForceConflicts:   upgrade.ForceConflicts,
+			Namespace:        namespace,
+			NewShutdownValue: true,
+			Mapping:          mapping,
+		})
+		if msg != "" {
+			_, _ = fmt.Fprint(upgrade.Out, msg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Step 4: Wait for the PGUpgrade to succeed, reporting changes to its
+	// conditions and Job along the way.
+	if err := upgrade.wait(ctx, pgupgrades, batch.Jobs(namespace)); err != nil {
+		return err
+	}
+
+	// Step 5: Set the new version, remove the annotation, and start the cluster.
+	if cluster, err = clusters.Get(ctx, upgrade.ClusterName, metav1.GetOptions{}); err != nil {
+		return err
+	}
+	if value := cluster.GetAnnotations()[util.AllowUpgradeAnnotation()]; value != upgrade.Name {
+		return fmt.Errorf("postgrescluster %q is no longer annotated for PGUpgrade %q",
+			upgrade.ClusterName, upgrade.Name)
+	}
+	if err := upgrade.checkImage(cluster); err != nil {
+		return err
+	}
+
+	intent = new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, upgrade.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := upgrade.finishIntent(intent); err != nil {
+		return err
+	}
+	if _, err := applyCluster(ctx, upgrade.Config, clusters, upgrade.ClusterName,
+		intent, upgrade.ForceConflicts); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(upgrade.Out, "%s/%s upgraded to Postgres %d and start initiated\n",
+		mapping.Resource.Resource, upgrade.ClusterName, upgrade.ToVersion)
+
+	return nil
+}
+
+// wait polls the PGUpgrade and its Job until the upgrade succeeds, fails, or
+// the timeout expires.
+func (upgrade pgUpgrade) wait(ctx context.Context,
+	pgupgrades dynamic.ResourceInterface, jobs batchv1client.JobInterface,
+) error {
+	interval := upgrade.pollInterval
+	if interval == 0 {
+		interval = 5 * time.Second
+	}
+
+	reported := map[string]bool{}
+	report := func(line string) {
+		if !reported[line] {
+			reported[line] = true
+			_, _ = fmt.Fprintln(upgrade.Out, line)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, upgrade.Timeout)
+	defer cancel()
+
+	var failed error
+	err := wait.PollImmediateUntilWithContext(ctx, interval, func(ctx context.Context) (bool, error) {
+		list, err := jobs.List(ctx, metav1.ListOptions{
+			LabelSelector: util.LabelPGUpgrade + "=" + upgrade.Name,
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, job := range list.Items {
+			state, jobFailed := describeJob(job)
+			report(fmt.Sprintf("jobs/%s %s", job.Name, state))
+			if jobFailed {
+				failed = fmt.Errorf("upgrade job %q failed; see 'kubectl logs job/%s'", job.Name, job.Name)
+				return true, nil
+			}
+		}
+
+		pgupgrade, err := pgupgrades.Get(ctx, upgrade.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, line := range describeConditions(pgupgrade) {
+			report(line)
+		}
+
+		done, err := upgradeProgress(pgupgrade)
+		failed = err
+		return done || err != nil, nil
+	})
+
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return fmt.Errorf("timed out after %v waiting for pgupgrade %q; "+
+			"run this command again to continue waiting", upgrade.Timeout, upgrade.Name)
+	}
+	if err != nil {
+		return err
+	}
+	return failed
+}
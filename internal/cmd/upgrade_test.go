@@ -0,0 +1,268 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestGeneratePGUpgrade(t *testing.T) {
+	upgrade := pgUpgrade{ClusterName: "hippo", Name: "hippo-upgrade", ToVersion: 17}
+	assert.Assert(t, cmp.MarshalMatches(upgrade.generatePGUpgrade(16), `
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PGUpgrade
+metadata:
+  name: hippo-upgrade
+spec:
+  fromPostgresVersion: 16
+  postgresClusterName: hippo
+  toPostgresVersion: 17
+`))
+
+	upgrade.Image = "postgres:17"
+	upgrade.UpgradeImage = "upgrade:latest"
+	assert.Assert(t, cmp.MarshalMatches(upgrade.generatePGUpgrade(16).Object["spec"], `
+fromPostgresVersion: 16
+image: upgrade:latest
+postgresClusterName: hippo
+toPostgresImage: postgres:17
+toPostgresVersion: 17
+`))
+}
+
+func TestPGUpgradeCheckCluster(t *testing.T) {
+	// Parse numbers the same as the Kubernetes API client, as int64.
+	parse := func(t *testing.T, s string) *unstructured.Unstructured {
+		var u unstructured.Unstructured
+		assert.NilError(t, kyaml.Unmarshal([]byte(s), &u.Object))
+		return &u
+	}
+
+	upgrade := pgUpgrade{ClusterName: "hippo", Name: "hippo-upgrade", ToVersion: 17}
+
+	t.Run("Stopped", func(t *testing.T) {
+		version, err := upgrade.checkCluster(parse(t, `{ spec: { postgresVersion: 16, shutdown: true } }`))
+		assert.NilError(t, err)
+		assert.Equal(t, version, int64(16))
+	})
+
+	t.Run("Running", func(t *testing.T) {
+		cluster := parse(t, `{ spec: { postgresVersion: 16 } }`)
+
+		_, err := upgrade.checkCluster(cluster)
+		assert.ErrorContains(t, err, "pass --shutdown")
+
+		shutdown := upgrade
+		shutdown.Shutdown = true
+		_, err = shutdown.checkCluster(cluster)
+		assert.NilError(t, err)
+	})
+
+	t.Run("SameVersion", func(t *testing.T) {
+		_, err := upgrade.checkCluster(parse(t, `{ spec: { postgresVersion: 17, shutdown: true } }`))
+		assert.ErrorContains(t, err, "already at Postgres 17")
+	})
+
+	t.Run("OtherUpgrade", func(t *testing.T) {
+		_, err := upgrade.checkCluster(parse(t, `{
+			metadata: { annotations: { postgres-operator.crunchydata.com/allow-upgrade: other } },
+			spec: { postgresVersion: 16, shutdown: true },
+		}`))
+		assert.ErrorContains(t, err, `annotated for PGUpgrade "other"`)
+	})
+
+	t.Run("Image", func(t *testing.T) {
+		upgrade := upgrade
+		upgrade.Config = &internal.Config{Patch: internal.PatchConfig{FieldManager: "kubectl-pgo"}}
+
+		managed := func(manager string) *unstructured.Unstructured {
+			return parse(t, `{
+				metadata: { managedFields: [{
+					manager: `+manager+`, operation: Apply, fieldsType: FieldsV1,
+					fieldsV1: { f:spec: { f:image: {} } },
+				}] },
+				spec: { postgresVersion: 16, shutdown: true, image: example.com/postgres:16 },
+			}`)
+		}
+
+		// The image of this client is removed when the upgrade finishes.
+		_, err := upgrade.checkCluster(managed("kubectl-pgo"))
+		assert.NilError(t, err)
+
+		_, err = upgrade.checkCluster(managed("helm"))
+		assert.ErrorContains(t, err, `has image "example.com/postgres:16" set by another client`)
+		assert.ErrorContains(t, err, "pass --image with a Postgres 17 image")
+
+		upgrade.Image = "example.com/postgres:17"
+		_, err = upgrade.checkCluster(managed("helm"))
+		assert.NilError(t, err)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		_, err := upgrade.checkCluster(parse(t, `{
+			metadata: { annotations: { postgres-operator.crunchydata.com/allow-upgrade: hippo-upgrade } },
+			spec: { postgresVersion: 16, shutdown: true },
+		}`))
+		assert.NilError(t, err)
+
+		assert.NilError(t, upgrade.checkExisting(upgrade.generatePGUpgrade(16), 16))
+		assert.ErrorContains(t, upgrade.checkExisting(upgrade.generatePGUpgrade(15), 16),
+			"already exists")
+	})
+}
+
+func TestPGUpgradeIntent(t *testing.T) {
+	upgrade := pgUpgrade{ClusterName: "hippo", Name: "hippo-upgrade", ToVersion: 17}
+
+	var intent unstructured.Unstructured
+	assert.NilError(t, yaml.Unmarshal([]byte(`{ spec: { shutdown: true } }`), &intent.Object))
+
+	upgrade.annotateIntent(&intent)
+	assert.Assert(t, cmp.MarshalMatches(&intent, `
+metadata:
+  annotations:
+    postgres-operator.crunchydata.com/allow-upgrade: hippo-upgrade
+spec:
+  shutdown: true
+`))
+
+	assert.NilError(t, upgrade.finishIntent(&intent))
+	assert.Assert(t, cmp.MarshalMatches(&intent, `
+spec:
+  postgresVersion: 17
+  shutdown: false
+`))
+
+	upgrade.Image = "postgres:17"
+	assert.NilError(t, upgrade.finishIntent(&intent))
+	assert.Assert(t, cmp.MarshalMatches(&intent, `
+spec:
+  image: postgres:17
+  postgresVersion: 17
+  shutdown: false
+`))
+}
+
+func TestUpgradeProgress(t *testing.T) {
+	pgupgrade := func(t *testing.T, conditions string) *unstructured.Unstructured {
+		var u unstructured.Unstructured
+		assert.NilError(t, yaml.Unmarshal([]byte(`{ metadata: { name: up }, status: { conditions: `+conditions+` } }`), &u.Object))
+		return &u
+	}
+
+	done, err := upgradeProgress(pgupgrade(t, `[]`))
+	assert.NilError(t, err)
+	assert.Assert(t, !done)
+
+	done, err = upgradeProgress(pgupgrade(t, `[{ type: Progressing, status: "True" }]`))
+	assert.NilError(t, err)
+	assert.Assert(t, !done)
+
+	done, err = upgradeProgress(pgupgrade(t, `[{ type: Succeeded, status: "True" }]`))
+	assert.NilError(t, err)
+	assert.Assert(t, done)
+
+	_, err = upgradeProgress(pgupgrade(t, `[{ type: Succeeded, status: "False", reason: PGUpgradeFailed, message: oops }]`))
+	assert.ErrorContains(t, err, "PGUpgradeFailed: oops")
+
+	assert.DeepEqual(t, describeConditions(pgupgrade(t,
+		`[{ type: Progressing, status: "False", reason: PGClusterNotShutdown, message: "not shut down" }]`)),
+		[]string{"Progressing=False (PGClusterNotShutdown): not shut down"})
+}
+
+func TestDescribeJob(t *testing.T) {
+	state, failed := describeJob(batchv1.Job{})
+	assert.Equal(t, state, "pending")
+	assert.Assert(t, !failed)
+
+	state, failed = describeJob(batchv1.Job{Status: batchv1.JobStatus{Active: 1}})
+	assert.Equal(t, state, "running")
+	assert.Assert(t, !failed)
+
+	state, failed = describeJob(batchv1.Job{Status: batchv1.JobStatus{
+		Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}},
+	}})
+	assert.Equal(t, state, "succeeded")
+	assert.Assert(t, !failed)
+
+	state, failed = describeJob(batchv1.Job{Status: batchv1.JobStatus{
+		Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded"}},
+	}})
+	assert.Equal(t, state, "failed: BackoffLimitExceeded")
+	assert.Assert(t, failed)
+}
+
+func TestPGUpgradeWait(t *testing.T) {
+	ctx := context.Background()
+
+	var out bytes.Buffer
+	upgrade := pgUpgrade{
+		Config: &internal.Config{
+			IOStreams: genericclioptions.IOStreams{Out: &out},
+		},
+		Name: "hippo-upgrade", Timeout: time.Second, pollInterval: time.Millisecond,
+	}
+
+	var pgupgrade unstructured.Unstructured
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+apiVersion: postgres-operator.crunchydata.com/v1beta1
+kind: PGUpgrade
+metadata: { name: hippo-upgrade, namespace: zoo }
+status:
+  conditions:
+  - { type: Progressing, status: "False", reason: PGUpgradeCompleted, message: done }
+  - { type: Succeeded, status: "True", reason: PGUpgradeSucceeded, message: "Upgraded" }
+`), &pgupgrade.Object))
+
+	pgupgrades := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &pgupgrade).
+		Resource(v1beta1.GroupVersion.WithResource("pgupgrades")).Namespace("zoo")
+
+	jobs := fake.NewSimpleClientset(&batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "zoo", Name: "hippo-pgupgrade",
+			Labels: map[string]string{"postgres-operator.crunchydata.com/pgupgrade": "hippo-upgrade"},
+		},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}},
+	}).BatchV1().Jobs("zoo")
+
+	assert.NilError(t, upgrade.wait(ctx, pgupgrades, jobs))
+	assert.Equal(t, out.String(), strings.TrimLeft(`
+jobs/hippo-pgupgrade succeeded
+Progressing=False (PGUpgradeCompleted): done
+Succeeded=True (PGUpgradeSucceeded): Upgraded
+`, "\n"))
+
+	t.Run("Timeout", func(t *testing.T) {
+		unstructured.RemoveNestedField(pgupgrade.Object, "status")
+		pgupgrades := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &pgupgrade).
+			Resource(v1beta1.GroupVersion.WithResource("pgupgrades")).Namespace("zoo")
+
+		upgrade.Timeout = 10 * time.Millisecond
+		err := upgrade.wait(ctx, pgupgrades, fake.NewSimpleClientset().BatchV1().Jobs("zoo"))
+		assert.ErrorContains(t, err, "timed out")
+	})
+}
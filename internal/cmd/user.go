@@ -0,0 +1,493 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+	"github.com/crunchydata/postgres-operator-client/internal/util"
+)
+
+// newUserCommand returns the user subcommand of the PGO plugin. Subcommands
+// of user manage the users defined in the spec of a PostgresCluster.
+func newUserCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage PostgresCluster users",
+		Long: `Manage the users defined in "spec.users" of a PostgresCluster.
+
+Changes to "spec.users" are sent using server-side apply, so only the users
+created by this command can be changed or deleted by it. Overwriting fields
+owned by another client may require the --force-conflicts flag.`,
+	}
+
+	cmd.AddCommand(
+		newUserCreateCommand(config),
+		newUserUpdateCommand(config),
+		newUserDeleteCommand(config),
+		newUserRotatePasswordCommand(config),
+	)
+
+	return cmd
+}
+
+// addUserSpecFlags adds flags for the fields of a user spec to cmd.
+func addUserSpecFlags(cmd *cobra.Command, user *pgUser) {
+	cmd.Flags().StringVarP(&user.ClusterName, "cluster", "c", "", "Set the Postgres cluster name (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("cluster"))
+
+	cmd.Flags().BoolVar(&user.ForceConflicts, "force-conflicts", false, "take ownership and overwrite the user settings")
+	cmd.Flags().StringSlice("databases", nil, "databases the user can access; can be used multiple times")
+	cmd.Flags().String("options", "", `role attributes of the user, such as "CREATEDB"`)
+
+	passwordType := util.DefaultPassword
+	cmd.Flags().Var(&passwordType, "password-type", `characters in the generated password: "ASCII" or "AlphaNumeric"`)
+}
+
+// readUserSpecFlags assigns the user spec flags that were set on the command
+// line to user.
+func readUserSpecFlags(cmd *cobra.Command, user *pgUser) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("databases") {
+		databases, err := flags.GetStringSlice("databases")
+		if err != nil {
+			return err
+		}
+		user.Databases = &databases
+	}
+	if flags.Changed("options") {
+		options, err := flags.GetString("options")
+		if err != nil {
+			return err
+		}
+		user.Options = &options
+	}
+	if flags.Changed("password-type") {
+		passwordType := flags.Lookup("password-type").Value.String()
+		user.PasswordType = &passwordType
+	}
+
+	return nil
+}
+
+func newUserCreateCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create USER_NAME --cluster CLUSTER_NAME",
+		Short: "Add a user to a PostgresCluster",
+		Long: `Add a user to the "spec.users" of a PostgresCluster. PGO creates the role
+in Postgres and a Secret containing its connection information.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Add user 'rhino' that can access the 'zoo' database of the 'hippo' postgrescluster
+pgo user create rhino --cluster hippo --databases zoo
+
+# Add user 'rhino' that can create databases
+pgo user create rhino --cluster hippo --databases zoo,aquarium --options "CREATEDB"
+
+### Example output
+postgresclusters/hippo user rhino created`)
+
+	user := pgUser{Config: config}
+	addUserSpecFlags(cmd, &user)
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		user.UserName = args[0]
+		if err := readUserSpecFlags(cmd, &user); err != nil {
+			return err
+		}
+		return user.Create(context.Background())
+	}
+
+	return cmd
+}
+
+func newUserUpdateCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update USER_NAME --cluster CLUSTER_NAME",
+		Short: "Change a user of a PostgresCluster",
+		Long: `Change a user in the "spec.users" of a PostgresCluster. Only the fields
+of flags passed on the command line are changed; an empty value removes the field.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Allow user 'rhino' to access only the 'aquarium' database
+pgo user update rhino --cluster hippo --databases aquarium
+
+# Remove the role attributes of user 'rhino'
+pgo user update rhino --cluster hippo --options ""
+
+### Example output
+postgresclusters/hippo user rhino updated`)
+
+	user := pgUser{Config: config}
+	addUserSpecFlags(cmd, &user)
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		user.UserName = args[0]
+		if err := readUserSpecFlags(cmd, &user); err != nil {
+			return err
+		}
+		return user.Update(context.Background())
+	}
+
+	return cmd
+}
+
+func newUserDeleteCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete USER_NAME --cluster CLUSTER_NAME",
+		Short: "Remove a user from a PostgresCluster",
+		Long: `Remove a user from the "spec.users" of a PostgresCluster. PGO deletes the
+Secret of the user but does not drop its role or objects in Postgres.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    postgresclusters.postgres-operator.crunchydata.com  [get patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Remove user 'rhino' from the 'hippo' postgrescluster
+pgo user delete rhino --cluster hippo
+
+### Example output
+WARNING: Applications using user rhino will no longer be able to read its Secret.
+Are you sure you want to continue? (yes/no): yes
+postgresclusters/hippo user rhino deleted`)
+
+	user := pgUser{Config: config}
+
+	cmd.Flags().StringVarP(&user.ClusterName, "cluster", "c", "", "Set the Postgres cluster name (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("cluster"))
+	cmd.Flags().BoolVar(&user.ForceConflicts, "force-conflicts", false, "take ownership and remove the user")
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		user.UserName = args[0]
+		return user.Delete(context.Background())
+	}
+
+	return cmd
+}
+
+func newUserRotatePasswordCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-password USER_NAME --cluster CLUSTER_NAME",
+		Short: "Generate a new password for a PostgresCluster user",
+		Long: `Remove the password and verifier from the Secret of a PostgresCluster user.
+PGO generates a new password, updates the role in Postgres, and updates the
+connection information in the Secret.
+
+### RBAC Requirements
+    Resources  Verbs
+    ---------  -----
+    secrets    [list patch]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Generate a new password for user 'rhino' of the 'hippo' postgrescluster
+pgo user rotate-password rhino --cluster hippo
+
+### Example output
+WARNING: Applications using the current password of user rhino will no longer be able to connect.
+Are you sure you want to continue? (yes/no): yes
+secrets/hippo-pguser-rhino password cleared; PGO will generate a new one`)
+
+	user := pgUser{Config: config}
+
+	cmd.Flags().StringVarP(&user.ClusterName, "cluster", "c", "", "Set the Postgres cluster name (required)")
+	cobra.CheckErr(cmd.MarkFlagRequired("cluster"))
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		user.UserName = args[0]
+		return user.RotatePassword(context.Background())
+	}
+
+	return cmd
+}
+
+type pgUser struct {
+	*internal.Config
+
+	ClusterName    string
+	ForceConflicts bool
+	UserName       string
+
+	// Fields of the user spec to change. Nil fields are left as they are and
+	// empty fields are removed.
+	Databases    *[]string
+	Options      *string
+	PasswordType *string
+}
+
+// userIndex returns the position of the user named name in users, or -1.
+func userIndex(users []any, name string) int {
+	for i := range users {
+		user, _ := users[i].(map[string]any)
+		if user["name"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// clusterHasUser returns whether or not the spec of cluster defines the user.
+func (user pgUser) clusterHasUser(cluster *unstructured.Unstructured) bool {
+	users, _, _ := unstructured.NestedSlice(cluster.Object, "spec", "users")
+	return userIndex(users, user.UserName) >= 0
+}
+
+// setIntent adds the user to intent, when necessary, and assigns the fields
+// that are set on user.
+func (user pgUser) setIntent(intent *unstructured.Unstructured) error {
+	users, _, err := unstructured.NestedSlice(intent.Object, "spec", "users")
+	if err != nil {
+		return err
+	}
+
+	index := userIndex(users, user.UserName)
+	if index < 0 {
+		users = append(users, map[string]any{"name": user.UserName})
+		index = len(users) - 1
+	}
+	spec, _ := users[index].(map[string]any)
+
+	if user.Databases != nil {
+		databases := make([]any, 0, len(*user.Databases))
+		for _, database := range *user.Databases {
+			databases = append(databases, database)
+		}
+		if len(databases) == 0 {
+			delete(spec, "databases")
+		} else {
+			spec["databases"] = databases
+		}
+	}
+	if user.Options != nil {
+		if *user.Options == "" {
+			delete(spec, "options")
+		} else {
+			spec["options"] = *user.Options
+		}
+	}
+	if user.PasswordType != nil {
+		if *user.PasswordType == "" {
+			delete(spec, "password")
+		} else {
+			spec["password"] = map[string]any{"type": *user.PasswordType}
+		}
+	}
+
+	users[index] = spec
+	return unstructured.SetNestedSlice(intent.Object, users, "spec", "users")
+}
+
+// removeIntent removes the user from intent. It returns false when intent
+// does not have the user.
+func (user pgUser) removeIntent(intent *unstructured.Unstructured) bool {
+	users, _, _ := unstructured.NestedSlice(intent.Object, "spec", "users")
+
+	index := userIndex(users, user.UserName)
+	if index < 0 {
+		return false
+	}
+
+	users = append(users[:index], users[index+1:]...)
+	if len(users) == 0 {
+		unstructured.RemoveNestedField(intent.Object, "spec", "users")
+		internal.RemoveEmptySections(intent, "spec")
+	} else {
+		_ = unstructured.SetNestedSlice(intent.Object, users, "spec", "users")
+	}
+	return true
+}
+
+// Create adds the user to the cluster and fails when it is already there.
+func (user pgUser) Create(ctx context.Context) error {
+	return user.modify(ctx, false, "created")
+}
+
+// Update changes the user of the cluster and fails when it is not there.
+func (user pgUser) Update(ctx context.Context) error {
+	return user.modify(ctx, true, "updated")
+}
+
+func (user pgUser) modify(ctx context.Context, exists bool, verb string) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(user)
+	if err != nil {
+		return err
+	}
+	namespace, err := user.Namespace()
+	if err != nil {
+		return err
+	}
+
+	// Fetch the cluster to (1) see if the user exists and (2) extract CLI managed fields.
+	cluster, err := client.Namespace(namespace).Get(ctx, user.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if found := user.clusterHasUser(cluster); found && !exists {
+		return fmt.Errorf("user %q already exists in %s/%s; use \"pgo user update\" to change it",
+			user.UserName, mapping.Resource.Resource, user.ClusterName)
+	} else if !found && exists {
+		return fmt.Errorf("user %q not found in %s/%s",
+			user.UserName, mapping.Resource.Resource, user.ClusterName)
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, user.Patch.FieldManager); err != nil {
+		return err
+	}
+	if err := user.setIntent(intent); err != nil {
+		return err
+	}
+
+	if _, err := applyCluster(ctx, user.Config, client.Namespace(namespace), user.ClusterName,
+		intent, user.ForceConflicts); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(user.Out, "%s/%s user %s %s\n",
+		mapping.Resource.Resource, user.ClusterName, user.UserName, verb)
+	return nil
+}
+
+// Delete removes the user from the fields of the cluster managed by the CLI.
+func (user pgUser) Delete(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(user)
+	if err != nil {
+		return err
+	}
+	namespace, err := user.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, user.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !user.clusterHasUser(cluster) {
+		return fmt.Errorf("user %q not found in %s/%s",
+			user.UserName, mapping.Resource.Resource, user.ClusterName)
+	}
+
+	intent := new(unstructured.Unstructured)
+	if err := internal.ExtractFieldsInto(cluster, intent, user.Patch.FieldManager); err != nil {
+		return err
+	}
+	if !user.removeIntent(intent) {
+		return fmt.Errorf("user %q of %s/%s was not created by pgo; remove it from \"spec.users\" with the client that added it",
+			user.UserName, mapping.Resource.Resource, user.ClusterName)
+	}
+
+	if !confirm(user.In, user.Out, fmt.Sprintf("WARNING: Applications using user %s will no longer be able to read its Secret."+
+		"\nAre you sure you want to continue? (yes/no): ", user.UserName)) {
+		return nil
+	}
+
+	cluster, err = applyCluster(ctx, user.Config, client.Namespace(namespace), user.ClusterName,
+		intent, user.ForceConflicts)
+	if err != nil {
+		return err
+	}
+
+	// Another client may also have applied this user.
+	if user.clusterHasUser(cluster) {
+		_, _ = fmt.Fprintf(user.Out, "WARNING: user %s is still defined by another client of %s/%s\n",
+			user.UserName, mapping.Resource.Resource, user.ClusterName)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(user.Out, "%s/%s user %s deleted\n",
+		mapping.Resource.Resource, user.ClusterName, user.UserName)
+	return nil
+}
+
+// RotatePassword removes the password and verifier from the Secret of the
+// user so that PGO generates new ones.
+func (user pgUser) RotatePassword(ctx context.Context) error {
+	namespace, err := user.Namespace()
+	if err != nil {
+		return err
+	}
+	rest, err := user.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	client, err := corev1client.NewForConfig(rest)
+	if err != nil {
+		return err
+	}
+
+	secret, err := user.findSecret(ctx, client.Secrets(namespace))
+	if err != nil {
+		return err
+	}
+
+	if !confirm(user.In, user.Out, fmt.Sprintf("WARNING: Applications using the current password of user %s will no longer be able to connect."+
+		"\nAre you sure you want to continue? (yes/no): ", user.UserName)) {
+		return nil
+	}
+
+	if err := user.clearPassword(ctx, client.Secrets(namespace), secret); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(user.Out, "secrets/%s password cleared; PGO will generate a new one\n", secret)
+	return nil
+}
+
+// findSecret returns the name of the Secret that PGO maintains for the user.
+func (user pgUser) findSecret(ctx context.Context, secrets corev1client.SecretInterface) (string, error) {
+	list, err := secrets.List(ctx, metav1.ListOptions{
+		LabelSelector: util.PostgresUserSecretLabels(user.ClusterName) +
+			"," + util.LabelPostgresUser + "=" + user.UserName,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(list.Items) == 0 {
+		return "", fmt.Errorf("no Secret found for user %q of cluster %q", user.UserName, user.ClusterName)
+	}
+	return list.Items[0].Name, nil
+}
+
+// clearPassword removes the password and verifier from the named Secret.
+func (user pgUser) clearPassword(ctx context.Context, secrets corev1client.SecretInterface, name string) error {
+	_, err := secrets.Patch(ctx, name, types.MergePatchType,
+		[]byte(`{"data":{"password":null,"verifier":null}}`),
+		user.Patch.PatchOptions(metav1.PatchOptions{}))
+	return err
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/testing/cmp"
+)
+
+func TestPGUserSetIntent(t *testing.T) {
+	databases := []string{"zoo", "aquarium"}
+	options := "CREATEDB"
+	passwordType := "AlphaNumeric"
+
+	var intent unstructured.Unstructured
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+spec:
+  shutdown: false
+  users:
+  - name: hippo
+`), &intent.Object))
+
+	t.Run("Create", func(t *testing.T) {
+		user := pgUser{
+			UserName:  "rhino",
+			Databases: &databases, Options: &options, PasswordType: &passwordType,
+		}
+		assert.NilError(t, user.setIntent(&intent))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object["spec"], `
+shutdown: false
+users:
+- name: hippo
+- databases:
+  - zoo
+  - aquarium
+  name: rhino
+  options: CREATEDB
+  password:
+    type: AlphaNumeric
+`))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		empty := ""
+		user := pgUser{UserName: "rhino", Databases: &[]string{"zoo"}, Options: &empty}
+		assert.NilError(t, user.setIntent(&intent))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object["spec"], `
+shutdown: false
+users:
+- name: hippo
+- databases:
+  - zoo
+  name: rhino
+  password:
+    type: AlphaNumeric
+`))
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		user := pgUser{UserName: "rhino"}
+		assert.Assert(t, user.removeIntent(&intent))
+		assert.Assert(t, !user.removeIntent(&intent))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object["spec"], `
+shutdown: false
+users:
+- name: hippo
+`))
+
+		user.UserName = "hippo"
+		assert.Assert(t, user.removeIntent(&intent))
+		assert.Assert(t, cmp.MarshalMatches(intent.Object["spec"], `
+shutdown: false
+`))
+	})
+}
+
+func TestPGUserClusterHasUser(t *testing.T) {
+	var cluster unstructured.Unstructured
+	assert.NilError(t, yaml.Unmarshal([]byte(`{ spec: { users: [{ name: hippo }] } }`), &cluster.Object))
+
+	assert.Assert(t, pgUser{UserName: "hippo"}.clusterHasUser(&cluster))
+	assert.Assert(t, !pgUser{UserName: "rhino"}.clusterHasUser(&cluster))
+	assert.Assert(t, !pgUser{UserName: "rhino"}.clusterHasUser(&unstructured.Unstructured{}))
+}
+
+func TestPGUserRotatePassword(t *testing.T) {
+	ctx := context.Background()
+	secrets := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "zoo", Name: "hippo-pguser-rhino",
+			Labels: map[string]string{
+				"postgres-operator.crunchydata.com/cluster": "hippo",
+				"postgres-operator.crunchydata.com/pguser":  "rhino",
+				"postgres-operator.crunchydata.com/role":    "pguser",
+			},
+		},
+		Data: map[string][]byte{
+			"user":     []byte("rhino"),
+			"password": []byte("secret"),
+			"verifier": []byte("SCRAM-SHA-256$..."),
+		},
+	}).CoreV1().Secrets("zoo")
+
+	user := pgUser{Config: &internal.Config{}, ClusterName: "hippo", UserName: "rhino"}
+
+	name, err := user.findSecret(ctx, secrets)
+	assert.NilError(t, err)
+	assert.Equal(t, name, "hippo-pguser-rhino")
+
+	assert.NilError(t, user.clearPassword(ctx, secrets, name))
+
+	secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, secret.Data, map[string][]byte{"user": []byte("rhino")})
+
+	t.Run("NotFound", func(t *testing.T) {
+		user := user
+		user.UserName = "zebra"
+		_, err := user.findSecret(ctx, secrets)
+		assert.ErrorContains(t, err, `no Secret found for user "zebra"`)
+	})
+}
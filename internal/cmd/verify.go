@@ -0,0 +1,251 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/crunchydata/postgres-operator-client/internal"
+	"github.com/crunchydata/postgres-operator-client/internal/apis/postgres-operator.crunchydata.com/v1beta1"
+)
+
+func newBackupVerifyCommand(config *internal.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify CLUSTER_NAME",
+		Short: "Verify the integrity of the backups of a PostgresCluster",
+		Long: `Verify runs "pgbackrest check" on the primary to confirm that WAL can be
+archived to each repository. It then runs "pgbackrest verify" on the repository
+host, or on the primary when there is none, to confirm the checksums of the
+backups and WAL in each repository.
+
+The result is a pass or fail for each repository followed by the files that are
+missing or corrupt. Verify exits with status 0 when every repository passes,
+2 when any repository fails, and 1 when the check could not run, such as when
+the cluster cannot be reached. This lets a monitoring CronJob tell a failed
+backup from a failed check.
+
+### RBAC Requirements
+    Resources                                           Verbs
+    ---------                                           -----
+    pods                                                [list]
+    pods/exec                                           [create]
+    postgresclusters.postgres-operator.crunchydata.com  [get]
+
+### Usage`,
+	}
+
+	cmd.Example = internal.FormatExample(`# Verify every repository of the 'hippo' postgrescluster
+pgo backup verify hippo
+
+### Example output
+REPO    CHECK   VERIFY
+repo1   pass    pass
+repo2   pass    fail
+repo2: invalid checksum 'backup/db/20250302-010002F/pg_data/base/5/16384.gz'
+Error: backup verification failed: repo2`)
+
+	verify := pgBackRestVerify{Config: config}
+
+	cmd.Flags().StringVar(&verify.RepoName, "repo", "", "only verify this repository, such as 1 or repo1")
+
+	// Only one positional argument: the PostgresCluster name.
+	cmd.Args = cobra.ExactArgs(1)
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		verify.ClusterName = args[0]
+		return verify.Run(context.Background())
+	}
+
+	return cmd
+}
+
+// BackupVerificationError is returned when the backups of one or more
+// repositories fail verification. It is not returned when verification
+// cannot run at all.
+type BackupVerificationError struct {
+	Repos []string
+}
+
+func (e BackupVerificationError) Error() string {
+	return "backup verification failed: " + strings.Join(e.Repos, ", ")
+}
+
+type pgBackRestVerify struct {
+	*internal.Config
+
+	ClusterName string
+	RepoName    string
+}
+
+// repoVerification is the result of checking and verifying one repository.
+type repoVerification struct {
+	Repo     string
+	Check    bool
+	Verify   bool
+	Problems []string
+}
+
+var (
+	// verifyFilePattern matches the log messages of "pgbackrest verify" about
+	// files that are missing or corrupt.
+	// - https://github.com/pgbackrest/pgbackrest/blob/main/src/command/verify/verify.c
+	verifyFilePattern = regexp.MustCompile(`(file missing|invalid checksum|invalid size|invalid result[^']*) '[^']+'`)
+
+	// verifyStatusPattern matches the status in the text output of "pgbackrest verify".
+	verifyStatusPattern = regexp.MustCompile(`(?m)^\s*status: (\S+)`)
+
+	// errorPattern matches the error messages of pgBackRest.
+	errorPattern = regexp.MustCompile(`ERROR: \[\d+\]: .*`)
+
+	// repoPattern matches the names of repositories in messages.
+	repoPattern = regexp.MustCompile(`\brepo\d\b`)
+)
+
+// parseCheck returns whether or not the output of "pgbackrest check" passed
+// for repo and the errors that apply to it. Errors that do not name a
+// repository apply to every repository.
+func parseCheck(output string, failed bool, repo string) (bool, []string) {
+	var problems []string
+	messages := errorPattern.FindAllString(output, -1)
+	for _, message := range messages {
+		names := repoPattern.FindAllString(message, -1)
+		if len(names) == 0 || slices.Contains(names, repo) {
+			problems = append(problems, "check: "+strings.TrimSpace(message))
+		}
+	}
+	if failed && len(messages) == 0 {
+		problems = append(problems, "check: pgbackrest check failed")
+	}
+	return len(problems) == 0, problems
+}
+
+// parseVerify returns whether or not the output of "pgbackrest verify" passed
+// and the files that are missing or corrupt.
+func parseVerify(output string, failed bool) (bool, []string) {
+	problems := verifyFilePattern.FindAllString(output, -1)
+	for _, message := range errorPattern.FindAllString(output, -1) {
+		problems = append(problems, strings.TrimSpace(message))
+	}
+
+	passed := !failed && len(problems) == 0
+	for _, match := range verifyStatusPattern.FindAllStringSubmatch(output, -1) {
+		if match[1] != "ok" {
+			passed = false
+		}
+	}
+	if !passed && len(problems) == 0 {
+		problems = append(problems, "pgbackrest verify failed")
+	}
+	return passed, problems
+}
+
+func printVerifications(w io.Writer, results []repoVerification) error {
+	result := func(passed bool) string {
+		if passed {
+			return "pass"
+		}
+		return "fail"
+	}
+
+	var buf bytes.Buffer
+	p := printers.GetNewTabWriter(&buf)
+	if _, err := fmt.Fprintf(p, "REPO\tCHECK\tVERIFY\n"); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if _, err := fmt.Fprintf(p, "%s\t%s\t%s\n", r.Repo, result(r.Check), result(r.Verify)); err != nil {
+			return err
+		}
+	}
+	if err := p.Flush(); err != nil {
+		return err
+	}
+	for _, r := range results {
+		for _, problem := range r.Problems {
+			_, _ = fmt.Fprintf(&buf, "%s: %s\n", r.Repo, problem)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Run checks and verifies each repository, prints the results, and returns a
+// BackupVerificationError when any repository fails.
+func (verify pgBackRestVerify) Run(ctx context.Context) error {
+	mapping, client, err := v1beta1.NewPostgresClusterClient(verify)
+	if err != nil {
+		return err
+	}
+	namespace, err := verify.Namespace()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := client.Namespace(namespace).Get(ctx, verify.ClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	var repos []string
+	for _, repo := range clusterRepos(cluster) {
+		name, _ := repo["name"].(string)
+		if verify.RepoName == "" || name == "repo"+strings.TrimPrefix(verify.RepoName, "repo") {
+			repos = append(repos, name)
+		}
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("repository %q not found in %s/%s",
+			verify.RepoName, mapping.Resource.Resource, verify.ClusterName)
+	}
+
+	primary, err := getPrimaryExec(verify.Config, []string{verify.ClusterName})
+	if err != nil {
+		return err
+	}
+	stdout, stderr, err := Executor(primary).pgBackRestCheck()
+	checkOutput, checkFailed := stdout+stderr, err != nil
+
+	backup, err := getBackupExec(ctx, verify.Config, verify.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	var results []repoVerification
+	var failed []string
+	for _, repo := range repos {
+		r := repoVerification{Repo: repo}
+
+		var checkProblems, verifyProblems []string
+		r.Check, checkProblems = parseCheck(checkOutput, checkFailed, repo)
+
+		stdout, stderr, err := Executor(backup).pgBackRestVerify(strings.TrimPrefix(repo, "repo"))
+		r.Verify, verifyProblems = parseVerify(stdout+stderr, err != nil)
+
+		r.Problems = append(checkProblems, verifyProblems...)
+		results = append(results, r)
+		if !r.Check || !r.Verify {
+			failed = append(failed, repo)
+		}
+	}
+
+	if err := printVerifications(verify.Out, results); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return BackupVerificationError{Repos: failed}
+	}
+	return nil
+}
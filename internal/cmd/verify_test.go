@@ -0,0 +1,104 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseCheck(t *testing.T) {
+	passed, problems := parseCheck(`
+2025-03-09 14:30:00.000 P00   INFO: check command begin 2.54.2: --log-level-console=detail
+2025-03-09 14:30:00.100 P00   INFO: check repo1 configuration (primary)
+2025-03-09 14:30:00.200 P00   INFO: check repo2 configuration (primary)
+2025-03-09 14:30:01.300 P00   INFO: check command end: completed successfully
+`, false, "repo1")
+	assert.Assert(t, passed)
+	assert.Assert(t, problems == nil)
+
+	output := `
+2025-03-09 14:30:00.100 P00   INFO: check repo1 configuration (primary)
+2025-03-09 14:31:00.300 P00  ERROR: [082]: WAL segment 000000010000000000000032 was not archived before the 60000ms timeout
+                                    HINT: check the archive_command to ensure that all options are correct (especially --stanza).
+2025-03-09 14:31:00.400 P00  ERROR: [104]: repo2: unable to access bucket
+`
+	passed, problems = parseCheck(output, true, "repo1")
+	assert.Assert(t, !passed)
+	assert.DeepEqual(t, problems, []string{
+		"check: ERROR: [082]: WAL segment 000000010000000000000032 was not archived before the 60000ms timeout",
+	})
+
+	passed, problems = parseCheck(output, true, "repo2")
+	assert.Assert(t, !passed)
+	assert.Equal(t, len(problems), 2)
+
+	passed, problems = parseCheck("command terminated with exit code 137", true, "repo1")
+	assert.Assert(t, !passed)
+	assert.DeepEqual(t, problems, []string{"check: pgbackrest check failed"})
+}
+
+func TestParseVerify(t *testing.T) {
+	passed, problems := parseVerify(`
+stanza: db
+status: ok
+  archiveId: 16-1, total WAL checked: 42, total valid WAL: 42
+  backup: 20250302-010002F, status: valid, total files checked: 1021, total valid files: 1021
+`, false)
+	assert.Assert(t, passed)
+	assert.Assert(t, problems == nil)
+
+	passed, problems = parseVerify(`
+2025-03-09 14:30:02.000 P01   INFO: invalid checksum 'backup/db/20250302-010002F/pg_data/base/5/16384.gz'
+2025-03-09 14:30:02.100 P01   INFO: file missing 'archive/db/16-1/0000000100000000/000000010000000000000011-2b1c.gz'
+stanza: db
+status: error
+  archiveId: 16-1, total WAL checked: 42, total valid WAL: 41
+    missing: 1, checksum invalid: 0, size invalid: 0, other: 0
+  backup: 20250302-010002F, status: invalid, total files checked: 1021, total valid files: 1020
+    missing: 0, checksum invalid: 1, size invalid: 0, other: 0
+`, false)
+	assert.Assert(t, !passed)
+	assert.DeepEqual(t, problems, []string{
+		"invalid checksum 'backup/db/20250302-010002F/pg_data/base/5/16384.gz'",
+		"file missing 'archive/db/16-1/0000000100000000/000000010000000000000011-2b1c.gz'",
+	})
+
+	passed, problems = parseVerify("status: error\n", false)
+	assert.Assert(t, !passed)
+	assert.DeepEqual(t, problems, []string{"pgbackrest verify failed"})
+
+	passed, problems = parseVerify("P00  ERROR: [055]: unable to load info file\n", true)
+	assert.Assert(t, !passed)
+	assert.DeepEqual(t, problems, []string{"ERROR: [055]: unable to load info file"})
+}
+
+func TestPrintVerifications(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NilError(t, printVerifications(&buf, []repoVerification{
+		{Repo: "repo1", Check: true, Verify: true},
+		{Repo: "repo2", Check: true, Problems: []string{
+			"invalid checksum 'backup/db/20250302-010002F/pg_data/base/5/16384.gz'",
+		}},
+	}))
+	assert.Equal(t, buf.String(), ``+
+		"REPO    CHECK   VERIFY\n"+
+		"repo1   pass    pass\n"+
+		"repo2   pass    fail\n"+
+		"repo2: invalid checksum 'backup/db/20250302-010002F/pg_data/base/5/16384.gz'\n")
+}
+
+func TestBackupVerificationError(t *testing.T) {
+	var err error = fmt.Errorf("wrapped: %w", BackupVerificationError{Repos: []string{"repo1", "repo2"}})
+	assert.ErrorContains(t, err, "backup verification failed: repo1, repo2")
+
+	var verification BackupVerificationError
+	assert.Assert(t, errors.As(err, &verification))
+	assert.DeepEqual(t, verification.Repos, []string{"repo1", "repo2"})
+}
@@ -7,10 +7,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
-	v1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crunchydata/postgres-operator-client/internal"
 )
@@ -24,6 +23,16 @@ func newVersionCommand(config *internal.Config) *cobra.Command {
 		Short: "PGO client and operator versions",
 		Long: `Version displays the versions of the PGO client and the Crunchy Postgres Operator
 
+### Compatibility
+    Before other commands run, the client reads the operator version of the
+    current kubeconfig context and remembers it for an hour, along with the
+    cluster and server of the context. The client warns when the operator is
+    older than it supports, and refuses commands and flags that need a newer
+    operator. Nothing is checked when the version
+    cannot be read, such as without the RBAC below.
+
+    Use --check to compare the operator with the versions this client supports.
+
 ### RBAC Requirements
     Resources                                       Verbs
     ---------                                       -----
@@ -40,12 +49,28 @@ func newVersionCommand(config *internal.Config) *cobra.Command {
 	var clientOnly bool
 	cmd.Flags().BoolVar(&clientOnly, "client", false, "If true, shows client version only (no server required).")
 
+	var check bool
+	cmd.Flags().BoolVar(&check, "check", false,
+		"If true, compares the operator version with the versions this client supports.")
+	cmd.MarkFlagsMutuallyExclusive("client", "check")
+
 	cmd.Example = internal.FormatExample(fmt.Sprintf(`# Request the version of the client and the operator
 pgo version
 
 ### Example output
 Client Version: %s
-Operator Version: v5.7.0`, clientVersion))
+Operator Version: v5.7.0
+
+# Check that the operator is supported by this client
+pgo version --check
+
+### Example output
+Client Version: %s
+Operator Version: v5.6.1
+Supported Operator Versions: %s
+Operator v5.6.1 is supported. Upgrade the operator to use:
+    create postgrescluster --disable-backups (v5.7.0 or later)`,
+		clientVersion, clientVersion, supportedOperatorVersions()))
 
 	cmd.RunE = func(cmd *cobra.Command, args []string) error {
 
@@ -55,32 +80,61 @@ Operator Version: v5.7.0`, clientVersion))
 		}
 
 		ctx := context.Background()
-		restConfig, err := config.ToRESTConfig()
+		label, err := getOperatorVersion(ctx, config)
 		if err != nil {
 			return err
 		}
-		// get a client capable of retrieving the PostgresCluster CRD
-		client, err := v1.NewForConfig(restConfig)
-		if err != nil {
-			return err
-		}
-		crd, err := client.CustomResourceDefinitions().
-			Get(ctx, "postgresclusters.postgres-operator.crunchydata.com", metav1.GetOptions{})
-		if err != nil {
-			return err
+
+		if label == "" {
+			cmd.Println("Operator version not found.")
+			return nil
 		}
+		cmd.Printf("Operator Version: v%s\n", label)
 
-		if crd != nil &&
-			crd.Labels != nil &&
-			crd.Labels["app.kubernetes.io/version"] != "" {
+		if !check {
+			return nil
+		}
 
-			cmd.Printf("Operator Version: v%s\n", crd.Labels["app.kubernetes.io/version"])
-		} else {
-			cmd.Println("Operator version not found.")
+		// Refresh the version used by other commands.
+		if cache, err := newOperatorVersionCache(); err == nil {
+			_ = cache.Set(operatorVersionKey(config), label)
 		}
 
+		version, err := parseOperatorVersion(label)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Supported Operator Versions: %s\n", supportedOperatorVersions())
+		printOperatorCompatibility(cmd.OutOrStdout(), version)
 		return nil
 	}
 
 	return cmd
 }
+
+// printOperatorCompatibility writes whether version is supported and the
+// features it does not have.
+func printOperatorCompatibility(w io.Writer, version operatorVersion) {
+	latest := operatorVersion{latestOperatorVersion.Major, latestOperatorVersion.Minor + 1, 0}
+	features := unsupportedFeatures(version)
+
+	switch {
+	case version.Less(minimumOperatorVersion):
+		_, _ = fmt.Fprintf(w, "WARNING: Operator %s is older than this client supports. "+
+			"Upgrade the operator to %s or later.\n", version, minimumOperatorVersion)
+	case !version.Less(latest):
+		_, _ = fmt.Fprintf(w, "WARNING: Operator %s is newer than this client supports. "+
+			"Upgrade the client.\n", version)
+	case len(features) == 0:
+		_, _ = fmt.Fprintf(w, "Operator %s is supported.\n", version)
+	default:
+		_, _ = fmt.Fprintf(w, "Operator %s is supported. Upgrade the operator to use:\n", version)
+	}
+
+	if version.Less(minimumOperatorVersion) && len(features) > 0 {
+		_, _ = fmt.Fprintln(w, "The operator also lacks:")
+	}
+	for _, feature := range features {
+		_, _ = fmt.Fprintf(w, "    %s (%s or later)\n", feature.Usage(), feature.Minimum)
+	}
+}
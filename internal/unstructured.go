@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"slices"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -155,6 +156,39 @@ func extractDepthFirst(src interface{}, paths *fieldpath.Set) (interface{}, erro
 	return result, err
 }
 
+// FieldManagers returns the managers of the main resource (not a subresource)
+// of object that own the field at path or any field beneath it.
+func FieldManagers(object metav1.Object, path ...string) ([]string, error) {
+	elements := make([]interface{}, len(path))
+	for i := range path {
+		elements[i] = path[i]
+	}
+	target, err := fieldpath.MakePath(elements...)
+	if err != nil {
+		return nil, err
+	}
+
+	var managers []string
+	for _, mfe := range object.GetManagedFields() {
+		if mfe.Subresource != "" || mfe.FieldsV1 == nil {
+			continue
+		}
+
+		var paths fieldpath.Set
+		if err := paths.FromJSON(bytes.NewReader(mfe.FieldsV1.Raw)); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal FieldsV1 from JSON: %w", err)
+		}
+		owns := false
+		paths.Iterate(func(owned fieldpath.Path) {
+			owns = owns || (len(owned) >= len(target) && owned[:len(target)].Equals(target))
+		})
+		if owns && !slices.Contains(managers, mfe.Manager) {
+			managers = append(managers, mfe.Manager)
+		}
+	}
+	return managers, nil
+}
+
 // findManagedFields returns the server-side apply entry on object for
 // fieldManager and subresource. Blank subresource represents the main resource.
 func findManagedFields(object metav1.Object, fieldManager, subresource string) (metav1.ManagedFieldsEntry, bool) {
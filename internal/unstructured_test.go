@@ -172,6 +172,63 @@ metadata:
 	})
 }
 
+func TestFieldManagers(t *testing.T) {
+	var object unstructured.Unstructured
+	assert.NilError(t, yaml.Unmarshal([]byte(`
+metadata:
+  managedFields:
+  - fieldsType: FieldsV1
+    fieldsV1:
+      f:spec:
+        f:image: {}
+        f:patroni:
+          f:dynamicConfiguration:
+            f:postgresql:
+              f:pg_hba: {}
+    manager: helm
+    operation: Apply
+  - fieldsType: FieldsV1
+    fieldsV1:
+      f:spec:
+        f:patroni:
+          f:dynamicConfiguration:
+            f:postgresql:
+              f:parameters:
+                f:work_mem: {}
+    manager: kubectl-pgo
+    operation: Apply
+  - fieldsType: FieldsV1
+    fieldsV1:
+      f:spec:
+        f:image: {}
+    manager: kubectl-edit
+    operation: Update
+  - fieldsType: FieldsV1
+    fieldsV1:
+      f:spec:
+        f:image: {}
+    manager: somebody
+    operation: Update
+    subresource: status
+`), &object.Object))
+
+	managers, err := FieldManagers(&object, "spec", "image")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, managers, []string{"helm", "kubectl-edit"})
+
+	managers, err = FieldManagers(&object, "spec", "patroni", "dynamicConfiguration", "postgresql")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, managers, []string{"helm", "kubectl-pgo"})
+
+	managers, err = FieldManagers(&object, "spec", "patroni", "dynamicConfiguration", "postgresql", "pg_hba")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, managers, []string{"helm"})
+
+	managers, err = FieldManagers(&object, "spec", "shutdown")
+	assert.NilError(t, err)
+	assert.Assert(t, managers == nil)
+}
+
 func TestMergeStringMaps(t *testing.T) {
 	assert.DeepEqual(t, MergeStringMaps(), map[string]string{})
 
@@ -0,0 +1,188 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron expression in the standard five field format
+// that Kubernetes CronJobs accept: minute, hour, day of month, month, and day
+// of week.
+// - https://docs.k8s.io/concepts/workloads/controllers/cron-jobs/#schedule-syntax
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// anyDay is true when either the day of month or day of week is "*" or "?".
+	// Otherwise, a day matches when either field matches.
+	anyDay bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"", "jan", "feb", "mar", "apr", "may", "jun",
+		"jul", "aug", "sep", "oct", "nov", "dec",
+	}},
+	{name: "day of week", min: 0, max: 6, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}},
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCronSchedule parses a cron expression such as "0 1 * * 1-6" or "@daily".
+func ParseCronSchedule(expression string) (CronSchedule, error) {
+	var schedule CronSchedule
+
+	spec := strings.TrimSpace(expression)
+	if strings.HasPrefix(spec, "@") {
+		standard, ok := cronDescriptors[strings.ToLower(spec)]
+		if !ok {
+			return schedule, fmt.Errorf("unsupported descriptor %q", spec)
+		}
+		spec = standard
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return schedule, fmt.Errorf("expected %d fields, found %d", len(cronFields), len(fields))
+	}
+
+	bits := make([]uint64, len(fields))
+	for i, field := range fields {
+		var err error
+		if bits[i], err = cronFields[i].parse(field); err != nil {
+			return schedule, fmt.Errorf("invalid %s %q: %w", cronFields[i].name, field, err)
+		}
+	}
+
+	schedule.minute, schedule.hour, schedule.dom, schedule.month, schedule.dow =
+		bits[0], bits[1], bits[2], bits[3], bits[4]
+	schedule.anyDay = isCronWildcard(fields[2]) || isCronWildcard(fields[4])
+
+	return schedule, nil
+}
+
+func isCronWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parse returns the values of a comma-separated list of values, ranges, and
+// steps as bits.
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		value, step, hasStep := strings.Cut(item, "/")
+
+		var low, high int
+		var err error
+		switch {
+		case isCronWildcard(value):
+			low, high = f.min, f.max
+		case strings.Contains(value, "-"):
+			lowText, highText, _ := strings.Cut(value, "-")
+			if low, err = f.value(lowText); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(highText); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("range %s is backwards", value)
+			}
+		default:
+			if low, err = f.value(value); err != nil {
+				return 0, err
+			}
+			high = low
+			if hasStep {
+				high = f.max
+			}
+		}
+
+		increment := 1
+		if hasStep {
+			if increment, err = strconv.Atoi(step); err != nil || increment < 1 {
+				return 0, fmt.Errorf("step %q must be a positive number", step)
+			}
+		}
+
+		for v := low; v <= high; v += increment {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses one number or name of the field.
+func (f cronField) value(text string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(text, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", text)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d is not between %d and %d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (s CronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first time after t that matches the schedule, in the
+// location of t. It returns an error when nothing matches in the next five
+// years, such as "0 0 31 2 *".
+func (s CronSchedule) Next(t time.Time) (time.Time, error) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("schedule never runs")
+}
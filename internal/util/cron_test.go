@@ -0,0 +1,75 @@
+// Copyright 2021 - 2025 Crunchy Data Solutions, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	// Sunday, March 9, 2025 at 14:30 UTC
+	now := time.Date(2025, 3, 9, 14, 30, 15, 0, time.UTC)
+
+	for _, tt := range []struct {
+		Expression string
+		Next       string
+	}{
+		{Expression: "* * * * *", Next: "2025-03-09T14:31:00Z"},
+		{Expression: "0 1 * * 0", Next: "2025-03-16T01:00:00Z"},
+		{Expression: "0 1 * * 1-6", Next: "2025-03-10T01:00:00Z"},
+		{Expression: "*/20 * * * *", Next: "2025-03-09T14:40:00Z"},
+		{Expression: "5/20 14 * * *", Next: "2025-03-09T14:45:00Z"},
+		{Expression: "0 9-17/4 * * ?", Next: "2025-03-09T17:00:00Z"},
+		{Expression: "0 0 1,15 * *", Next: "2025-03-15T00:00:00Z"},
+		{Expression: "0 0 1 * MON", Next: "2025-03-10T00:00:00Z"}, // day of month OR day of week
+		{Expression: "30 2 * jan-mar sat", Next: "2025-03-15T02:30:00Z"},
+		{Expression: "0 0 29 2 *", Next: "2028-02-29T00:00:00Z"},
+		{Expression: "@daily", Next: "2025-03-10T00:00:00Z"},
+		{Expression: "@HOURLY", Next: "2025-03-09T15:00:00Z"},
+	} {
+		t.Run(tt.Expression, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.Expression)
+			assert.NilError(t, err)
+
+			next, err := schedule.Next(now)
+			assert.NilError(t, err)
+			assert.Equal(t, next.Format(time.RFC3339), tt.Next)
+		})
+	}
+
+	for _, tt := range []struct {
+		Expression string
+		Error      string
+	}{
+		{Expression: "", Error: "expected 5 fields, found 0"},
+		{Expression: "0 1 * *", Error: "expected 5 fields, found 4"},
+		{Expression: "0 0 1 1 * 2025", Error: "expected 5 fields, found 6"},
+		{Expression: "60 * * * *", Error: `invalid minute "60": 60 is not between 0 and 59`},
+		{Expression: "0 24 * * *", Error: `invalid hour "24"`},
+		{Expression: "0 0 0 * *", Error: `invalid day of month "0"`},
+		{Expression: "0 0 * 13 *", Error: `invalid month "13"`},
+		{Expression: "0 0 * * 7", Error: `invalid day of week "7"`},
+		{Expression: "0 0 * * funday", Error: `"funday" is not a number`},
+		{Expression: "5-1 * * * *", Error: "range 5-1 is backwards"},
+		{Expression: "*/0 * * * *", Error: `step "0" must be a positive number`},
+		{Expression: "@every 1h", Error: `unsupported descriptor "@every 1h"`},
+	} {
+		t.Run(tt.Expression, func(t *testing.T) {
+			_, err := ParseCronSchedule(tt.Expression)
+			assert.ErrorContains(t, err, tt.Error)
+		})
+	}
+
+	t.Run("Never", func(t *testing.T) {
+		schedule, err := ParseCronSchedule("0 0 31 2 *")
+		assert.NilError(t, err)
+
+		_, err = schedule.Next(now)
+		assert.ErrorContains(t, err, "never runs")
+	})
+}
@@ -80,3 +80,193 @@ func (e *pgbackrestFormat) Set(v string) error {
 func (e *pgbackrestFormat) Type() string {
 	return "string"
 }
+
+// Dry-run strategies for commands that create or modify objects
+// - https://docs.k8s.io/reference/using-api/api-concepts/#dry-run
+type dryRunStrategy string
+
+const (
+	DryRunNone   dryRunStrategy = "none"
+	DryRunClient dryRunStrategy = "client"
+	DryRunServer dryRunStrategy = "server"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (e *dryRunStrategy) String() string {
+	return string(*e)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (e *dryRunStrategy) Set(v string) error {
+	switch v {
+	case "none", "client", "server":
+		*e = dryRunStrategy(v)
+		return nil
+	default:
+		return errors.New(`must be one of "none", "client", "server"`)
+	}
+}
+
+// Type is only used in help text
+func (e *dryRunStrategy) Type() string {
+	return "string"
+}
+
+// Object output format options, matching those of 'kubectl get --output'.
+// NoObject indicates that no object should be printed.
+type objectFormat string
+
+const (
+	NoObject   objectFormat = ""
+	YAMLObject objectFormat = "yaml"
+	JSONObject objectFormat = "json"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (e *objectFormat) String() string {
+	return string(*e)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (e *objectFormat) Set(v string) error {
+	switch v {
+	case "yaml", "json":
+		*e = objectFormat(v)
+		return nil
+	default:
+		return errors.New(`must be one of "yaml", "json"`)
+	}
+}
+
+// Type is only used in help text
+func (e *objectFormat) Type() string {
+	return "string"
+}
+
+// Output format options for commands that print details as text or JSON.
+type outputFormat string
+
+const (
+	TextOutput outputFormat = "text"
+	JSONOutput outputFormat = "json"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (e *outputFormat) String() string {
+	return string(*e)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (e *outputFormat) Set(v string) error {
+	switch v {
+	case "text", "json":
+		*e = outputFormat(v)
+		return nil
+	default:
+		return errors.New(`must be one of "text", "json"`)
+	}
+}
+
+// Type is only used in help text
+func (e *outputFormat) Type() string {
+	return "string"
+}
+
+// Password types of PostgresCluster users
+// - https://access.crunchydata.com/documentation/postgres-operator/latest/references/crd
+type passwordType string
+
+const (
+	DefaultPassword      passwordType = ""
+	ASCIIPassword        passwordType = "ASCII"
+	AlphaNumericPassword passwordType = "AlphaNumeric"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (e *passwordType) String() string {
+	return string(*e)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (e *passwordType) Set(v string) error {
+	switch v {
+	case "ASCII", "AlphaNumeric":
+		*e = passwordType(v)
+		return nil
+	default:
+		return errors.New(`must be one of "ASCII", "AlphaNumeric"`)
+	}
+}
+
+// Type is only used in help text
+func (e *passwordType) Type() string {
+	return "string"
+}
+
+// Formats of the connection information of a PostgresCluster user
+type userFormat string
+
+const (
+	NoUserFormat   userFormat = ""
+	EnvUser        userFormat = "env"
+	PGPassUser     userFormat = "pgpass"
+	JDBCUser       userFormat = "jdbc"
+	DotEnvUser     userFormat = "dotenv"
+	JSONUser       userFormat = "json"
+	SecretYAMLUser userFormat = "secret-yaml"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (e *userFormat) String() string {
+	return string(*e)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (e *userFormat) Set(v string) error {
+	switch v {
+	case "env", "pgpass", "jdbc", "dotenv", "json", "secret-yaml":
+		*e = userFormat(v)
+		return nil
+	default:
+		return errors.New(`must be one of "env", "pgpass", "jdbc", "dotenv", "json", "secret-yaml"`)
+	}
+}
+
+// Type is only used in help text
+func (e *userFormat) Type() string {
+	return "string"
+}
+
+// Components of a PostgresCluster that write logs.
+// AllComponents indicates the logs of every component.
+type logComponent string
+
+const (
+	AllComponents       logComponent = ""
+	PostgresComponent   logComponent = "postgres"
+	PatroniComponent    logComponent = "patroni"
+	PGBackRestComponent logComponent = "pgbackrest"
+	PGBouncerComponent  logComponent = "pgbouncer"
+	ExporterComponent   logComponent = "exporter"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (e *logComponent) String() string {
+	return string(*e)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (e *logComponent) Set(v string) error {
+	switch v {
+	case "postgres", "patroni", "pgbackrest", "pgbouncer", "exporter":
+		*e = logComponent(v)
+		return nil
+	default:
+		return errors.New(`must be one of "postgres", "patroni", "pgbackrest", "pgbouncer", "exporter"`)
+	}
+}
+
+// Type is only used in help text
+func (e *logComponent) Type() string {
+	return "string"
+}
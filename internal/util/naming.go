@@ -16,12 +16,18 @@ const (
 	// LabelPgadmin is used to label PGAdmin objects.
 	LabelPgadmin = labelPrefix + "pgadmin"
 
+	// LabelInstanceSet is used to identify the instance set of Pods.
+	LabelInstanceSet = labelPrefix + "instance-set"
+
 	// LabelData is used to identify Pods and Volumes store Postgres data.
 	LabelData = labelPrefix + "data"
 
 	// LabelRole is used to identify object roles.
 	LabelRole = labelPrefix + "role"
 
+	// LabelPostgresUser is used to identify the Postgres user of a Secret.
+	LabelPostgresUser = labelPrefix + "pguser"
+
 	// LabelMonitoring is used to identify monitoring Pods.
 	// Older versions of PGO monitoring use the label 'postgres-operator-monitoring'.
 	LabelMonitoring = "app.kubernetes.io/name in (postgres-operator-monitoring,crunchy-monitoring)"
@@ -31,6 +37,27 @@ const (
 
 	// LabelPGBackRestDedicated is used to identify the Repo Host pod
 	LabelPGBackRestDedicated = labelPrefix + "pgbackrest-dedicated"
+
+	// LabelPGBackRestCronJob is used to identify the CronJobs of scheduled
+	// backups. Its value is the type of backup: full, diff, or incr.
+	LabelPGBackRestCronJob = labelPrefix + "pgbackrest-cronjob"
+
+	// LabelPGBackRestRepo is used to identify objects of a pgBackRest
+	// repository. Its value is the name of the repository, such as repo1.
+	LabelPGBackRestRepo = labelPrefix + "pgbackrest-repo"
+
+	// LabelPGUpgrade is used to identify objects of a PGUpgrade, such as its Job.
+	// Its value is the name of the PGUpgrade.
+	LabelPGUpgrade = labelPrefix + "pgupgrade"
+
+	// LabelProfile is used to identify ConfigMaps that contain PostgresCluster
+	// profiles. Its value is the name of the profile.
+	LabelProfile = labelPrefix + "pgo-profile"
+
+	// LabelTestRestore is used to identify the scratch PostgresClusters of
+	// "pgo backup test-restore". Its value is the name of the PostgresCluster
+	// that was restored.
+	LabelTestRestore = labelPrefix + "pgo-test-restore"
 )
 
 const (
@@ -56,6 +83,9 @@ const (
 
 	// RolePostgresUser is the LabelRole applied to PostgreSQL user secrets.
 	RolePostgresUser = "pguser"
+
+	// RolePGBouncer is the LabelRole applied to PgBouncer objects.
+	RolePGBouncer = "pgbouncer"
 )
 
 const (
@@ -66,6 +96,13 @@ const (
 	ContainerDatabase = "database"
 
 	ContainerPGBackrest = "pgbackrest"
+
+	// ContainerPGBouncer is the name of the container running PgBouncer.
+	ContainerPGBouncer = "pgbouncer"
+
+	// ContainerExporter is the name of the container running the Postgres
+	// metrics exporter.
+	ContainerExporter = "exporter"
 )
 
 // DBInstanceLabels provides labels for a PostgreSQL cluster primary or replica instance
@@ -93,8 +130,20 @@ func PostgresUserSecretLabels(clusterName string) string {
 		LabelRole + "=" + RolePostgresUser
 }
 
+// PGBouncerLabels provides labels for the PgBouncer Pods of a PostgreSQL cluster
+func PGBouncerLabels(clusterName string) string {
+	return LabelCluster + "=" + clusterName + "," +
+		LabelRole + "=" + RolePGBouncer
+}
+
 // AllowUpgradeAnnotation is the annotation key to allow of PostgresCluster
 // to upgrade. Its value is the name of the PGUpgrade object.
 func AllowUpgradeAnnotation() string {
 	return labelPrefix + "allow-upgrade"
 }
+
+// RestartAnnotation is the annotation key that the CLI sets on the Pods of
+// a PostgresCluster to restart them. Its value is the time of the request.
+func RestartAnnotation() string {
+	return labelPrefix + "restart"
+}
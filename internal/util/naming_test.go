@@ -17,3 +17,10 @@ func TestPrimaryInstanceLabels(t *testing.T) {
 			"postgres-operator.crunchydata.com/data=postgres,"+
 			"postgres-operator.crunchydata.com/role=master")
 }
+
+func TestPGBouncerLabels(t *testing.T) {
+
+	assert.Equal(t, PGBouncerLabels("testcluster1"),
+		"postgres-operator.crunchydata.com/cluster=testcluster1,"+
+			"postgres-operator.crunchydata.com/role=pgbouncer")
+}